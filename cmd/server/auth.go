@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"calculator/internal/auth"
+	"calculator/internal/config"
+	"calculator/internal/tracing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// serverAuth проверяет учётные данные запросов. nil означает, что
+// аутентификация выключена.
+type serverAuth struct {
+	authn auth.Authenticator
+}
+
+func newServerAuth(cfg config.Auth) (*serverAuth, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	var chain auth.Chain
+	if cfg.APIKeysFile != "" {
+		keys, err := auth.LoadAPIKeys(cfg.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, keys)
+	}
+	jwtCfg := auth.JWTConfig{Issuer: cfg.JWT.Issuer, Audience: cfg.JWT.Audience}
+	if cfg.JWT.HS256SecretFile != "" {
+		secret, err := auth.LoadHS256Secret(cfg.JWT.HS256SecretFile)
+		if err != nil {
+			return nil, err
+		}
+		jwtCfg.HS256Secret = secret
+	}
+	if cfg.JWT.RS256PublicKeyFile != "" {
+		key, err := auth.LoadRS256PublicKey(cfg.JWT.RS256PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		jwtCfg.RS256PublicKey = key
+	}
+	if jwtCfg.HS256Secret != nil || jwtCfg.RS256PublicKey != nil {
+		chain = append(chain, auth.NewJWT(jwtCfg))
+	}
+	return &serverAuth{authn: chain}, nil
+}
+
+// publicHTTP — маршруты без аутентификации: пробы балансировщика и сбор
+// метрик.
+var publicHTTP = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// publicRPC сообщает, что метод доступен без аутентификации.
+func publicRPC(method string) bool {
+	return strings.HasPrefix(method, "/grpc.health.v1.Health/")
+}
+
+// authenticate проверяет значение заголовка Authorization и добавляет
+// личность клиента в контекст. Без заголовка клиента представляет
+// проверенный сертификат mTLS, если он есть.
+func (a *serverAuth) authenticate(ctx context.Context, header string, cert *x509.Certificate) (context.Context, error) {
+	var id auth.Identity
+	var err error
+	if header == "" && cert != nil {
+		id = auth.CertificateIdentity(cert)
+	} else {
+		var cred auth.Credentials
+		cred, err = auth.ParseAuthorization(header)
+		if err == nil {
+			id, err = a.authn.Authenticate(ctx, cred)
+		}
+	}
+	if err != nil {
+		slog.InfoContext(ctx, "authentication failed", "error", err)
+		return ctx, err
+	}
+	tracing.SpanFromContext(ctx).SetAttribute("auth.subject", id.Subject)
+	return auth.WithIdentity(ctx, id), nil
+}
+
+func (a *serverAuth) authHTTP(next http.Handler) http.Handler {
+	if a == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && publicHTTP[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, err := a.authenticate(r.Context(), r.Header.Get("Authorization"), verifiedClientCert(r.TLS))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="calculator"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (a *serverAuth) authRPC(ctx context.Context) (context.Context, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			header = v[0]
+		}
+	}
+	ctx, err := a.authenticate(ctx, header, rpcClientCert(ctx))
+	if err != nil {
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return ctx, nil
+}
+
+func (a *serverAuth) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if a == nil || publicRPC(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	ctx, err := a.authRPC(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *serverAuth) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if a == nil || publicRPC(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	ctx, err := a.authRPC(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
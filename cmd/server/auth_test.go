@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"calculator/internal/auth"
+)
+
+const testAPIKey = "test-key"
+
+func newTestAuth(t *testing.T) *serverAuth {
+	t.Helper()
+	sum := sha256.Sum256([]byte(testAPIKey))
+	keys, err := auth.NewAPIKeys([]auth.APIKey{{ID: "ci", SHA256: hex.EncodeToString(sum[:])}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &serverAuth{authn: keys}
+}
+
+func TestAuthHTTP(t *testing.T) {
+	c := newTestComponents(t)
+	c.auth = newTestAuth(t)
+	useChain(t, c, "auth")
+	url := startHTTP(t, c)
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   int
+	}{
+		{name: "no credentials", path: "/sessions/x", want: http.StatusUnauthorized},
+		{name: "unknown key", path: "/sessions/x", header: "ApiKey nope", want: http.StatusUnauthorized},
+		{name: "malformed header", path: "/sessions/x", header: "ApiKey", want: http.StatusUnauthorized},
+		{name: "valid key", path: "/sessions/x", header: "ApiKey " + testAPIKey, want: http.StatusNotFound},
+		{name: "public probe", path: "/healthz", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, url+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") == "" {
+				t.Fatal("missing WWW-Authenticate header")
+			}
+		})
+	}
+}
+
+// Журнал и метрики снаружи auth видят маршрут, хотя auth передаёт
+// дальше копию запроса с новым контекстом.
+func TestRouteVisibleOutsideAuth(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	c := newTestComponents(t)
+	c.auth = newTestAuth(t)
+	useChain(t, c, "logging", "metrics", "auth")
+	url := startHTTP(t, c)
+
+	req, err := http.NewRequest(http.MethodGet, url+"/sessions/x", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "ApiKey "+testAPIKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if !strings.Contains(logs.String(), `"route":"GET /sessions/{id}"`) {
+		t.Errorf("log does not contain the route: %s", logs.String())
+	}
+	rec := httptest.NewRecorder()
+	c.metrics.registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `method="GET /sessions/{id}"`) {
+		t.Errorf("metrics do not contain the route:\n%s", rec.Body.String())
+	}
+}
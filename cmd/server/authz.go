@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"calculator/internal/auth"
+	"calculator/internal/config"
+	"calculator/internal/logging"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// operationPermissions — права, которых требуют операции: HTTP по
+// шаблону маршрута и gRPC по полному имени метода. Операция без записи
+// запрещена всем, кроме публичных проб и метрик.
+var operationPermissions = map[string]auth.Permission{
+	"GET /api/descriptor": auth.PermAdmin,
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo":      auth.PermAdmin,
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": auth.PermAdmin,
+
+	"/calculate": auth.PermCalculate,
+	"/calculator.CalculatorService/Calculate":       auth.PermCalculate,
+	"/calculator.CalculatorService/CalculateStream": auth.PermCalculate,
+
+	"POST /sessions":                                 auth.PermSessions,
+	"GET /sessions/{id}":                             auth.PermSessions,
+	"DELETE /sessions/{id}":                          auth.PermSessions,
+	"GET /sessions/{id}/vars":                        auth.PermSessions,
+	"PATCH /sessions/{id}/vars":                      auth.PermSessions,
+	"POST /sessions/{id}/calculate":                  auth.PermSessions,
+	"/calculator.CalculatorService/CreateSession":    auth.PermSessions,
+	"/calculator.CalculatorService/GetSession":       auth.PermSessions,
+	"/calculator.CalculatorService/DeleteSession":    auth.PermSessions,
+	"/calculator.CalculatorService/ListVariables":    auth.PermSessions,
+	"/calculator.CalculatorService/SessionCalculate": auth.PermSessions,
+	"/calculator.CalculatorService/UpdateInputs":     auth.PermSessions,
+
+	"GET /programs":                              auth.PermProgramsRead,
+	"GET /programs/{name}":                       auth.PermProgramsRead,
+	"GET /programs/{name}/diff":                  auth.PermProgramsRead,
+	"/calculator.CalculatorService/ListPrograms": auth.PermProgramsRead,
+	"/calculator.CalculatorService/GetProgram":   auth.PermProgramsRead,
+	"/calculator.CalculatorService/DiffProgram":  auth.PermProgramsRead,
+
+	"PUT /programs/{name}":                        auth.PermProgramsWrite,
+	"DELETE /programs/{name}":                     auth.PermProgramsWrite,
+	"/calculator.CalculatorService/CreateProgram": auth.PermProgramsWrite,
+	"/calculator.CalculatorService/DeleteProgram": auth.PermProgramsWrite,
+
+	"POST /programs/{name}/execute":                auth.PermProgramsExecute,
+	"/calculator.CalculatorService/ExecuteProgram": auth.PermProgramsExecute,
+
+	"POST /batch":           auth.PermBatch,
+	"POST /calculate:batch": auth.PermBatch,
+	"/calculator.CalculatorService/BatchEvaluate":  auth.PermBatch,
+	"/calculator.CalculatorService/BatchCalculate": auth.PermBatch,
+
+	"POST /jobs":                                 auth.PermJobs,
+	"GET /jobs/{id}":                             auth.PermJobs,
+	"DELETE /jobs/{id}":                          auth.PermJobs,
+	"POST /jobs/{id}/redeliver":                  auth.PermJobs,
+	"/calculator.CalculatorService/SubmitJob":    auth.PermJobs,
+	"/calculator.CalculatorService/GetJob":       auth.PermJobs,
+	"/calculator.CalculatorService/CancelJob":    auth.PermJobs,
+	"/calculator.CalculatorService/RedeliverJob": auth.PermJobs,
+}
+
+// serverAuthz проверяет права клиента по политике. nil означает, что
+// политика не задана и прошедшему аутентификацию клиенту доступно всё.
+type serverAuthz struct {
+	policy *auth.Policy
+	// routes нужен, чтобы узнать шаблон маршрута до маршрутизации.
+	routes *http.ServeMux
+	audit  *slog.Logger
+	closer io.Closer
+}
+
+func newServerAuthz(cfg config.Auth, level slog.Level, routes *http.ServeMux) (*serverAuthz, error) {
+	if cfg.PolicyFile == "" {
+		return nil, nil
+	}
+	policy, err := auth.LoadPolicy(cfg.PolicyFile)
+	if err != nil {
+		return nil, err
+	}
+	z := &serverAuthz{policy: policy, routes: routes, audit: slog.Default()}
+	if cfg.AuditFile != "" {
+		f, err := os.OpenFile(cfg.AuditFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("open audit log: %w", err)
+		}
+		z.audit, z.closer = logging.New(f, level), f
+	}
+	z.audit = z.audit.With("log", "audit")
+	return z, nil
+}
+
+func (z *serverAuthz) Close() error {
+	if z == nil || z.closer == nil {
+		return nil
+	}
+	return z.closer.Close()
+}
+
+// authorize проверяет право на операцию и записывает отказ в журнал
+// аудита.
+func (z *serverAuthz) authorize(ctx context.Context, operation, remote string) error {
+	id, ok := auth.FromContext(ctx)
+	perm, known := operationPermissions[operation]
+	var err error
+	switch {
+	case !ok:
+		// Аутентификация выключена или стоит в цепочке позже.
+		err = fmt.Errorf("%w: caller is not authenticated", auth.ErrPermissionDenied)
+	case !known:
+		err = fmt.Errorf("%w: no permission is defined for %s", auth.ErrPermissionDenied, operation)
+	default:
+		err = z.policy.Authorize(id, perm)
+	}
+	if err != nil {
+		z.audit.LogAttrs(ctx, slog.LevelWarn, "permission denied",
+			slog.String("subject", id.Subject),
+			slog.String("auth_method", id.Method),
+			slog.Any("roles", z.policy.Roles(id)),
+			slog.String("permission", string(perm)),
+			slog.String("operation", operation),
+			slog.String("remote_addr", remote),
+		)
+	}
+	return err
+}
+
+func (z *serverAuthz) authorizeHTTP(next http.Handler) http.Handler {
+	if z == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && publicHTTP[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// Запросы без маршрута проходят дальше и получают 404 или 405.
+		if _, pattern := z.routes.Handler(r); pattern != "" {
+			if err := z.authorize(r.Context(), pattern, r.RemoteAddr); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (z *serverAuthz) authorizeRPC(ctx context.Context, method string) error {
+	if publicRPC(method) {
+		return nil
+	}
+	if err := z.authorize(ctx, method, peerAddr(ctx)); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+// peerAddr возвращает адрес клиента gRPC вызова.
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+func (z *serverAuthz) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if z == nil {
+		return handler(ctx, req)
+	}
+	if err := z.authorizeRPC(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (z *serverAuthz) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if z == nil {
+		return handler(srv, ss)
+	}
+	if err := z.authorizeRPC(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"calculator/internal/auth"
+	"calculator/internal/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// newTestAuthz включает auth и authz: ключ "operator-key" даёт роль
+// operator, "admin-key" — роль admin.
+func newTestAuthz(t *testing.T, c *components, audit *bytes.Buffer) {
+	t.Helper()
+	var keys []auth.APIKey
+	for _, role := range []string{"operator", "admin"} {
+		sum := sha256.Sum256([]byte(role + "-key"))
+		keys = append(keys, auth.APIKey{ID: role, SHA256: hex.EncodeToString(sum[:]), Roles: []string{role}})
+	}
+	authn, err := auth.NewAPIKeys(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	policy := "roles:\n  admin: [\"*\"]\n  operator: [calculate, sessions]\n"
+	if err := os.WriteFile(path, []byte(policy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := auth.LoadPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.auth = &serverAuth{authn: authn}
+	c.authz = &serverAuthz{policy: p, routes: c.routes, audit: slog.New(slog.NewJSONHandler(audit, nil))}
+	useChain(t, c, "auth", "authz")
+}
+
+func TestAuthorizeHTTP(t *testing.T) {
+	var audit bytes.Buffer
+	c := newTestComponents(t)
+	newTestAuthz(t, c, &audit)
+	url := startHTTP(t, c)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		want   int
+	}{
+		{name: "allowed", method: http.MethodPost, path: "/sessions", key: "operator-key", want: http.StatusCreated},
+		{name: "denied", method: http.MethodGet, path: "/programs", key: "operator-key", want: http.StatusForbidden},
+		{name: "denied batch", method: http.MethodPost, path: "/batch", key: "operator-key", want: http.StatusForbidden},
+		{name: "admin", method: http.MethodGet, path: "/programs", key: "admin-key", want: http.StatusOK},
+		{name: "unknown route", method: http.MethodGet, path: "/nope", key: "operator-key", want: http.StatusNotFound},
+		{name: "public probe", method: http.MethodGet, path: "/healthz", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, url+tt.path, strings.NewReader("{}"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.key != "" {
+				req.Header.Set("Authorization", "ApiKey "+tt.key)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	// Каждый отказ записан в журнал аудита.
+	entries := strings.Count(audit.String(), `"msg":"permission denied"`)
+	if entries != 2 || !strings.Contains(audit.String(), `"operation":"POST /batch"`) {
+		t.Fatalf("audit log:\n%s", audit.String())
+	}
+}
+
+func TestAuthorizeRPC(t *testing.T) {
+	var audit bytes.Buffer
+	c := newTestComponents(t)
+	newTestAuthz(t, c, &audit)
+	client := startGRPC(t, c)
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "ApiKey "+key)
+	}
+
+	if _, err := client.CreateSession(withKey("operator-key"), &pb.CreateSessionRequest{}); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	if _, err := client.ListPrograms(withKey("operator-key"), &pb.ListProgramsRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("ListPrograms: got %v, want PermissionDenied", err)
+	}
+	if _, err := client.ListPrograms(withKey("admin-key"), &pb.ListProgramsRequest{}); err != nil {
+		t.Fatalf("ListPrograms as admin: %v", err)
+	}
+	stream, err := client.BatchEvaluate(withKey("operator-key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("BatchEvaluate: got %v, want PermissionDenied", err)
+	}
+}
+
+// Методу без записи в operationPermissions доступ запрещён всем, поэтому
+// каждый метод сервиса должен в ней быть.
+func TestOperationPermissionsCoverService(t *testing.T) {
+	desc := pb.CalculatorService_ServiceDesc
+	var methods []string
+	for _, m := range desc.Methods {
+		methods = append(methods, m.MethodName)
+	}
+	for _, s := range desc.Streams {
+		methods = append(methods, s.StreamName)
+	}
+	for _, name := range methods {
+		full := "/" + desc.ServiceName + "/" + name
+		if _, ok := operationPermissions[full]; !ok {
+			t.Errorf("no permission is defined for %s", full)
+		}
+	}
+}
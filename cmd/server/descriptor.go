@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+
+	"calculator/internal/pb"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// descriptorSet возвращает набор дескрипторов calculator.proto и всех
+// его зависимостей — ту схему, с которой собран сервер. Зависимости
+// идут раньше зависящих от них файлов, как того требует protoc.
+func descriptorSet() *descriptorpb.FileDescriptorSet {
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := make(map[string]bool)
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+	add(pb.File_proto_calculator_proto)
+	return set
+}
+
+// descriptor отдаёт набор дескрипторов в двоичном виде
+// (application/x-protobuf) или, если клиент просит JSON, в protojson.
+func (s *httpServer) descriptor(w http.ResponseWriter, r *http.Request) {
+	set := descriptorSet()
+	if r.URL.Query().Get("format") == "json" || r.Header.Get("Accept") == "application/json" {
+		payload, err := protojson.MarshalOptions{Indent: "  "}.Marshal(set)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(payload)
+		return
+	}
+
+	payload, err := proto.Marshal(set)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Header().Set("Content-Disposition", `attachment; filename="calculator.protoset"`)
+	w.Write(payload)
+}
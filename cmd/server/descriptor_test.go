@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"calculator/internal/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// newReflectionComponents собирает компоненты с открытой схемой сервиса
+// или без неё.
+func newReflectionComponents(t *testing.T, enabled bool) *components {
+	t.Helper()
+	c := newTestComponents(t)
+	c.reflection = enabled
+	c.routes = (&httpServer{components: c}).routes()
+	return c
+}
+
+func getDescriptor(t *testing.T, url, query string) (*http.Response, []byte) {
+	t.Helper()
+	resp, err := http.Get(url + "/api/descriptor" + query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, body
+}
+
+// Набор дескрипторов разбирается как FileDescriptorSet и описывает
+// сервис калькулятора со всеми зависимостями.
+func checkDescriptorSet(t *testing.T, set *descriptorpb.FileDescriptorSet) {
+	t.Helper()
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		t.Fatalf("descriptor set does not resolve: %v", err)
+	}
+	desc, err := files.FindDescriptorByName("calculator.CalculatorService")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		t.Fatalf("calculator.CalculatorService is %T, want a service", desc)
+	}
+	if svc.Methods().ByName("CalculateStream") == nil {
+		t.Fatal("service has no CalculateStream method")
+	}
+}
+
+func TestDescriptorEndpoint(t *testing.T) {
+	url := startHTTP(t, newReflectionComponents(t, true))
+
+	resp, body := getDescriptor(t, url, "")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/x-protobuf" {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(body, &set); err != nil {
+		t.Fatal(err)
+	}
+	checkDescriptorSet(t, &set)
+
+	resp, body = getDescriptor(t, url, "?format=json")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("json: status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	var fromJSON descriptorpb.FileDescriptorSet
+	if err := protojson.Unmarshal(body, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(&set, &fromJSON) {
+		t.Fatal("JSON and binary descriptor sets differ")
+	}
+}
+
+func TestDescriptorEndpointDisabled(t *testing.T) {
+	url := startHTTP(t, newReflectionComponents(t, false))
+	if resp, _ := getDescriptor(t, url, ""); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("status %d, want 404", resp.StatusCode)
+	}
+}
+
+// listServices запрашивает у сервера список сервисов через reflection.
+func listServices(t *testing.T, addr string) ([]string, error) {
+	t.Helper()
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}); err != nil {
+		return nil, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, svc := range resp.GetListServicesResponse().GetService() {
+		names = append(names, svc.Name)
+	}
+	return names, nil
+}
+
+func TestGRPCReflection(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		c := newReflectionComponents(t, enabled)
+		tr, err := newGRPCTransport(c, config.GRPC{Listener: config.Listener{Addr: "127.0.0.1:0"}}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		go tr.serve()
+		t.Cleanup(tr.stop)
+
+		names, err := listServices(t, tr.ln.Addr().String())
+		if !enabled {
+			if status.Code(err) != codes.Unimplemented {
+				t.Fatalf("reflection disabled: got %v, %v, want Unimplemented", names, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, name := range names {
+			found = found || name == "calculator.CalculatorService"
+		}
+		if !found {
+			t.Fatalf("services = %v, want calculator.CalculatorService", names)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"calculator/internal/auth"
+	"calculator/internal/batch"
+	"calculator/internal/jobs"
+	"calculator/internal/programs"
+	"calculator/internal/ratelimit"
+	"calculator/internal/service"
+	"calculator/internal/session"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var errBadRequest = errors.New("bad request")
+
+// badRequest помечает ошибку разбора запроса клиента.
+func badRequest(err error) error {
+	return fmt.Errorf("%w: %v", errBadRequest, err)
+}
+
+// writeBodyError отвечает на ошибку разбора тела запроса.
+func writeBodyError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, "Invalid request body", http.StatusBadRequest)
+}
+
+// setRetryAfter добавляет Retry-After к ответу на превышение ограничения
+// или перегрузку.
+func setRetryAfter(h http.Header, err error) {
+	var limited *ratelimit.LimitError
+	switch {
+	case errors.As(err, &limited):
+		h.Set("Retry-After", strconv.Itoa(limited.RetryAfterSeconds()))
+	case errors.Is(err, service.ErrOverloaded):
+		h.Set("Retry-After", "1")
+	}
+}
+
+// limitedStatus — ResourceExhausted с RetryInfo, по которому клиент
+// узнаёт, когда повторить вызов.
+func limitedStatus(err error) error {
+	st := status.New(codes.ResourceExhausted, err.Error())
+	var limited *ratelimit.LimitError
+	if errors.As(err, &limited) {
+		retry := &errdetails.RetryInfo{RetryDelay: durationpb.New(time.Duration(limited.RetryAfterSeconds()) * time.Second)}
+		if detailed, derr := st.WithDetails(retry); derr == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
+
+func httpStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ratelimit.ErrLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, service.ErrOverloaded):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, service.ErrLimitExceeded):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, auth.ErrPermissionDenied):
+		return http.StatusForbidden
+	case errors.Is(err, session.ErrNotFound), errors.Is(err, session.ErrVariableNotFound),
+		errors.Is(err, programs.ErrNotFound), errors.Is(err, jobs.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, jobs.ErrQueueFull), errors.Is(err, jobs.ErrClosed):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, jobs.ErrFinished), errors.Is(err, jobs.ErrNoCallback),
+		errors.Is(err, jobs.ErrNotFinished), errors.Is(err, jobs.ErrDelivering):
+		return http.StatusConflict
+	case errors.Is(err, session.ErrTooManyVariables):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrAlreadyAssigned):
+		return http.StatusConflict
+	case errors.Is(err, session.ErrNotInput), errors.Is(err, service.ErrInvalidInputs),
+		errors.Is(err, service.ErrInvalidProgram), errors.Is(err, service.ErrUndefinedVariable),
+		errors.Is(err, programs.ErrInvalidName), errors.Is(err, programs.ErrInvalidVersion),
+		errors.Is(err, jobs.ErrInvalidCallback), errors.Is(err, batch.ErrInvalidBatch),
+		errors.Is(err, errBadRequest):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func grpcError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, ratelimit.ErrLimited):
+		return limitedStatus(err)
+	case errors.Is(err, service.ErrOverloaded):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, service.ErrLimitExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, auth.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, session.ErrNotFound), errors.Is(err, session.ErrVariableNotFound),
+		errors.Is(err, programs.ErrNotFound), errors.Is(err, jobs.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, jobs.ErrQueueFull):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, jobs.ErrClosed):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, jobs.ErrFinished), errors.Is(err, jobs.ErrNoCallback),
+		errors.Is(err, jobs.ErrNotFinished), errors.Is(err, jobs.ErrDelivering):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, session.ErrTooManyVariables):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, service.ErrAlreadyAssigned):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, session.ErrNotInput), errors.Is(err, service.ErrInvalidInputs),
+		errors.Is(err, service.ErrInvalidProgram), errors.Is(err, service.ErrUndefinedVariable),
+		errors.Is(err, programs.ErrInvalidName), errors.Is(err, programs.ErrInvalidVersion),
+		errors.Is(err, jobs.ErrInvalidCallback), errors.Is(err, batch.ErrInvalidBatch),
+		errors.Is(err, errBadRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"calculator/internal/batch"
+	"calculator/internal/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type batchStream = grpc.BidiStreamingServer[pb.BatchRequest, pb.BatchResponse]
+
+// BatchEvaluate принимает программу и поток строк входов. Строки
+// вычисляются по мере поступления, и результат каждой отправляется
+// клиенту сразу, поэтому память не зависит от числа строк.
+func (s *grpcServer) BatchEvaluate(stream batchStream) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	program := first.GetProgram()
+	if program == nil {
+		return status.Error(codes.InvalidArgument, "first batch message must be a program")
+	}
+
+	instructions := toInstructions(program.Instructions)
+	if program.Name != "" {
+		stored, err := s.programs.Get(program.Name, int(program.Version))
+		if err != nil {
+			return grpcError(err)
+		}
+		instructions = stored.Instructions
+	}
+
+	summary, err := s.batch.Run(stream.Context(), instructions, &streamRows{stream: stream}, func(res batch.Result) error {
+		return stream.Send(&pb.BatchResponse{Frame: &pb.BatchResponse_Result{Result: &pb.BatchRowResult{
+			Row:    int32(res.Row),
+			Inputs: res.Inputs,
+			Items:  toResultItems(res.Items),
+			Error:  res.Error,
+		}}})
+	})
+	if err != nil {
+		return grpcError(err)
+	}
+
+	return stream.Send(&pb.BatchResponse{Frame: &pb.BatchResponse_Summary{Summary: &pb.BatchSummary{
+		Rows:      int32(summary.Rows),
+		Succeeded: int32(summary.Succeeded),
+		Failed:    int32(summary.Failed),
+	}}})
+}
+
+// streamRows читает строки пакета из входящего потока gRPC.
+type streamRows struct {
+	stream batchStream
+	index  int
+}
+
+func (r *streamRows) Next() (batch.Row, error) {
+	msg, err := r.stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return batch.Row{}, io.EOF
+	}
+	if err != nil {
+		return batch.Row{}, err
+	}
+	r.index++
+	row := batch.Row{Index: r.index}
+	if msg.GetRow() == nil {
+		row.Err = errors.New("expected a row message")
+		return row, nil
+	}
+	row.Inputs = msg.GetRow().Inputs
+	return row, nil
+}
+
+// BatchCalculate выполняет независимые программы в одном вызове.
+func (s *grpcServer) BatchCalculate(ctx context.Context, req *pb.BatchCalculateRequest) (*pb.BatchCalculateResponse, error) {
+	if req.TimeoutMs < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "timeout_ms must not be negative: %d", req.TimeoutMs)
+	}
+	if req.TimeoutMs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutMs)*time.Millisecond)
+		defer cancel()
+	}
+
+	programs := make([]batch.Program, 0, len(req.Programs))
+	for _, p := range req.Programs {
+		programs = append(programs, batch.Program{
+			ID:           p.Id,
+			Instructions: toInstructions(p.Instructions),
+			Inputs:       p.Inputs,
+		})
+	}
+	results, err := s.batch.RunPrograms(ctx, programs)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &pb.BatchCalculateResponse{Results: make(map[string]*pb.BatchCalculateResult, len(results))}
+	for id, res := range results {
+		resp.Results[id] = &pb.BatchCalculateResult{
+			Items:  toResultItems(res.Items),
+			Inputs: res.Inputs,
+			Error:  res.Error,
+		}
+		if res.Error != "" {
+			resp.Failed++
+		} else {
+			resp.Succeeded++
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"calculator/internal/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Результат каждой строки приходит отдельным сообщением до того, как
+// клиент закончит передавать строки.
+func TestBatchEvaluateStreamsResults(t *testing.T) {
+	client := startGRPC(t, newTestComponents(t))
+	stream, err := client.BatchEvaluate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	program := &pb.BatchProgram{Instructions: []*pb.Instruction{
+		{Type: "input", Var: "n"},
+		{Type: "calc", Op: "*", Var: "sq", LeftType: &pb.Instruction_LeftVar{LeftVar: "n"}, RightType: &pb.Instruction_RightVar{RightVar: "n"}},
+		{Type: "print", Var: "sq"},
+	}}
+	if err := stream.Send(&pb.BatchRequest{Frame: &pb.BatchRequest_Program{Program: program}}); err != nil {
+		t.Fatal(err)
+	}
+
+	const rows = 5
+	for i := int64(1); i <= rows; i++ {
+		row := &pb.BatchRow{Inputs: map[string]int64{"n": i}}
+		if i == 3 {
+			row.Inputs["extra"] = 1
+		}
+		if err := stream.Send(&pb.BatchRequest{Frame: &pb.BatchRequest_Row{Row: row}}); err != nil {
+			t.Fatal(err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := resp.GetResult()
+		if res == nil || res.Row != int32(i) {
+			t.Fatalf("row %d: got %v", i, resp)
+		}
+		if i == 3 {
+			if res.Error == "" {
+				t.Fatalf("row 3: expected an error, got %v", res)
+			}
+			continue
+		}
+		if res.Error != "" || len(res.Items) != 1 || res.Items[0].Value != i*i {
+			t.Fatalf("row %d: got %v", i, res)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := resp.GetSummary()
+	if summary == nil || summary.Rows != rows || summary.Succeeded != rows-1 || summary.Failed != 1 {
+		t.Fatalf("summary = %v", resp)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("after summary: got %v, want EOF", err)
+	}
+}
+
+func TestBatchEvaluateRequiresProgram(t *testing.T) {
+	client := startGRPC(t, newTestComponents(t))
+	stream, err := client.BatchEvaluate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&pb.BatchRequest{Frame: &pb.BatchRequest_Row{Row: &pb.BatchRow{}}}); err != nil {
+		t.Fatal(err)
+	}
+	stream.CloseSend()
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("got %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+
+	"calculator/internal/jobs"
+	"calculator/internal/pb"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var jobStates = map[jobs.State]pb.JobState{
+	jobs.StateQueued:    pb.JobState_JOB_STATE_QUEUED,
+	jobs.StateRunning:   pb.JobState_JOB_STATE_RUNNING,
+	jobs.StateSucceeded: pb.JobState_JOB_STATE_SUCCEEDED,
+	jobs.StateFailed:    pb.JobState_JOB_STATE_FAILED,
+	jobs.StateCanceled:  pb.JobState_JOB_STATE_CANCELED,
+}
+
+func (s *grpcServer) SubmitJob(ctx context.Context, req *pb.SubmitJobRequest) (*pb.Job, error) {
+	var callback *jobs.Callback
+	if req.Callback != nil {
+		callback = &jobs.Callback{URL: req.Callback.Url, Secret: req.Callback.Secret}
+	}
+	job, err := s.jobs.Submit(ctx, toInstructions(req.Instructions), req.Inputs, callback)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return toJob(job), nil
+}
+
+func (s *grpcServer) GetJob(ctx context.Context, req *pb.GetJobRequest) (*pb.Job, error) {
+	job, err := s.jobs.Get(req.Id)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return toJob(job), nil
+}
+
+func (s *grpcServer) CancelJob(ctx context.Context, req *pb.CancelJobRequest) (*pb.Job, error) {
+	job, err := s.jobs.Cancel(req.Id)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return toJob(job), nil
+}
+
+func (s *grpcServer) RedeliverJob(ctx context.Context, req *pb.RedeliverJobRequest) (*pb.Job, error) {
+	job, err := s.jobs.Redeliver(req.Id)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return toJob(job), nil
+}
+
+func toJob(job jobs.Info) *pb.Job {
+	out := &pb.Job{
+		Id:        job.ID,
+		State:     jobStates[job.State],
+		Total:     int32(job.Progress.Total),
+		Completed: int32(job.Progress.Completed),
+		Items:     toResultItems(job.Items),
+		Inputs:    job.Inputs,
+		Error:     job.Error,
+		CreatedAt: timestamppb.New(job.CreatedAt),
+	}
+	if job.StartedAt != nil {
+		out.StartedAt = timestamppb.New(*job.StartedAt)
+	}
+	if job.FinishedAt != nil {
+		out.FinishedAt = timestamppb.New(*job.FinishedAt)
+	}
+	if job.Webhook != nil {
+		out.Webhook = &pb.JobWebhook{
+			Url:        job.Webhook.URL,
+			Delivered:  job.Webhook.Delivered,
+			Delivering: job.Webhook.Delivering,
+		}
+		for _, a := range job.Webhook.Attempts {
+			out.Webhook.Attempts = append(out.Webhook.Attempts, &pb.DeliveryAttempt{
+				Delivery:   int32(a.Delivery),
+				Attempt:    int32(a.Attempt),
+				Time:       timestamppb.New(a.Time),
+				StatusCode: int32(a.StatusCode),
+				Error:      a.Error,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+
+	"calculator/internal/pb"
+	"calculator/internal/programs"
+	"calculator/internal/service"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *grpcServer) CreateProgram(ctx context.Context, req *pb.CreateProgramRequest) (*pb.ProgramVersion, error) {
+	version, err := s.programs.Put(req.Name, toInstructions(req.Instructions))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return toProgramVersion(version), nil
+}
+
+func (s *grpcServer) ListPrograms(ctx context.Context, req *pb.ListProgramsRequest) (*pb.ListProgramsResponse, error) {
+	summaries, err := s.programs.List()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &pb.ListProgramsResponse{Programs: make([]*pb.ProgramSummary, 0, len(summaries))}
+	for _, sum := range summaries {
+		versions := make([]int32, 0, len(sum.Versions))
+		for _, v := range sum.Versions {
+			versions = append(versions, int32(v))
+		}
+		resp.Programs = append(resp.Programs, &pb.ProgramSummary{
+			Name:          sum.Name,
+			LatestVersion: int32(sum.LatestVersion),
+			Versions:      versions,
+			UpdatedAt:     timestamppb.New(sum.UpdatedAt),
+		})
+	}
+	return resp, nil
+}
+
+func (s *grpcServer) GetProgram(ctx context.Context, req *pb.GetProgramRequest) (*pb.ProgramVersion, error) {
+	version, err := s.programs.Get(req.Name, int(req.Version))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return toProgramVersion(version), nil
+}
+
+func (s *grpcServer) ExecuteProgram(ctx context.Context, req *pb.ExecuteProgramRequest) (*pb.ExecuteProgramResponse, error) {
+	program, results, inputs, err := s.programs.Execute(ctx, req.Name, int(req.Version), req.Inputs)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &pb.ExecuteProgramResponse{
+		Name:    program.Name,
+		Version: int32(program.Version),
+		Items:   toResultItems(results),
+		Inputs:  inputs,
+	}, nil
+}
+
+func (s *grpcServer) DeleteProgram(ctx context.Context, req *pb.DeleteProgramRequest) (*pb.DeleteProgramResponse, error) {
+	if err := s.programs.Delete(req.Name); err != nil {
+		return nil, grpcError(err)
+	}
+	return &pb.DeleteProgramResponse{}, nil
+}
+
+func (s *grpcServer) DiffProgram(ctx context.Context, req *pb.DiffProgramRequest) (*pb.DiffProgramResponse, error) {
+	diff, err := s.programs.Diff(req.Name, int(req.FromVersion), int(req.ToVersion))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &pb.DiffProgramResponse{
+		Name:        diff.Name,
+		FromVersion: int32(diff.From),
+		ToVersion:   int32(diff.To),
+		Added:       fromInstructions(diff.Added),
+		Removed:     fromInstructions(diff.Removed),
+		Changed:     make([]*pb.InstructionChange, 0, len(diff.Changed)),
+	}
+	for _, change := range diff.Changed {
+		from := fromInstructions([]service.Instruction{change.From})
+		to := fromInstructions([]service.Instruction{change.To})
+		resp.Changed = append(resp.Changed, &pb.InstructionChange{
+			Var:  change.Var,
+			From: from[0],
+			To:   to[0],
+		})
+	}
+	return resp, nil
+}
+
+func toProgramVersion(v programs.Version) *pb.ProgramVersion {
+	return &pb.ProgramVersion{
+		Name:         v.Name,
+		Version:      int32(v.Version),
+		Instructions: fromInstructions(v.Instructions),
+		CreatedAt:    timestamppb.New(v.CreatedAt),
+	}
+}
@@ -3,42 +3,160 @@ package main
 import (
 	"context"
 
-	"log"
+	"log/slog"
 
 	"net"
 
+	"calculator/internal/certs"
+
+	"calculator/internal/config"
+
 	"calculator/internal/pb"
 
 	"calculator/internal/service"
 
+	"calculator/internal/session"
+
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type grpcServer struct {
 	pb.UnimplementedCalculatorServiceServer
-	calculator *service.CalculatorService
+	*components
 }
 
 func (s *grpcServer) Calculate(ctx context.Context, req *pb.CalculateRequest) (*pb.CalculateResponse, error) {
-	instructions := make([]service.Instruction, 0, len(req.Instructions))
-	for _, instr := range req.Instructions {
+	instructions := toInstructions(req.Instructions)
+	inputs, err := service.Bind(instructions, req.Inputs)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	results, err := s.calculator.Run(ctx, instructions, inputs)
+	if err != nil {
+		slog.WarnContext(ctx, "execution failed", "error", err)
+		return nil, grpcError(err)
+	}
+
+	return &pb.CalculateResponse{Items: toResultItems(results), Inputs: inputs}, nil
+}
+
+func (s *grpcServer) CreateSession(ctx context.Context, req *pb.CreateSessionRequest) (*pb.Session, error) {
+	info, err := s.sessions.Create()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return toSession(info), nil
+}
+
+func (s *grpcServer) GetSession(ctx context.Context, req *pb.GetSessionRequest) (*pb.Session, error) {
+	info, err := s.sessions.Get(req.SessionId)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return toSession(info), nil
+}
+
+func (s *grpcServer) DeleteSession(ctx context.Context, req *pb.DeleteSessionRequest) (*pb.DeleteSessionResponse, error) {
+	if err := s.sessions.Delete(req.SessionId); err != nil {
+		return nil, grpcError(err)
+	}
+	return &pb.DeleteSessionResponse{}, nil
+}
+
+func (s *grpcServer) ListVariables(ctx context.Context, req *pb.ListVariablesRequest) (*pb.ListVariablesResponse, error) {
+	items, err := s.sessions.Variables(req.SessionId)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &pb.ListVariablesResponse{Items: toResultItems(items)}, nil
+}
+
+func (s *grpcServer) SessionCalculate(ctx context.Context, req *pb.SessionCalculateRequest) (*pb.CalculateResponse, error) {
+	instructions := toInstructions(req.Instructions)
+	inputs, err := service.Bind(instructions, req.Inputs)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	results, err := s.sessions.Calculate(ctx, req.SessionId, instructions, inputs)
+	if err != nil {
+		slog.WarnContext(ctx, "session execution failed", "session_id", req.SessionId, "error", err)
+		return nil, grpcError(err)
+	}
+
+	return &pb.CalculateResponse{Items: toResultItems(results), Inputs: inputs}, nil
+}
+
+func (s *grpcServer) UpdateInputs(ctx context.Context, req *pb.UpdateInputsRequest) (*pb.UpdateInputsResponse, error) {
+	changes, err := s.sessions.UpdateInputs(ctx, req.SessionId, req.Values)
+	if err != nil {
+		slog.WarnContext(ctx, "session recalculation failed", "session_id", req.SessionId, "error", err)
+		return nil, grpcError(err)
+	}
+
+	resp := &pb.UpdateInputsResponse{Changes: make([]*pb.VariableChange, 0, len(changes))}
+	for _, change := range changes {
+		resp.Changes = append(resp.Changes, &pb.VariableChange{
+			Var:      change.Var,
+			OldValue: change.Old,
+			NewValue: change.New,
+		})
+	}
+	return resp, nil
+}
+
+func toInstructions(in []*pb.Instruction) []service.Instruction {
+	instructions := make([]service.Instruction, 0, len(in))
+	for _, instr := range in {
 		left := parseValue(instr)
 		right := parseRight(instr)
 		instructions = append(instructions, service.Instruction{
-			Type:  instr.Type,
-			Op:    instr.Op,
-			Var:   instr.Var,
-			Left:  left,
-			Right: right,
+			Type:      instr.Type,
+			Op:        instr.Op,
+			Var:       instr.Var,
+			Left:      left,
+			Right:     right,
+			Default:   instr.Default,
+			ValueType: instr.ValueType,
 		})
 	}
+	return instructions
+}
 
-	results, err := s.calculator.Run(context.Background(), instructions)
-	if err != nil {
-		log.Printf("Ошибка выполнения: %v", err)
-		return nil, err
+// fromInstructions — обратное к toInstructions преобразование.
+func fromInstructions(in []service.Instruction) []*pb.Instruction {
+	instructions := make([]*pb.Instruction, 0, len(in))
+	for _, instr := range in {
+		out := &pb.Instruction{
+			Type:      instr.Type,
+			Op:        instr.Op,
+			Var:       instr.Var,
+			Default:   instr.Default,
+			ValueType: instr.ValueType,
+		}
+		switch v := instr.Left.(type) {
+		case int64:
+			out.LeftType = &pb.Instruction_LeftInt{LeftInt: v}
+		case string:
+			out.LeftType = &pb.Instruction_LeftVar{LeftVar: v}
+		}
+		switch v := instr.Right.(type) {
+		case int64:
+			out.RightType = &pb.Instruction_RightInt{RightInt: v}
+		case string:
+			out.RightType = &pb.Instruction_RightVar{RightVar: v}
+		}
+		instructions = append(instructions, out)
 	}
+	return instructions
+}
 
+func toResultItems(results []service.ResultItem) []*pb.ResultItem {
 	items := make([]*pb.ResultItem, 0, len(results))
 	for _, item := range results {
 		items = append(items, &pb.ResultItem{
@@ -46,8 +164,16 @@ func (s *grpcServer) Calculate(ctx context.Context, req *pb.CalculateRequest) (*
 			Value: item.Value,
 		})
 	}
+	return items
+}
 
-	return &pb.CalculateResponse{Items: items}, nil
+func toSession(info session.Info) *pb.Session {
+	return &pb.Session{
+		Id:            info.ID,
+		CreatedAt:     timestamppb.New(info.CreatedAt),
+		ExpiresAt:     timestamppb.New(info.ExpiresAt),
+		VariableCount: int32(info.VariableCount),
+	}
 }
 
 func parseValue(instr *pb.Instruction) interface{} {
@@ -70,17 +196,24 @@ func parseRight(instr *pb.Instruction) interface{} {
 	return nil
 }
 
-func startGRPCServer() {
-	lis, err := net.Listen("tcp", ":50051")
+func newGRPCTransport(c *components, cfg config.GRPC, certs *certs.Reloader) (*grpcTransport, error) {
+	// Сообщение больше предела отклоняется ещё при чтении, до разбора.
+	opts := append(c.chain.serverOptions(), grpc.MaxRecvMsgSize(c.limits.MaxRequestBytes))
+	if certs != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(certs.TLSConfig())))
+	}
+
+	lis, err := net.Listen("tcp", cfg.Addr)
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		return nil, err
 	}
-	s := grpc.NewServer()
-	pb.RegisterCalculatorServiceServer(s, &grpcServer{
-		calculator: service.NewCalculatorService(),
-	})
-	log.Printf("gRPC сервер запущен на порту %d\n", 50051)
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+	s := grpc.NewServer(opts...)
+	pb.RegisterCalculatorServiceServer(s, &grpcServer{components: c})
+	h := newGRPCHealth(c.health)
+	healthpb.RegisterHealthServer(s, healthService{Server: h.srv, draining: h.draining})
+	if c.reflection {
+		reflection.Register(s)
 	}
+	slog.Info("gRPC server listening", "addr", cfg.Addr, "tls", certs != nil, "mtls", cfg.TLS.ClientCAFile != "", "reflection", c.reflection)
+	return &grpcTransport{srv: s, ln: lis, health: h}, nil
 }
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"calculator/internal/pb"
+	"calculator/internal/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type calculateStream = grpc.BidiStreamingServer[pb.CalculateStreamRequest, pb.CalculateStreamResponse]
+
+// CalculateStream выполняет программу по мере поступления инструкций и
+// отправляет значения print, как только они готовы. Протокол описан в
+// proto/calculator.proto.
+func (s *grpcServer) CalculateStream(stream calculateStream) error {
+	var inputs map[string]int64
+	first, err := stream.Recv()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if start := first.GetStart(); start != nil {
+		inputs = start.Inputs
+		first = nil
+	}
+
+	var sendMu sync.Mutex
+	send := func(resp *pb.CalculateStreamResponse) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return stream.Send(resp)
+	}
+
+	st := s.calculator.NewStream(stream.Context(), service.NewEnvironment(), inputs, func(item service.ResultItem) {
+		send(&pb.CalculateStreamResponse{Frame: &pb.CalculateStreamResponse_Item{
+			Item: &pb.ResultItem{Var: item.Var, Value: item.Value},
+		}})
+	})
+
+	recvErr := make(chan error, 1)
+	go func() {
+		recvErr <- receiveInstructions(stream, st, first)
+	}()
+
+	var protocolErr error
+	select {
+	case protocolErr = <-recvErr:
+	case <-st.Failed():
+	}
+
+	summary, err := st.Close()
+	if protocolErr != nil {
+		return protocolErr
+	}
+	if err != nil {
+		return send(&pb.CalculateStreamResponse{Frame: &pb.CalculateStreamResponse_Error{Error: streamError(err)}})
+	}
+
+	unresolved := summary.Unresolved
+	return send(&pb.CalculateStreamResponse{Frame: &pb.CalculateStreamResponse_Done{Done: &pb.StreamDone{
+		Instructions: int32(summary.Instructions),
+		Printed:      int32(summary.Printed),
+		Unresolved:   unresolved,
+		Inputs:       summary.Inputs,
+	}}})
+}
+
+// receiveInstructions передаёт инструкции клиента в st до кадра end или
+// закрытия потока. Возвращает ошибку только при нарушении протокола;
+// ошибки выполнения st сообщает сам.
+func receiveInstructions(stream calculateStream, st *service.Stream, msg *pb.CalculateStreamRequest) error {
+	for {
+		if msg == nil {
+			var err error
+			msg, err = stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		switch frame := msg.Frame.(type) {
+		case *pb.CalculateStreamRequest_Instruction:
+			if err := st.Add(toInstructions([]*pb.Instruction{frame.Instruction})[0]); err != nil {
+				return nil
+			}
+		case *pb.CalculateStreamRequest_End:
+			return nil
+		case *pb.CalculateStreamRequest_Start:
+			return status.Error(codes.InvalidArgument, "start frame must be the first message")
+		default:
+			return status.Error(codes.InvalidArgument, "empty stream frame")
+		}
+		msg = nil
+	}
+}
+
+func streamError(err error) *pb.StreamError {
+	frame := &pb.StreamError{
+		Message: err.Error(),
+		Code:    int32(status.Code(grpcError(err))),
+	}
+	var evalErr *service.EvalError
+	if errors.As(err, &evalErr) {
+		frame.Var = evalErr.Var
+	}
+	return frame
+}
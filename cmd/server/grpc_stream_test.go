@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"calculator/internal/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func streamInstruction(instr *pb.Instruction) *pb.CalculateStreamRequest {
+	return &pb.CalculateStreamRequest{Frame: &pb.CalculateStreamRequest_Instruction{Instruction: instr}}
+}
+
+func streamCalc(name, op string, left, right interface{}) *pb.CalculateStreamRequest {
+	instr := &pb.Instruction{Type: "calc", Op: op, Var: name}
+	switch l := left.(type) {
+	case int64:
+		instr.LeftType = &pb.Instruction_LeftInt{LeftInt: l}
+	case string:
+		instr.LeftType = &pb.Instruction_LeftVar{LeftVar: l}
+	}
+	switch r := right.(type) {
+	case int64:
+		instr.RightType = &pb.Instruction_RightInt{RightInt: r}
+	case string:
+		instr.RightType = &pb.Instruction_RightVar{RightVar: r}
+	}
+	return streamInstruction(instr)
+}
+
+func streamPrint(name string) *pb.CalculateStreamRequest {
+	return streamInstruction(&pb.Instruction{Type: "print", Var: name})
+}
+
+func streamStart(inputs map[string]int64) *pb.CalculateStreamRequest {
+	return &pb.CalculateStreamRequest{Frame: &pb.CalculateStreamRequest_Start{Start: &pb.StreamStart{Inputs: inputs}}}
+}
+
+var streamEnd = &pb.CalculateStreamRequest{Frame: &pb.CalculateStreamRequest_End{End: &pb.EndOfInput{}}}
+
+func openStream(t *testing.T, ctx context.Context, client pb.CalculatorServiceClient, frames ...*pb.CalculateStreamRequest) pb.CalculatorService_CalculateStreamClient {
+	t.Helper()
+	stream, err := client.CalculateStream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendFrames(t, stream, frames...)
+	return stream
+}
+
+func sendFrames(t *testing.T, stream pb.CalculatorService_CalculateStreamClient, frames ...*pb.CalculateStreamRequest) {
+	t.Helper()
+	for _, frame := range frames {
+		if err := stream.Send(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// recvFinal читает кадры до завершающего и проверяет, что после него
+// сервер закрывает поток со статусом OK.
+func recvFinal(t *testing.T, stream pb.CalculatorService_CalculateStreamClient) (items []*pb.ResultItem, final *pb.CalculateStreamResponse) {
+	t.Helper()
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		if item := resp.GetItem(); item != nil {
+			items = append(items, item)
+			continue
+		}
+		if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+			t.Fatalf("after final frame: got %v, want EOF", err)
+		}
+		return items, resp
+	}
+}
+
+// Значение print приходит, как только готово, до конца ввода, а
+// следующие инструкции используют уже вычисленные переменные.
+func TestCalculateStreamIncremental(t *testing.T) {
+	client := startGRPC(t, newTestComponents(t))
+	stream := openStream(t, context.Background(), client,
+		streamStart(map[string]int64{"n": 10}),
+		streamCalc("x", "+", int64(1), int64(2)),
+		streamPrint("x"),
+	)
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item := resp.GetItem(); item.GetVar() != "x" || item.GetValue() != 3 {
+		t.Fatalf("first frame = %v, want item x=3", resp)
+	}
+
+	sendFrames(t, stream,
+		streamInstruction(&pb.Instruction{Type: "input", Var: "n"}),
+		streamCalc("y", "*", "n", "x"),
+		streamPrint("y"),
+	)
+	resp, err = stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item := resp.GetItem(); item.GetVar() != "y" || item.GetValue() != 30 {
+		t.Fatalf("second frame = %v, want item y=30", resp)
+	}
+
+	sendFrames(t, stream, streamPrint("missing"), streamEnd)
+	items, final := recvFinal(t, stream)
+	if len(items) != 0 {
+		t.Fatalf("unexpected items %v", items)
+	}
+	done := final.GetDone()
+	if done == nil || done.Instructions != 6 || done.Printed != 2 || len(done.Unresolved) != 1 || done.Unresolved[0] != "missing" || done.Inputs["n"] != 10 {
+		t.Fatalf("final frame = %v", final)
+	}
+}
+
+// Ввод заканчивается кадром end или закрытием передачи клиентом; в обоих
+// случаях поток завершается кадром done. После end сервер не ждёт
+// закрытия передачи.
+func TestCalculateStreamEndOfInput(t *testing.T) {
+	client := startGRPC(t, newTestComponents(t))
+	program := []*pb.CalculateStreamRequest{
+		streamCalc("x", "+", int64(2), int64(3)),
+		streamPrint("x"),
+	}
+	tests := []struct {
+		name   string
+		finish func(pb.CalculatorService_CalculateStreamClient) error
+	}{
+		{name: "end frame", finish: func(s pb.CalculatorService_CalculateStreamClient) error { return s.Send(streamEnd) }},
+		{name: "client EOF", finish: func(s pb.CalculatorService_CalculateStreamClient) error { return s.CloseSend() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := openStream(t, context.Background(), client, program...)
+			if err := tt.finish(stream); err != nil {
+				t.Fatal(err)
+			}
+			items, final := recvFinal(t, stream)
+			if len(items) != 1 || items[0].Value != 5 {
+				t.Fatalf("items = %v, want x=5", items)
+			}
+			if done := final.GetDone(); done == nil || done.Instructions != 2 || done.Printed != 1 {
+				t.Fatalf("final frame = %v", final)
+			}
+		})
+	}
+}
+
+// Кадр start допустим только первым; иначе вызов завершается с
+// InvalidArgument, а не кадром error.
+func TestCalculateStreamStartFrame(t *testing.T) {
+	client := startGRPC(t, newTestComponents(t))
+	tests := []struct {
+		name   string
+		frames []*pb.CalculateStreamRequest
+	}{
+		{name: "after instruction", frames: []*pb.CalculateStreamRequest{streamCalc("x", "+", int64(1), int64(1)), streamStart(nil)}},
+		{name: "repeated", frames: []*pb.CalculateStreamRequest{streamStart(nil), streamStart(nil)}},
+		{name: "empty frame", frames: []*pb.CalculateStreamRequest{{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := openStream(t, context.Background(), client, tt.frames...)
+			for {
+				_, err := stream.Recv()
+				if err == nil {
+					continue
+				}
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("got %v, want InvalidArgument", err)
+				}
+				return
+			}
+		})
+	}
+}
+
+func TestCalculateStreamErrorFrames(t *testing.T) {
+	client := startGRPC(t, newTestComponents(t))
+	tests := []struct {
+		name     string
+		frames   []*pb.CalculateStreamRequest
+		wantVar  string
+		wantCode codes.Code
+	}{
+		{
+			name:     "undefined variable",
+			frames:   []*pb.CalculateStreamRequest{streamCalc("y", "+", "missing", int64(1)), streamPrint("y")},
+			wantVar:  "y",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "reassigned variable",
+			frames: []*pb.CalculateStreamRequest{
+				streamCalc("x", "+", int64(1), int64(1)),
+				streamCalc("x", "+", int64(2), int64(2)),
+			},
+			wantVar:  "x",
+			wantCode: codes.AlreadyExists,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := openStream(t, context.Background(), client, append(tt.frames, streamEnd)...)
+			_, final := recvFinal(t, stream)
+			frame := final.GetError()
+			if frame == nil {
+				t.Fatalf("final frame = %v, want error", final)
+			}
+			if frame.Var != tt.wantVar || codes.Code(frame.Code) != tt.wantCode || frame.Message == "" {
+				t.Fatalf("error frame = %v, want var %s and code %s", frame, tt.wantVar, tt.wantCode)
+			}
+		})
+	}
+}
+
+// Отмена вызова посреди потока прерывает выполнение, а сервер остаётся
+// доступным для следующих вызовов.
+func TestCalculateStreamCanceled(t *testing.T) {
+	client := startGRPC(t, newTestComponents(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := openStream(t, ctx, client,
+		streamCalc("x", "+", int64(1), int64(2)),
+		streamPrint("x"),
+	)
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	// y ждёт переменную, которая так и не будет объявлена.
+	sendFrames(t, stream, streamCalc("y", "+", "later", int64(1)))
+	cancel()
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Fatalf("got %v, want Canceled", err)
+	}
+
+	stream = openStream(t, context.Background(), client, streamCalc("z", "+", int64(4), int64(4)), streamPrint("z"), streamEnd)
+	if items, _ := recvFinal(t, stream); len(items) != 1 || items[0].Value != 8 {
+		t.Fatalf("items after cancel = %v", items)
+	}
+}
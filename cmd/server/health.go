@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"calculator/internal/health"
+	"calculator/internal/pb"
+	"calculator/internal/storage"
+
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// healthInterval — период обновления статуса gRPC health.
+	healthInterval = time.Second
+	healthTimeout  = time.Second
+	// poolSaturationGrace — сколько пул может быть занят полностью, не
+	// влияя на готовность.
+	poolSaturationGrace = 10 * time.Second
+)
+
+// newHealthChecker собирает проверки готовности: длительное насыщение
+// общего пула, очередь заданий и исправность хранилища.
+func newHealthChecker(c *components, store storage.Store) *health.Checker {
+	checker := health.NewChecker()
+	pool := &poolCheck{usage: c.calculator.PoolUsage, grace: poolSaturationGrace, now: time.Now}
+	checker.Add("pool", pool.check)
+	checker.Add("jobs", func(context.Context) error {
+		queued, size := c.jobs.QueueUsage()
+		if queued >= size {
+			return fmt.Errorf("job queue full: %d/%d", queued, size)
+		}
+		return nil
+	})
+	checker.Add("storage", func(context.Context) error {
+		return store.Err()
+	})
+	return checker
+}
+
+// poolCheck сообщает о насыщении пула, только если оно длится дольше
+// grace. Полная занятость пула под нагрузкой — обычное состояние:
+// вычисления ждут своей очереди, а снятие сервера с балансировки при
+// каждом пике приводило бы к колебаниям готовности.
+type poolCheck struct {
+	usage func() (busy, size int)
+	grace time.Duration
+	now   func() time.Time
+
+	mu sync.Mutex
+	// since — начало текущего насыщения; нулевое, если пул не насыщен.
+	since time.Time
+}
+
+func (p *poolCheck) check(context.Context) error {
+	busy, size := p.usage()
+	now := p.now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if busy < size {
+		p.since = time.Time{}
+		return nil
+	}
+	if p.since.IsZero() {
+		p.since = now
+	}
+	if d := now.Sub(p.since); d > p.grace {
+		return fmt.Errorf("worker pool saturated for %s: %d/%d busy", d.Round(time.Second), busy, size)
+	}
+	return nil
+}
+
+// healthz — проверка живости: процесс отвечает на запросы.
+func (s *httpServer) healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// readyz — проверка готовности принимать новые запросы.
+func (s *httpServer) readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthTimeout)
+	defer cancel()
+	report := s.health.Ready(ctx)
+	code := http.StatusOK
+	if !report.Ready {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, report)
+}
+
+// grpcHealth публикует готовность через стандартный сервис
+// grpc.health.v1.Health — для сервера в целом и для CalculatorService.
+type grpcHealth struct {
+	srv     *grpchealth.Server
+	checker *health.Checker
+	stop    chan struct{}
+	// draining закрывается перед GracefulStop: открытые потоки Watch
+	// иначе не дали бы серверу остановиться.
+	draining chan struct{}
+}
+
+func newGRPCHealth(checker *health.Checker) *grpcHealth {
+	h := &grpcHealth{
+		srv:      grpchealth.NewServer(),
+		checker:  checker,
+		stop:     make(chan struct{}),
+		draining: make(chan struct{}),
+	}
+	h.update()
+	go h.loop()
+	return h
+}
+
+func (h *grpcHealth) loop() {
+	ticker := time.NewTicker(healthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+			h.update()
+		}
+	}
+}
+
+func (h *grpcHealth) update() {
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+	status := healthpb.HealthCheckResponse_SERVING
+	if !h.checker.Ready(ctx).Ready {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	h.srv.SetServingStatus("", status)
+	h.srv.SetServingStatus(pb.CalculatorService_ServiceDesc.ServiceName, status)
+}
+
+// shutdown переводит все сервисы в NOT_SERVING; последующие обновления
+// игнорируются.
+func (h *grpcHealth) shutdown() {
+	close(h.stop)
+	h.srv.Shutdown()
+}
+
+// drain завершает открытые потоки Watch.
+func (h *grpcHealth) drain() {
+	close(h.draining)
+}
+
+// healthService — grpc.health.v1.Health, потоки Watch которого
+// завершаются при остановке сервера.
+type healthService struct {
+	*grpchealth.Server
+	draining <-chan struct{}
+}
+
+func (s healthService) Watch(req *healthpb.HealthCheckRequest, stream grpc.ServerStreamingServer[healthpb.HealthCheckResponse]) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.draining:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return s.Server.Watch(req, &watchStream{ServerStreamingServer: stream, ctx: ctx})
+}
+
+type watchStream struct {
+	grpc.ServerStreamingServer[healthpb.HealthCheckResponse]
+	ctx context.Context
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"calculator/internal/health"
+	"calculator/internal/pb"
+	"calculator/internal/storage"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Кратковременное насыщение пула не влияет на готовность.
+func TestPoolCheckGrace(t *testing.T) {
+	busy := 4
+	now := time.Unix(0, 0)
+	p := &poolCheck{
+		usage: func() (int, int) { return busy, 4 },
+		grace: 10 * time.Second,
+		now:   func() time.Time { return now },
+	}
+	steps := []struct {
+		advance time.Duration
+		busy    int
+		wantErr bool
+	}{
+		{busy: 4},
+		{advance: 5 * time.Second, busy: 4},
+		{advance: 10 * time.Second, busy: 4, wantErr: true},
+		// Освободившийся пул сбрасывает отсчёт.
+		{advance: time.Second, busy: 3},
+		{advance: time.Second, busy: 4},
+		{advance: 9 * time.Second, busy: 4},
+		{advance: 2 * time.Second, busy: 4, wantErr: true},
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		busy = step.busy
+		if err := p.check(context.Background()); step.wantErr != (err != nil) {
+			t.Fatalf("step %d: check = %v, wantErr %v", i, err, step.wantErr)
+		}
+	}
+}
+
+func TestStorageCheck(t *testing.T) {
+	c := newTestComponents(t)
+	store := storage.NewMemoryStore()
+	checker := newHealthChecker(c, store)
+	if report := checker.Ready(context.Background()); !report.Ready {
+		t.Fatalf("report = %+v, want ready", report)
+	}
+	store.Close()
+	report := checker.Ready(context.Background())
+	if report.Ready || report.Checks["storage"] != storage.ErrClosed.Error() {
+		t.Fatalf("report = %+v, want storage failure", report)
+	}
+}
+
+func readyz(t *testing.T, url string) (int, health.Report) {
+	t.Helper()
+	resp, err := http.Get(url + "/readyz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var report health.Report
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, report
+}
+
+func servingStatus(t *testing.T, h *grpcHealth, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := h.srv.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Status
+}
+
+// Обработчики beforeShutdown переводят /readyz и gRPC health в
+// неготовность до остановки приёма запросов.
+func TestBeforeShutdownMarksNotServing(t *testing.T) {
+	c := newTestComponents(t)
+	url := startHTTP(t, c)
+	h := newGRPCHealth(c.health)
+
+	if code, report := readyz(t, url); code != http.StatusOK || !report.Ready {
+		t.Fatalf("readyz = %d %+v, want ready", code, report)
+	}
+	services := []string{"", pb.CalculatorService_ServiceDesc.ServiceName}
+	for _, service := range services {
+		if got := servingStatus(t, h, service); got != healthpb.HealthCheckResponse_SERVING {
+			t.Fatalf("service %q = %s, want SERVING", service, got)
+		}
+	}
+
+	// Те же обработчики, что run передаёт в lifecycle.beforeShutdown.
+	c.health.SetShuttingDown()
+	h.shutdown()
+
+	code, report := readyz(t, url)
+	if code != http.StatusServiceUnavailable || report.Ready || report.Checks["shutdown"] != health.ErrShuttingDown.Error() {
+		t.Fatalf("readyz = %d %+v, want shutting down", code, report)
+	}
+	for _, service := range services {
+		if got := servingStatus(t, h, service); got != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Fatalf("service %q = %s, want NOT_SERVING", service, got)
+		}
+	}
+	// Периодическое обновление не возвращает SERVING.
+	h.update()
+	if got := servingStatus(t, h, ""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("after update = %s, want NOT_SERVING", got)
+	}
+}
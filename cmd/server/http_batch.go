@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+
+	"calculator/internal/batch"
+	"calculator/internal/programs"
+	"calculator/internal/service"
+)
+
+// batchEvaluate выполняет программу для каждой строки CSV или JSONL и
+// потоково возвращает по одной строке NDJSON на входную строку. Итоги
+// передаются в трейлерах X-Batch-*.
+//
+// Программа передаётся либо первой частью multipart-запроса ("program",
+// вторая часть — "rows"), либо ссылкой на сохранённую программу в
+// параметрах ?program=<name>&version=<n>, и тогда тело — это строки.
+func (s *httpServer) batchEvaluate(w http.ResponseWriter, r *http.Request) {
+	instructions, rows, err := s.batchSource(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.calculator.Validate(instructions); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Строки читаются из тела одновременно с записью ответа.
+	rc := http.NewResponseController(w)
+	rc.EnableFullDuplex()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Trailer", "X-Batch-Rows, X-Batch-Failed, X-Batch-Error")
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	summary, err := s.batch.Run(r.Context(), instructions, rows, func(res batch.Result) error {
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+		return rc.Flush()
+	})
+
+	w.Header().Set("X-Batch-Rows", fmt.Sprint(summary.Rows))
+	w.Header().Set("X-Batch-Failed", fmt.Sprint(summary.Failed))
+	if err != nil {
+		w.Header().Set("X-Batch-Error", err.Error())
+	}
+}
+
+func (s *httpServer) batchSource(r *http.Request) ([]service.Instruction, batch.RowReader, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		mr, err := r.MultipartReader()
+		if err != nil {
+			return nil, nil, badRequest(err)
+		}
+		return s.multipartBatchSource(r, mr)
+	}
+
+	query := r.URL.Query()
+	name := query.Get("program")
+	if name == "" {
+		return nil, nil, badRequest(errors.New("program is required: use multipart body or ?program=<name>"))
+	}
+	version, err := programs.ParseVersion(query.Get("version"))
+	if err != nil {
+		return nil, nil, err
+	}
+	program, err := s.programs.Get(name, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	format, err := batch.FormatFromContentType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, nil, badRequest(err)
+	}
+	// Строки пакета читаются потоком, поэтому их размер не ограничен.
+	unlimitBody(r)
+	rows, err := batch.NewReader(format, r.Body)
+	if err != nil {
+		return nil, nil, badRequest(err)
+	}
+	return program.Instructions, rows, nil
+}
+
+// multipartBatchSource читает программу и возвращает строки из второй
+// части. Размер строк не ограничен, а программа ограничена тем же
+// пределом, что и тело обычного запроса.
+func (s *httpServer) multipartBatchSource(r *http.Request, mr *multipart.Reader) ([]service.Instruction, batch.RowReader, error) {
+	// Ограничение тела целиком снимается сразу: multipart.Reader читает
+	// тело с упреждением и иначе наткнулся бы на предел уже в строках.
+	unlimitBody(r)
+	part, err := mr.NextPart()
+	if err != nil || part.FormName() != "program" {
+		return nil, nil, badRequest(errors.New(`first multipart part must be "program"`))
+	}
+	var program programRequest
+	if err := json.NewDecoder(http.MaxBytesReader(nil, part, int64(s.limits.MaxRequestBytes))).Decode(&program); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, nil, fmt.Errorf("program: %w", err)
+		}
+		return nil, nil, badRequest(fmt.Errorf("program: %w", err))
+	}
+
+	part, err = mr.NextPart()
+	if err != nil || part.FormName() != "rows" {
+		return nil, nil, badRequest(errors.New(`second multipart part must be "rows"`))
+	}
+	format, err := batch.FormatFromContentType(part.Header.Get("Content-Type"))
+	if err != nil {
+		switch strings.ToLower(path.Ext(part.FileName())) {
+		case ".csv":
+			format = batch.FormatCSV
+		case ".jsonl", ".ndjson":
+			format = batch.FormatJSONL
+		default:
+			return nil, nil, badRequest(err)
+		}
+	}
+	rows, err := batch.NewReader(format, part)
+	if err != nil {
+		return nil, nil, badRequest(err)
+	}
+	return program.Instructions, rows, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"calculator/internal/batch"
+)
+
+// calculateBatchRequest — набор независимых программ с общим сроком
+// выполнения timeout_ms.
+type calculateBatchRequest struct {
+	Programs  []batch.Program `json:"programs"`
+	TimeoutMS int64           `json:"timeout_ms,omitempty"`
+}
+
+type calculateBatchResponse struct {
+	Results   map[string]batch.ProgramResult `json:"results"`
+	Succeeded int                            `json:"succeeded"`
+	Failed    int                            `json:"failed"`
+}
+
+// calculateBatch выполняет несколько независимых программ в одном
+// запросе. Ответ 200 возвращается, даже если часть программ завершилась
+// ошибкой: ошибки передаются в результатах по ID программы.
+func (s *httpServer) calculateBatch(w http.ResponseWriter, r *http.Request) {
+	var req calculateBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	if req.TimeoutMS < 0 {
+		writeError(w, badRequest(fmt.Errorf("timeout_ms must not be negative: %d", req.TimeoutMS)))
+		return
+	}
+
+	ctx := r.Context()
+	if req.TimeoutMS > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutMS)*time.Millisecond)
+		defer cancel()
+	}
+
+	results, err := s.batch.RunPrograms(ctx, req.Programs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp := calculateBatchResponse{Results: results}
+	for _, res := range results {
+		if res.Error != "" {
+			resp.Failed++
+		} else {
+			resp.Succeeded++
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
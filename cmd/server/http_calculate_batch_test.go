@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"calculator/internal/batch"
+	"calculator/internal/pb"
+	"calculator/internal/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// calculateBatchHTTP отправляет пакет в POST /calculate:batch.
+func calculateBatchHTTP(t *testing.T, url string, req calculateBatchRequest) (int, calculateBatchResponse) {
+	t.Helper()
+	payload, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(url+"/calculate:batch", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var out calculateBatchResponse
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode, out
+}
+
+func calculateBatchGRPC(client pb.CalculatorServiceClient, req calculateBatchRequest) (calculateBatchResponse, error) {
+	in := &pb.BatchCalculateRequest{TimeoutMs: req.TimeoutMS}
+	for _, p := range req.Programs {
+		in.Programs = append(in.Programs, &pb.BatchCalculateProgram{Id: p.ID, Instructions: fromInstructions(p.Instructions), Inputs: p.Inputs})
+	}
+	resp, err := client.BatchCalculate(context.Background(), in)
+	if err != nil {
+		return calculateBatchResponse{}, err
+	}
+	out := calculateBatchResponse{
+		Results:   make(map[string]batch.ProgramResult, len(resp.Results)),
+		Succeeded: int(resp.Succeeded),
+		Failed:    int(resp.Failed),
+	}
+	for id, res := range resp.Results {
+		var items []service.ResultItem
+		for _, item := range res.Items {
+			items = append(items, service.ResultItem{Var: item.Var, Value: item.Value})
+		}
+		out.Results[id] = batch.ProgramResult{Items: items, Inputs: res.Inputs, Error: res.Error}
+	}
+	return out, nil
+}
+
+// HTTP и gRPC возвращают одинаковые результаты по ID программ, а ошибка
+// одной программы не влияет на остальные.
+func TestCalculateBatch(t *testing.T) {
+	c := newTestComponents(t)
+	url := startHTTP(t, c)
+	client := startGRPC(t, c)
+
+	square := []service.Instruction{
+		{Type: "input", Var: "n"},
+		{Type: "calc", Op: "*", Var: "sq", Left: "n", Right: "n"},
+		{Type: "print", Var: "sq"},
+	}
+	req := calculateBatchRequest{Programs: []batch.Program{
+		{ID: "two", Instructions: square, Inputs: map[string]int64{"n": 2}},
+		{ID: "three", Instructions: square, Inputs: map[string]int64{"n": 3}},
+		{ID: "no input", Instructions: square},
+		{ID: "invalid", Instructions: []service.Instruction{{Type: "calc", Op: "+", Var: "x", Left: "y", Right: int64(1)}}},
+	}}
+
+	code, viaHTTP := calculateBatchHTTP(t, url, req)
+	if code != http.StatusOK {
+		t.Fatalf("HTTP status %d", code)
+	}
+	viaGRPC, err := calculateBatchGRPC(client, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(viaHTTP, viaGRPC) {
+		t.Fatalf("HTTP and gRPC results differ:\n%+v\n%+v", viaHTTP, viaGRPC)
+	}
+
+	if viaHTTP.Succeeded != 2 || viaHTTP.Failed != 2 {
+		t.Fatalf("succeeded %d, failed %d, want 2 and 2", viaHTTP.Succeeded, viaHTTP.Failed)
+	}
+	for id, want := range map[string]int64{"two": 4, "three": 9} {
+		if res := viaHTTP.Results[id]; fmt.Sprint(res.Items) != fmt.Sprint([]service.ResultItem{{Var: "sq", Value: want}}) {
+			t.Errorf("%s = %+v, want sq=%d", id, res, want)
+		}
+	}
+	for _, id := range []string{"no input", "invalid"} {
+		if res := viaHTTP.Results[id]; res.Error == "" {
+			t.Errorf("%s = %+v, want an error", id, res)
+		}
+	}
+}
+
+func TestCalculateBatchInvalid(t *testing.T) {
+	c := newTestComponents(t)
+	url := startHTTP(t, c)
+	client := startGRPC(t, c)
+
+	program := []service.Instruction{{Type: "calc", Op: "+", Var: "x", Left: int64(1), Right: int64(1)}}
+	tests := []struct {
+		name string
+		req  calculateBatchRequest
+	}{
+		{name: "missing id", req: calculateBatchRequest{Programs: []batch.Program{{Instructions: program}}}},
+		{name: "duplicate id", req: calculateBatchRequest{Programs: []batch.Program{{ID: "a", Instructions: program}, {ID: "a", Instructions: program}}}},
+		{name: "negative timeout", req: calculateBatchRequest{Programs: []batch.Program{{ID: "a", Instructions: program}}, TimeoutMS: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _ := calculateBatchHTTP(t, url, tt.req); code != http.StatusBadRequest {
+				t.Errorf("HTTP status %d, want 400", code)
+			}
+			if _, err := calculateBatchGRPC(client, tt.req); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("gRPC error %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+// По истечении timeout_ms незавершённые программы получают ошибку
+// контекста, а ответ всё равно приходит.
+func TestCalculateBatchTimeout(t *testing.T) {
+	c := newTestComponents(t, service.WithLatency(time.Hour))
+	url := startHTTP(t, c)
+	client := startGRPC(t, c)
+
+	req := calculateBatchRequest{
+		Programs: []batch.Program{
+			{ID: "a", Instructions: []service.Instruction{{Type: "calc", Op: "+", Var: "x", Left: int64(1), Right: int64(1)}}},
+		},
+		TimeoutMS: 20,
+	}
+	code, viaHTTP := calculateBatchHTTP(t, url, req)
+	if code != http.StatusOK {
+		t.Fatalf("HTTP status %d", code)
+	}
+	viaGRPC, err := calculateBatchGRPC(client, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, resp := range []calculateBatchResponse{viaHTTP, viaGRPC} {
+		if resp.Failed != 1 || resp.Results["a"].Error != context.DeadlineExceeded.Error() {
+			t.Fatalf("response = %+v, want a deadline error", resp)
+		}
+	}
+}
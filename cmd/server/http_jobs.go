@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"calculator/internal/jobs"
+	"calculator/internal/service"
+)
+
+// jobRequest — программа задания и необязательный адрес уведомления.
+// Как и для /calculate, принимается и просто массив инструкций.
+type jobRequest struct {
+	Instructions []service.Instruction `json:"instructions"`
+	Inputs       map[string]int64      `json:"inputs,omitempty"`
+	Callback     *jobs.Callback        `json:"callback,omitempty"`
+}
+
+func (j *jobRequest) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		*j = jobRequest{}
+		return json.Unmarshal(trimmed, &j.Instructions)
+	}
+	type plain jobRequest
+	return json.Unmarshal(data, (*plain)(j))
+}
+
+func (s *httpServer) submitJob(w http.ResponseWriter, r *http.Request) {
+	var req jobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	job, err := s.jobs.Submit(r.Context(), req.Instructions, req.Inputs, req.Callback)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (s *httpServer) getJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.jobs.Get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *httpServer) cancelJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.jobs.Cancel(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *httpServer) redeliverJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.jobs.Redeliver(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"calculator/internal/programs"
+	"calculator/internal/service"
+)
+
+func (s *httpServer) listPrograms(w http.ResponseWriter, r *http.Request) {
+	summaries, err := s.programs.List()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	response := struct {
+		Programs []programs.Summary `json:"programs"`
+	}{Programs: summaries}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *httpServer) putProgram(w http.ResponseWriter, r *http.Request) {
+	var req programRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	version, err := s.programs.Put(r.PathValue("name"), req.Instructions)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, version)
+}
+
+func (s *httpServer) getProgram(w http.ResponseWriter, r *http.Request) {
+	version, err := programs.ParseVersion(r.URL.Query().Get("version"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	program, err := s.programs.Get(r.PathValue("name"), version)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, program)
+}
+
+func (s *httpServer) deleteProgram(w http.ResponseWriter, r *http.Request) {
+	if err := s.programs.Delete(r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *httpServer) executeProgram(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Version int              `json:"version"`
+		Inputs  map[string]int64 `json:"inputs"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	program, results, inputs, err := s.programs.Execute(r.Context(), r.PathValue("name"), req.Version, req.Inputs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	response := struct {
+		Name    string               `json:"name"`
+		Version int                  `json:"version"`
+		Items   []service.ResultItem `json:"items"`
+		Inputs  map[string]int64     `json:"inputs,omitempty"`
+	}{Name: program.Name, Version: program.Version, Items: results, Inputs: inputs}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *httpServer) diffProgram(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, err := programs.ParseVersion(query.Get("from"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	to, err := programs.ParseVersion(query.Get("to"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	diff, err := s.programs.Diff(r.PathValue("name"), from, to)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, diff)
+}
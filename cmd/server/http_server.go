@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"calculator/internal/certs"
+	"calculator/internal/config"
+	"calculator/internal/service"
+	"calculator/internal/session"
+)
+
+type httpServer struct {
+	*components
+}
+
+func (s *httpServer) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.healthz)
+	mux.HandleFunc("GET /readyz", s.readyz)
+	mux.Handle("GET /metrics", s.metrics.registry.Handler())
+	if s.reflection {
+		mux.HandleFunc("GET /api/descriptor", s.descriptor)
+	}
+	mux.HandleFunc("/calculate", s.calculate)
+	mux.HandleFunc("POST /calculate:batch", s.calculateBatch)
+	mux.HandleFunc("POST /sessions", s.createSession)
+	mux.HandleFunc("GET /sessions/{id}", s.getSession)
+	mux.HandleFunc("DELETE /sessions/{id}", s.deleteSession)
+	mux.HandleFunc("GET /sessions/{id}/vars", s.listVariables)
+	mux.HandleFunc("PATCH /sessions/{id}/vars", s.updateInputs)
+	mux.HandleFunc("POST /sessions/{id}/calculate", s.sessionCalculate)
+	mux.HandleFunc("GET /programs", s.listPrograms)
+	mux.HandleFunc("PUT /programs/{name}", s.putProgram)
+	mux.HandleFunc("GET /programs/{name}", s.getProgram)
+	mux.HandleFunc("DELETE /programs/{name}", s.deleteProgram)
+	mux.HandleFunc("POST /programs/{name}/execute", s.executeProgram)
+	mux.HandleFunc("GET /programs/{name}/diff", s.diffProgram)
+	mux.HandleFunc("POST /batch", s.batchEvaluate)
+	mux.HandleFunc("POST /jobs", s.submitJob)
+	mux.HandleFunc("GET /jobs/{id}", s.getJob)
+	mux.HandleFunc("DELETE /jobs/{id}", s.cancelJob)
+	mux.HandleFunc("POST /jobs/{id}/redeliver", s.redeliverJob)
+	return mux
+}
+
+// programRequest — тело запроса на выполнение программы. Для
+// совместимости принимается и просто массив инструкций.
+type programRequest struct {
+	Instructions []service.Instruction `json:"instructions"`
+	Inputs       map[string]int64      `json:"inputs,omitempty"`
+}
+
+func (p *programRequest) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		p.Inputs = nil
+		return json.Unmarshal(trimmed, &p.Instructions)
+	}
+	type plain programRequest
+	return json.Unmarshal(data, (*plain)(p))
+}
+
+type calculateResponse struct {
+	Items  []service.ResultItem `json:"items"`
+	Inputs map[string]int64     `json:"inputs,omitempty"`
+}
+
+// decodeProgram читает программу из тела запроса и связывает её входы.
+func decodeProgram(w http.ResponseWriter, r *http.Request) (programRequest, map[string]int64, bool) {
+	var req programRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBodyError(w, err)
+		return req, nil, false
+	}
+	inputs, err := service.Bind(req.Instructions, req.Inputs)
+	if err != nil {
+		writeError(w, err)
+		return req, nil, false
+	}
+	return req, inputs, true
+}
+
+func (s *httpServer) calculate(w http.ResponseWriter, r *http.Request) {
+	req, inputs, ok := decodeProgram(w, r)
+	if !ok {
+		return
+	}
+
+	switch streamingFormat(r) {
+	case contentTypeNDJSON:
+		s.calculateNDJSON(w, r, req, inputs)
+		return
+	case contentTypeSSE:
+		s.calculateSSE(w, r, req, inputs)
+		return
+	}
+
+	results, err := s.calculator.Run(r.Context(), req.Instructions, inputs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, calculateResponse{Items: results, Inputs: inputs})
+}
+
+func (s *httpServer) createSession(w http.ResponseWriter, r *http.Request) {
+	info, err := s.sessions.Create()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, info)
+}
+
+func (s *httpServer) getSession(w http.ResponseWriter, r *http.Request) {
+	info, err := s.sessions.Get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+func (s *httpServer) deleteSession(w http.ResponseWriter, r *http.Request) {
+	if err := s.sessions.Delete(r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *httpServer) listVariables(w http.ResponseWriter, r *http.Request) {
+	items, err := s.sessions.Variables(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeItems(w, items)
+}
+
+func (s *httpServer) updateInputs(w http.ResponseWriter, r *http.Request) {
+	var inputs map[string]int64
+	if err := json.NewDecoder(r.Body).Decode(&inputs); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	changes, err := s.sessions.UpdateInputs(r.Context(), r.PathValue("id"), inputs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	response := struct {
+		Changes []session.Change `json:"changes"`
+	}{Changes: changes}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *httpServer) sessionCalculate(w http.ResponseWriter, r *http.Request) {
+	req, inputs, ok := decodeProgram(w, r)
+	if !ok {
+		return
+	}
+
+	results, err := s.sessions.Calculate(r.Context(), r.PathValue("id"), req.Instructions, inputs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, calculateResponse{Items: results, Inputs: inputs})
+}
+
+func writeItems(w http.ResponseWriter, items []service.ResultItem) {
+	response := struct {
+		Items []service.ResultItem `json:"items"`
+	}{Items: items}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	code := httpStatus(err)
+	setRetryAfter(w.Header(), err)
+	if code == http.StatusInternalServerError {
+		http.Error(w, fmt.Sprintf("Execution error: %v", err), code)
+		return
+	}
+	http.Error(w, err.Error(), code)
+}
+
+// newHTTPTransport занимает адрес HTTP сервера. Контексты запросов
+// происходят от общего контекста, который отменяется при
+// принудительной остановке. certs не nil включает TLS.
+func newHTTPTransport(c *components, cfg config.Listener, certs *certs.Reloader) (*httpTransport, error) {
+	ln, err := net.Listen("tcp", cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+	base, cancel := context.WithCancel(context.Background())
+	t := &httpTransport{
+		srv: &http.Server{
+			Handler:     c.chain.wrapHTTP(c.routes),
+			BaseContext: func(net.Listener) context.Context { return base },
+		},
+		ln:     ln,
+		tls:    certs != nil,
+		cancel: cancel,
+	}
+	if t.tls {
+		t.srv.TLSConfig = certs.TLSConfig()
+	}
+	slog.Info("HTTP server listening", "addr", cfg.Addr, "tls", t.tls, "mtls", cfg.TLS.ClientCAFile != "")
+	return t, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"calculator/internal/service"
+)
+
+const (
+	contentTypeNDJSON = "application/x-ndjson"
+	contentTypeSSE    = "text/event-stream"
+)
+
+// streamErrorLine — последняя строка потока при ошибке выполнения.
+type streamErrorLine struct {
+	Var   string `json:"var,omitempty"`
+	Error string `json:"error"`
+}
+
+func newStreamErrorLine(err error) streamErrorLine {
+	line := streamErrorLine{Error: err.Error()}
+	var evalErr *service.EvalError
+	if errors.As(err, &evalErr) {
+		line.Var = evalErr.Var
+	}
+	return line
+}
+
+// streamingFormat возвращает потоковый формат ответа, запрошенный в
+// заголовке Accept, или пустую строку для обычного JSON.
+func streamingFormat(r *http.Request) string {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case contentTypeNDJSON, contentTypeSSE:
+			return mediaType
+		}
+	}
+	return ""
+}
+
+// calculateNDJSON пишет по строке NDJSON на каждую переменную из print,
+// как только она вычислена. Статус 200 отправляется до проверки
+// программы, поэтому ошибки проверки и выполнения приходят только
+// последней строкой — объектом с полем error. Ошибки разбора тела и
+// входов decodeProgram возвращает обычным ответом.
+func (s *httpServer) calculateNDJSON(w http.ResponseWriter, r *http.Request, req programRequest, inputs map[string]int64) {
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", contentTypeNDJSON)
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	st := s.calculator.NewStream(r.Context(), service.NewEnvironment(), inputs, func(item service.ResultItem) {
+		enc.Encode(item)
+		rc.Flush()
+	})
+	for _, instr := range req.Instructions {
+		if err := st.Add(instr); err != nil {
+			break
+		}
+	}
+	if _, err := st.Close(); err != nil {
+		enc.Encode(newStreamErrorLine(err))
+		rc.Flush()
+	}
+}
+
+// calculateSSE отправляет события scheduled, started, completed и failed
+// для каждой переменной и итоговое событие summary.
+func (s *httpServer) calculateSSE(w http.ResponseWriter, r *http.Request, req programRequest, inputs map[string]int64) {
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", contentTypeSSE)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	var mu sync.Mutex
+	writeEvent := func(name string, v interface{}) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+		rc.Flush()
+	}
+
+	results, err := s.calculator.Run(r.Context(), req.Instructions, inputs, service.WithEvents(func(ev service.Event) {
+		writeEvent(string(ev.Type), ev)
+	}))
+
+	if results == nil {
+		results = make([]service.ResultItem, 0)
+	}
+	summary := struct {
+		Items  []service.ResultItem `json:"items"`
+		Inputs map[string]int64     `json:"inputs,omitempty"`
+		*streamErrorLine
+	}{Items: results, Inputs: inputs}
+	if err != nil {
+		line := newStreamErrorLine(err)
+		summary.streamErrorLine = &line
+	}
+	writeEvent("summary", summary)
+}
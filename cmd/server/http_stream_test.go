@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"calculator/internal/service"
+)
+
+func TestStreamingFormat(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{accept: "", want: ""},
+		{accept: "application/json", want: ""},
+		{accept: "application/x-ndjson", want: contentTypeNDJSON},
+		{accept: "text/event-stream", want: contentTypeSSE},
+		{accept: "text/html, application/x-ndjson;q=0.9", want: contentTypeNDJSON},
+		{accept: "TEXT/Event-Stream", want: contentTypeSSE},
+		{accept: "invalid;;, text/event-stream", want: contentTypeSSE},
+		{accept: "text/event-stream, application/x-ndjson", want: contentTypeSSE},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/calculate", nil)
+		r.Header.Set("Accept", tt.accept)
+		if got := streamingFormat(r); got != tt.want {
+			t.Errorf("streamingFormat(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+// postStream отправляет программу на /calculate с заданным Accept.
+func postStream(t *testing.T, url, accept, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url+"/calculate", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", accept)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func readLines(t *testing.T, resp *http.Response) []map[string]interface{} {
+	t.Helper()
+	lines := make([]map[string]interface{}, 0)
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return lines
+}
+
+const streamProgram = `{"instructions": [
+	{"type": "calc", "op": "+", "var": "x", "left": 1, "right": 2},
+	{"type": "calc", "op": "*", "var": "y", "left": "x", "right": "n"},
+	{"type": "input", "var": "n"},
+	{"type": "print", "var": "x"},
+	{"type": "print", "var": "y"},
+	{"type": "print", "var": "x"}
+], "inputs": {"n": 4}}`
+
+// Каждая инструкция print даёт одну строку; порядок строк — порядок
+// готовности.
+func TestCalculateNDJSON(t *testing.T) {
+	url := startHTTP(t, newTestComponents(t))
+	resp := postStream(t, url, contentTypeNDJSON, streamProgram)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != contentTypeNDJSON {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	got := make(map[string]int)
+	for _, line := range readLines(t, resp) {
+		if line["error"] != nil {
+			t.Fatalf("unexpected error line %v", line)
+		}
+		want := map[string]float64{"x": 3, "y": 12}[line["var"].(string)]
+		if line["value"] != want {
+			t.Fatalf("line %v, want value %v", line, want)
+		}
+		got[line["var"].(string)]++
+	}
+	if got["x"] != 2 || got["y"] != 1 {
+		t.Fatalf("lines per variable = %v, want x twice and y once", got)
+	}
+}
+
+// Ответ NDJSON начинается со статуса 200 до проверки программы: ошибки
+// проверки и выполнения приходят последней строкой. Ошибки разбора тела
+// и входов возвращаются обычным ответом.
+func TestCalculateNDJSONErrors(t *testing.T) {
+	url := startHTTP(t, newTestComponents(t))
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantVar    string
+	}{
+		{
+			name:       "undefined variable",
+			body:       `[{"type":"calc","op":"+","var":"x","left":1,"right":2},{"type":"print","var":"x"},{"type":"calc","op":"+","var":"y","left":"missing","right":1}]`,
+			wantStatus: http.StatusOK,
+			wantVar:    "y",
+		},
+		{
+			name:       "unknown operation",
+			body:       `[{"type":"calc","op":"%","var":"x","left":1,"right":2}]`,
+			wantStatus: http.StatusOK,
+			wantVar:    "x",
+		},
+		{
+			name:       "reassigned variable",
+			body:       `[{"type":"calc","op":"+","var":"x","left":1,"right":2},{"type":"calc","op":"+","var":"x","left":3,"right":4}]`,
+			wantStatus: http.StatusOK,
+			wantVar:    "x",
+		},
+		{name: "malformed body", body: `[{"type":`, wantStatus: http.StatusBadRequest},
+		{name: "missing input", body: `[{"type":"input","var":"n"}]`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := postStream(t, url, contentTypeNDJSON, tt.body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if ct := resp.Header.Get("Content-Type"); ct == contentTypeNDJSON {
+					t.Fatalf("error response has content type %s", ct)
+				}
+				return
+			}
+			lines := readLines(t, resp)
+			if len(lines) == 0 {
+				t.Fatal("empty stream")
+			}
+			last := lines[len(lines)-1]
+			if last["error"] == nil || last["var"] != tt.wantVar {
+				t.Fatalf("last line = %v, want error for %s", last, tt.wantVar)
+			}
+			for _, line := range lines[:len(lines)-1] {
+				if line["error"] != nil {
+					t.Fatalf("error line %v before the last one", line)
+				}
+			}
+		})
+	}
+}
+
+type sseEvent struct {
+	name string
+	data json.RawMessage
+}
+
+func readEvents(t *testing.T, resp *http.Response) []sseEvent {
+	t.Helper()
+	events := make([]sseEvent, 0)
+	sc := bufio.NewScanner(resp.Body)
+	var ev sseEvent
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			ev.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.data = json.RawMessage(strings.TrimPrefix(line, "data: "))
+		case line == "":
+			events = append(events, ev)
+			ev = sseEvent{}
+		default:
+			t.Fatalf("unexpected line %q", line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+// События каждой переменной идут в порядке scheduled, started,
+// completed, а итоговое событие summary — последним.
+func TestCalculateSSE(t *testing.T) {
+	url := startHTTP(t, newTestComponents(t))
+	resp := postStream(t, url, contentTypeSSE, streamProgram)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != contentTypeSSE {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	events := readEvents(t, resp)
+	if len(events) == 0 || events[len(events)-1].name != "summary" {
+		t.Fatalf("events = %v, want summary last", events)
+	}
+
+	seen := make(map[string][]service.EventType)
+	for _, ev := range events[:len(events)-1] {
+		var e service.Event
+		if err := json.Unmarshal(ev.data, &e); err != nil {
+			t.Fatal(err)
+		}
+		if string(e.Type) != ev.name {
+			t.Fatalf("event %s carries type %s", ev.name, e.Type)
+		}
+		seen[e.Var] = append(seen[e.Var], e.Type)
+	}
+	order := []service.EventType{service.EventScheduled, service.EventStarted, service.EventCompleted}
+	for _, name := range []string{"x", "y", "n"} {
+		if len(seen[name]) != len(order) {
+			t.Fatalf("%s events = %v, want %v", name, seen[name], order)
+		}
+		for i, typ := range order {
+			if seen[name][i] != typ {
+				t.Fatalf("%s events = %v, want %v", name, seen[name], order)
+			}
+		}
+	}
+
+	var summary struct {
+		Items  []service.ResultItem `json:"items"`
+		Inputs map[string]int64     `json:"inputs"`
+		Error  string               `json:"error"`
+	}
+	if err := json.Unmarshal(events[len(events)-1].data, &summary); err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.Items) != 3 || summary.Inputs["n"] != 4 || summary.Error != "" {
+		t.Fatalf("summary = %+v", summary)
+	}
+}
+
+// При ошибке summary содержит пустой список items, а не null.
+func TestCalculateSSEError(t *testing.T) {
+	url := startHTTP(t, newTestComponents(t))
+	resp := postStream(t, url, contentTypeSSE, `[{"type":"calc","op":"/","var":"x","left":1,"right":0},{"type":"print","var":"x"}]`)
+	events := readEvents(t, resp)
+	if len(events) == 0 {
+		t.Fatal("no events")
+	}
+	last := events[len(events)-1]
+	if last.name != "summary" {
+		t.Fatalf("last event = %s, want summary", last.name)
+	}
+	var summary map[string]json.RawMessage
+	if err := json.Unmarshal(last.data, &summary); err != nil {
+		t.Fatal(err)
+	}
+	if string(summary["items"]) != "[]" || string(summary["var"]) != `"x"` || summary["error"] == nil {
+		t.Fatalf("summary = %s", last.data)
+	}
+	if !bytes.Contains(events[len(events)-2].data, []byte(`"failed"`)) {
+		t.Fatalf("event before summary = %s %s, want failed", events[len(events)-2].name, events[len(events)-2].data)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Коды завершения процесса.
+const (
+	exitOK = 0
+	// exitServeError — один из серверов не смог запуститься или упал.
+	exitServeError = 1
+	// exitConfig — некорректная конфигурация.
+	exitConfig = 2
+	// exitForced — за отведённое время не все вызовы завершились, и
+	// оставшиеся были прерваны.
+	exitForced = 3
+)
+
+// transport — сервер одного протокола под управлением lifecycle.
+type transport interface {
+	name() string
+	// serve блокируется до остановки; после штатной остановки
+	// возвращает nil.
+	serve() error
+	// shutdown перестаёт принимать новые вызовы и ждёт завершения
+	// текущих, пока не истечёт ctx.
+	shutdown(ctx context.Context) error
+	// stop прерывает оставшиеся вызовы.
+	stop()
+}
+
+// lifecycle запускает транспорты и останавливает их все вместе: по
+// SIGINT/SIGTERM или при падении любого из них.
+type lifecycle struct {
+	transports []transport
+	timeout    time.Duration
+	// beforeShutdown вызываются при начале остановки, затем lifecycle
+	// ждёт delay и только после этого перестаёт принимать запросы.
+	beforeShutdown []func()
+	delay          time.Duration
+}
+
+// run возвращает код завершения процесса.
+func (l *lifecycle) run() int {
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	go func() {
+		<-signals.Done()
+		// Повторный сигнал завершает процесс сразу.
+		stopSignals()
+	}()
+	return l.runUntil(signals)
+}
+
+// runUntil работает до отмены stop или падения одного из транспортов.
+func (l *lifecycle) runUntil(stop context.Context) int {
+	errc := make(chan error, len(l.transports))
+	for _, t := range l.transports {
+		go func() {
+			if err := t.serve(); err != nil {
+				errc <- errors.New(t.name() + ": " + err.Error())
+			}
+		}()
+	}
+
+	code := exitOK
+	select {
+	case <-stop.Done():
+		slog.Info("shutdown signal received, draining in-flight calls", "timeout", l.timeout)
+	case err := <-errc:
+		slog.Error("server stopped with error", "error", err)
+		code = exitServeError
+	}
+
+	for _, fn := range l.beforeShutdown {
+		fn()
+	}
+	if l.delay > 0 {
+		time.Sleep(l.delay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		forced bool
+	)
+	for _, t := range l.transports {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := t.shutdown(ctx); err != nil {
+				slog.Warn("in-flight calls did not finish in time, forcing stop", "transport", t.name(), "error", err)
+				t.stop()
+				mu.Lock()
+				forced = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if forced && code == exitOK {
+		code = exitForced
+	}
+	slog.Info("servers stopped", "exit_code", code)
+	return code
+}
+
+type httpTransport struct {
+	srv *http.Server
+	ln  net.Listener
+	tls bool
+	// cancel отменяет контексты всех запросов при принудительной
+	// остановке.
+	cancel context.CancelFunc
+}
+
+func (t *httpTransport) name() string { return "HTTP" }
+
+func (t *httpTransport) serve() error {
+	var err error
+	if t.tls {
+		err = t.srv.ServeTLS(t.ln, "", "")
+	} else {
+		err = t.srv.Serve(t.ln)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (t *httpTransport) shutdown(ctx context.Context) error {
+	return t.srv.Shutdown(ctx)
+}
+
+func (t *httpTransport) stop() {
+	t.cancel()
+	t.srv.Close()
+}
+
+type grpcTransport struct {
+	srv    *grpc.Server
+	ln     net.Listener
+	health *grpcHealth
+}
+
+func (t *grpcTransport) name() string { return "gRPC" }
+
+func (t *grpcTransport) serve() error {
+	return t.srv.Serve(t.ln)
+}
+
+func (t *grpcTransport) shutdown(ctx context.Context) error {
+	t.health.drain()
+	done := make(chan struct{})
+	go func() {
+		t.srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *grpcTransport) stop() {
+	t.srv.Stop()
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"calculator/internal/jobs"
+	"calculator/internal/service"
+)
+
+// eventLog — общий для транспортов журнал вызовов.
+type eventLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *eventLog) record(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *eventLog) list() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.events...)
+}
+
+// fakeTransport работает до вызова shutdown; при непустом serveErr serve
+// сразу завершается с этой ошибкой.
+type fakeTransport struct {
+	serveErr error
+	log      *eventLog
+	done     chan struct{}
+	closeOne sync.Once
+}
+
+func newFakeTransport(log *eventLog, serveErr error) *fakeTransport {
+	return &fakeTransport{serveErr: serveErr, log: log, done: make(chan struct{})}
+}
+
+func (t *fakeTransport) name() string { return "fake" }
+
+func (t *fakeTransport) serve() error {
+	if t.serveErr != nil {
+		return t.serveErr
+	}
+	<-t.done
+	return nil
+}
+
+func (t *fakeTransport) shutdown(ctx context.Context) error {
+	t.log.record("shutdown")
+	t.closeOne.Do(func() { close(t.done) })
+	return nil
+}
+
+func (t *fakeTransport) stop() { t.log.record("stop") }
+
+// startLifecycle запускает l и возвращает функцию остановки, которая
+// ждёт кода завершения.
+func startLifecycle(l *lifecycle) (stop func() int, code <-chan int) {
+	ctx, cancel := context.WithCancel(context.Background())
+	codes := make(chan int, 1)
+	go func() { codes <- l.runUntil(ctx) }()
+	return func() int {
+		cancel()
+		return <-codes
+	}, codes
+}
+
+// newLifecycleHTTP запускает HTTP транспорт с обработчиком handler.
+func newLifecycleHTTP(t *testing.T, handler http.HandlerFunc) (*httpTransport, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, cancel := context.WithCancel(context.Background())
+	tr := &httpTransport{
+		srv: &http.Server{
+			Handler:     handler,
+			BaseContext: func(net.Listener) context.Context { return base },
+		},
+		ln:     ln,
+		cancel: cancel,
+	}
+	t.Cleanup(func() { tr.stop() })
+	return tr, "http://" + ln.Addr().String()
+}
+
+type httpResult struct {
+	body string
+	err  error
+}
+
+func getAsync(url string) <-chan httpResult {
+	res := make(chan httpResult, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			res <- httpResult{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		res <- httpResult{body: string(body), err: err}
+	}()
+	return res
+}
+
+// Вызов, начатый до остановки, завершается, если укладывается в
+// shutdown.timeout.
+func TestLifecycleDrainsInFlight(t *testing.T) {
+	started := make(chan struct{})
+	tr, url := newLifecycleHTTP(t, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		io.WriteString(w, "done")
+	})
+	stop, _ := startLifecycle(&lifecycle{transports: []transport{tr}, timeout: 5 * time.Second})
+
+	res := getAsync(url)
+	<-started
+	if code := stop(); code != exitOK {
+		t.Fatalf("exit code = %d, want %d", code, exitOK)
+	}
+	if r := <-res; r.err != nil || r.body != "done" {
+		t.Fatalf("in-flight request = %q, %v", r.body, r.err)
+	}
+	if _, err := http.Get(url); err == nil {
+		t.Fatal("server accepts requests after shutdown")
+	}
+}
+
+// Если вызовы не успевают завершиться, они прерываются, а процесс
+// завершается с exitForced.
+func TestLifecycleForcedStop(t *testing.T) {
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+	tr, url := newLifecycleHTTP(t, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+		close(canceled)
+	})
+	stop, _ := startLifecycle(&lifecycle{transports: []transport{tr}, timeout: 20 * time.Millisecond})
+
+	res := getAsync(url)
+	<-started
+	if code := stop(); code != exitForced {
+		t.Fatalf("exit code = %d, want %d", code, exitForced)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight request was not canceled")
+	}
+	if r := <-res; r.err == nil {
+		t.Fatalf("interrupted request succeeded: %q", r.body)
+	}
+}
+
+// Падение одного транспорта останавливает остальные; beforeShutdown
+// вызываются до того, как транспорты перестают принимать вызовы.
+func TestLifecycleTransportFailure(t *testing.T) {
+	log := &eventLog{}
+	failing := newFakeTransport(log, errors.New("address already in use"))
+	healthy := newFakeTransport(log, nil)
+	l := &lifecycle{
+		transports:     []transport{failing, healthy},
+		timeout:        time.Second,
+		beforeShutdown: []func(){func() { log.record("before shutdown") }},
+	}
+	_, codes := startLifecycle(l)
+
+	select {
+	case code := <-codes:
+		if code != exitServeError {
+			t.Fatalf("exit code = %d, want %d", code, exitServeError)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("lifecycle did not stop after a transport failure")
+	}
+	events := log.list()
+	if len(events) != 3 || events[0] != "before shutdown" || events[1] != "shutdown" || events[2] != "shutdown" {
+		t.Fatalf("events = %v, want before shutdown and then shutdown of both transports", events)
+	}
+}
+
+// jobs.Close при остановке не ждёт выполняющихся заданий, а отменяет их.
+func TestJobsCloseCancelsRunning(t *testing.T) {
+	c := newTestComponents(t, service.WithLatency(time.Hour))
+	info, err := c.jobs.Submit(context.Background(), []service.Instruction{{Type: "calc", Op: "+", Var: "x", Left: int64(1), Right: int64(2)}}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for info.State != jobs.StateRunning && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		info, _ = c.jobs.Get(info.ID)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		c.jobs.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("jobs.Close waited for a running job")
+	}
+	if info, _ = c.jobs.Get(info.ID); info.State != jobs.StateCanceled {
+		t.Fatalf("job after Close = %+v, want canceled", info)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"calculator/internal/service"
+)
+
+// batchBody собирает multipart-тело POST /batch.
+func batchBody(t *testing.T, program string, rows string) (io.Reader, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part, err := mw.CreateFormField("program")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(part, program)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="rows"; filename="rows.csv"`)
+	h.Set("Content-Type", "text/csv")
+	part, err = mw.CreatePart(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(part, rows)
+	mw.Close()
+	return &buf, mw.FormDataContentType()
+}
+
+func TestBatchBodyLimit(t *testing.T) {
+	c := newTestComponents(t, service.WithLatency(0))
+	c.limits.MaxRequestBytes = 1024
+	useChain(t, c, "limits")
+	url := startHTTP(t, c)
+
+	program := `{"instructions":[{"type":"input","var":"n"},{"type":"calc","op":"+","var":"r","left":"n","right":1},{"type":"print","var":"r"}]}`
+	var rows strings.Builder
+	rows.WriteString("n\n")
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&rows, "%d\n", i)
+	}
+	// Имя с пробелами даёт программу больше предела, но корректную.
+	bigProgram := strings.Replace(program, `"var":"r"`, `"var":"r"`+strings.Repeat(" ", 2048), 1)
+
+	tests := []struct {
+		name    string
+		program string
+		want    int
+	}{
+		{name: "rows beyond limit", program: program, want: http.StatusOK},
+		{name: "program beyond limit", program: bigProgram, want: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType := batchBody(t, tt.program, rows.String())
+			resp, err := http.Post(url+"/batch", contentType, body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			out, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.want, out)
+			}
+			if tt.want == http.StatusOK {
+				if n := bytes.Count(out, []byte("\n")); n != 1000 || resp.Trailer.Get("X-Batch-Failed") != "0" {
+					t.Fatalf("got %d result lines, trailers %v", n, resp.Trailer)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"calculator/internal/logging"
+	"calculator/internal/tracing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey — ключ метаданных gRPC с идентификатором запроса.
+var requestIDKey = strings.ToLower(logging.HeaderRequestID)
+
+// logHTTP назначает запросу идентификатор, возвращает его в заголовке
+// ответа и записывает в журнал итог запроса. Идентификатор также
+// добавляется к спану запроса, если трассировка включена.
+func logHTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := logging.AcceptRequestID(r.Header.Get(logging.HeaderRequestID))
+		w.Header().Set(logging.HeaderRequestID, id)
+		ctx := logging.WithRequestID(r.Context(), id)
+		tracing.SpanFromContext(ctx).SetAttribute("request_id", id)
+
+		rec := &statusRecorder{ResponseWriter: w}
+		r = r.WithContext(ctx)
+		next.ServeHTTP(rec, r)
+
+		level := slog.LevelInfo
+		if rec.code() >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(ctx, level, "request completed",
+			slog.String("transport", "http"),
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.String("route", r.Pattern),
+			slog.Int("status", rec.code()),
+			slog.Duration("duration", time.Since(start)),
+		)
+	})
+}
+
+// rpcRequestID берёт идентификатор запроса из метаданных или создаёт
+// новый.
+func rpcRequestID(ctx context.Context) string {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(requestIDKey); len(v) > 0 {
+			id = v[0]
+		}
+	}
+	return logging.AcceptRequestID(id)
+}
+
+func logRPC(ctx context.Context, method string, start time.Time, err error) {
+	level := slog.LevelInfo
+	code := status.Code(err)
+	if isServerError(code) {
+		level = slog.LevelError
+	}
+	attrs := []slog.Attr{
+		slog.String("transport", "grpc"),
+		slog.String("method", method),
+		slog.String("status", code.String()),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+	slog.LogAttrs(ctx, level, "request completed", attrs...)
+}
+
+// logUnary возвращает идентификатор запроса в заголовках и трейлерах
+// ответа и записывает в журнал итог вызова.
+func logUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	id := rpcRequestID(ctx)
+	md := metadata.Pairs(requestIDKey, id)
+	grpc.SetHeader(ctx, md)
+	grpc.SetTrailer(ctx, md)
+	ctx = logging.WithRequestID(ctx, id)
+	tracing.SpanFromContext(ctx).SetAttribute("request_id", id)
+
+	resp, err := handler(ctx, req)
+	logRPC(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+func logStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	id := rpcRequestID(ss.Context())
+	md := metadata.Pairs(requestIDKey, id)
+	ss.SetHeader(md)
+	ss.SetTrailer(md)
+	ctx := logging.WithRequestID(ss.Context(), id)
+	tracing.SpanFromContext(ctx).SetAttribute("request_id", id)
+
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	logRPC(ctx, info.FullMethod, start, err)
+	return err
+}
+
+// isServerError сообщает, что код означает ошибку сервера, а не запроса.
+func isServerError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unimplemented:
+		return true
+	}
+	return false
+}
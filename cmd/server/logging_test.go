@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"calculator/internal/logging"
+)
+
+// Идентификатор из заголовка возвращается клиенту и попадает в журнал,
+// а недопустимый заменяется новым и в журнал не пишется.
+func TestLogHTTPRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{name: "client id", header: "req-42", keep: true},
+		{name: "missing", header: ""},
+		{name: "log injection", header: "x\n{\"level\":\"ERROR\"}"},
+		{name: "too long", header: strings.Repeat("x", 129)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			defer slog.SetDefault(slog.Default())
+			slog.SetDefault(logging.New(&logs, slog.LevelInfo))
+
+			var seen string
+			h := logHTTP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = logging.RequestID(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+			if tt.header != "" {
+				req.Header.Set(logging.HeaderRequestID, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(logging.HeaderRequestID)
+			if tt.keep != (id == tt.header) || id == "" {
+				t.Fatalf("response id = %q for header %q, keep = %v", id, tt.header, tt.keep)
+			}
+			if seen != id {
+				t.Fatalf("handler saw id %q, response has %q", seen, id)
+			}
+			var entry map[string]any
+			if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+				t.Fatalf("log line %q: %v", logs.String(), err)
+			}
+			if entry["request_id"] != id || entry["level"] != "INFO" {
+				t.Fatalf("log entry = %v, want request_id %q at INFO", entry, id)
+			}
+		})
+	}
+}
@@ -1,56 +1,207 @@
 package main
 
 import (
-	"context"
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
-	"sync"
+	"os"
+	"time"
 
+	"calculator/internal/batch"
+	"calculator/internal/config"
+	"calculator/internal/health"
+	"calculator/internal/jobs"
+	"calculator/internal/logging"
+	"calculator/internal/programs"
 	"calculator/internal/service"
+	"calculator/internal/session"
+	"calculator/internal/storage"
 )
 
-func startHTTPServer() {
-	http.HandleFunc("/calculate", func(w http.ResponseWriter, r *http.Request) {
-		var instructions []service.Instruction
-		if err := json.NewDecoder(r.Body).Decode(&instructions); err != nil {
-			http.Error(w, "Invalid request body", http.StatusBadRequest)
-			return
-		}
-
-		calc := service.NewCalculatorService()
-		results, err := calc.Run(context.Background(), instructions)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Execution error: %v", err), http.StatusInternalServerError)
-			return
-		}
-
-		response := struct {
-			Items []service.ResultItem `json:"items"`
-		}{Items: results}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(response)
-	})
+// components — долгоживущие компоненты, общие для HTTP и gRPC серверов.
+type components struct {
+	calculator *service.CalculatorService
+	sessions   *session.Manager
+	programs   *programs.Registry
+	batch      *batch.Runner
+	jobs       *jobs.Manager
+	health     *health.Checker
+	metrics    *serverMetrics
+	tracer     *serverTracer
+	auth       *serverAuth
+	authz      *serverAuthz
+	rateLimit  *serverRateLimit
+	limits     config.Limits
+	// reflection — схема сервиса открыта клиентам: gRPC reflection и
+	// GET /api/descriptor.
+	reflection bool
+	routes     *http.ServeMux
+	chain      chain
+}
 
-	log.Println("HTTP сервер запущен на :8081")
-	log.Fatal(http.ListenAndServe(":8081", nil))
+func openStore(cfg config.Storage) (storage.Store, error) {
+	if cfg.DataDir == "" {
+		return storage.NewMemoryStore(), nil
+	}
+	store, err := storage.OpenFileStore(cfg.DataDir, storage.FileOptions{
+		CompactEvery:    cfg.CompactEvery,
+		CompactInterval: time.Duration(cfg.CompactInterval),
+		NoSync:          cfg.NoSync,
+	})
+	if err != nil {
+		return nil, err
+	}
+	report := store.Recovery()
+	slog.Info("storage recovered", "data_dir", cfg.DataDir, "snapshot_keys", report.SnapshotKeys, "log_records", report.Records)
+	if report.Corruption != nil {
+		slog.Warn("discarded corrupted journal tail", "error", report.Corruption)
+	}
+	return store, nil
 }
 
 func main() {
-	var wg sync.WaitGroup
-	wg.Add(2)
+	os.Exit(run())
+}
+
+// run собирает компоненты, запускает серверы и после их остановки
+// освобождает ресурсы. Возвращает код завершения процесса.
+func run() int {
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitConfig
+	}
+	slog.SetDefault(logging.New(os.Stderr, cfg.LogLevel()))
+
+	store, err := openStore(cfg.Storage)
+	if err != nil {
+		slog.Error("failed to open storage", "error", err)
+		return exitServeError
+	}
+	defer store.Close()
+
+	tracer, err := newServerTracer(cfg.Tracing)
+	if err != nil {
+		slog.Error("failed to open trace exporter", "error", err)
+		return exitServeError
+	}
+	defer tracer.Close()
+
+	rateLimit, err := newServerRateLimit(cfg.RateLimit, store)
+	if err != nil {
+		slog.Error("failed to restore quotas", "error", err)
+		return exitServeError
+	}
+	// Квоты записываются до закрытия хранилища.
+	defer rateLimit.Close()
+
+	m := newServerMetrics()
+	calc := service.NewCalculatorService(
+		service.WithWorkers(cfg.Calculator.Workers),
+		service.WithLatency(time.Duration(cfg.Calculator.Latency)),
+		service.WithMetrics(m),
+		service.WithQuota(rateLimit.quota()),
+		service.WithLimits(service.Limits{
+			MaxInstructions:         cfg.Limits.MaxInstructions,
+			MaxNameLength:           cfg.Limits.MaxNameLength,
+			MaxDepth:                cfg.Limits.MaxDepth,
+			MaxFanIn:                cfg.Limits.MaxFanIn,
+			MaxValueBits:            cfg.Limits.MaxValueBits,
+			MaxEvaluationTime:       time.Duration(cfg.Limits.MaxEvaluationTime),
+			MaxInFlightExecutions:   cfg.Limits.MaxInFlightExecutions,
+			MaxInFlightInstructions: cfg.Limits.MaxInFlightInstructions,
+		}),
+	)
+	sessions, err := session.NewManager(calc, store, session.Config{
+		TTL:          time.Duration(cfg.Sessions.TTL),
+		MaxVariables: cfg.Sessions.MaxVariables,
+	})
+	if err != nil {
+		slog.Error("failed to restore sessions", "error", err)
+		return exitServeError
+	}
+	defer sessions.Close()
+
+	jobsCfg := jobs.DefaultConfig()
+	jobsCfg.Workers = cfg.Jobs.Workers
+	jobsCfg.QueueSize = cfg.Jobs.QueueSize
+	jobsCfg.Retention = time.Duration(cfg.Jobs.Retention)
+	jobsCfg.Webhook.AllowedHosts = cfg.Jobs.Webhook.AllowedHosts
+	jobsCfg.Webhook.AllowPrivateNetworks = cfg.Jobs.Webhook.AllowPrivateNetworks
+
+	c := &components{
+		calculator: calc,
+		sessions:   sessions,
+		programs:   programs.NewRegistry(calc, store),
+		batch:      batch.NewRunner(calc, cfg.Batch.Concurrency),
+		jobs:       jobs.NewManager(calc, jobsCfg),
+		metrics:    m,
+		tracer:     tracer,
+		rateLimit:  rateLimit,
+		limits:     cfg.Limits,
+		reflection: cfg.GRPC.Reflection,
+	}
+	// Задания, не завершившиеся к остановке серверов, отменяются без
+	// ожидания: выполняющиеся завершаются в состоянии canceled, а задания
+	// из очереди не запускаются.
+	defer c.jobs.Close()
+	c.health = newHealthChecker(c, store)
+	m.registerGauges(c)
+	c.auth, err = newServerAuth(cfg.Auth)
+	if err != nil {
+		slog.Error("failed to load authentication keys", "error", err)
+		return exitConfig
+	}
+	c.routes = (&httpServer{components: c}).routes()
+	c.authz, err = newServerAuthz(cfg.Auth, cfg.LogLevel(), c.routes)
+	if err != nil {
+		slog.Error("failed to load authorization policy", "error", err)
+		return exitConfig
+	}
+	defer c.authz.Close()
+	c.chain, err = newChain(c.middlewares(), cfg.Middleware.Order)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitConfig
+	}
 
-	go func() {
-		defer wg.Done()
-		startHTTPServer()
-	}()
+	httpCerts, err := newServerTLS(cfg.HTTP.TLS, "h2", "http/1.1")
+	if err != nil {
+		slog.Error("failed to load HTTP server certificates", "error", err)
+		return exitServeError
+	}
+	defer httpCerts.Close()
+	grpcCerts, err := newServerTLS(cfg.GRPC.TLS)
+	if err != nil {
+		slog.Error("failed to load gRPC server certificates", "error", err)
+		return exitServeError
+	}
+	defer grpcCerts.Close()
 
-	go func() {
-		defer wg.Done()
-		startGRPCServer()
-	}()
+	httpT, err := newHTTPTransport(c, cfg.HTTP, httpCerts)
+	if err != nil {
+		slog.Error("failed to start HTTP server", "error", err)
+		return exitServeError
+	}
+	grpcT, err := newGRPCTransport(c, cfg.GRPC, grpcCerts)
+	if err != nil {
+		httpT.ln.Close()
+		slog.Error("failed to start gRPC server", "error", err)
+		return exitServeError
+	}
 
-	wg.Wait()
+	l := &lifecycle{
+		transports: []transport{httpT, grpcT},
+		timeout:    time.Duration(cfg.Shutdown.Timeout),
+		delay:      time.Duration(cfg.Shutdown.Delay),
+		// До остановки приёма запросов сервер сообщает о неготовности,
+		// чтобы балансировщик перестал направлять на него трафик.
+		beforeShutdown: []func(){c.health.SetShuttingDown, grpcT.health.shutdown},
+	}
+	return l.run()
 }
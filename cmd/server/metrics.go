@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"calculator/internal/metrics"
+	"calculator/internal/ratelimit"
+	"calculator/internal/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// instructionBuckets — границы гистограммы числа инструкций в программе.
+var instructionBuckets = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000}
+
+// serverMetrics — метрики сервера. Реализует service.Metrics.
+type serverMetrics struct {
+	registry *metrics.Registry
+
+	requests        *metrics.CounterVec
+	requestDuration *metrics.HistogramVec
+	instructions    *metrics.HistogramVec
+	executions      *metrics.CounterVec
+	executionTime   *metrics.HistogramVec
+	operators       *metrics.CounterVec
+	dependencyWait  *metrics.HistogramVec
+}
+
+func newServerMetrics() *serverMetrics {
+	r := metrics.NewRegistry()
+	return &serverMetrics{
+		registry: r,
+		requests: r.NewCounterVec("calculator_requests_total",
+			"Requests by transport, method and status.", "transport", "method", "status"),
+		requestDuration: r.NewHistogramVec("calculator_request_duration_seconds",
+			"Request latency by transport, method and status.", metrics.DefaultBuckets, "transport", "method", "status"),
+		instructions: r.NewHistogramVec("calculator_execution_instructions",
+			"Instructions per executed program.", instructionBuckets),
+		executions: r.NewCounterVec("calculator_executions_total",
+			"Finished program executions by error type; error_type is \"none\" on success.", "error_type"),
+		executionTime: r.NewHistogramVec("calculator_execution_duration_seconds",
+			"Program execution time.", metrics.DefaultBuckets),
+		operators: r.NewCounterVec("calculator_operator_evaluations_total",
+			"Operator evaluations by operator and result.", "op", "result"),
+		dependencyWait: r.NewHistogramVec("calculator_dependency_wait_seconds",
+			"Time a variable waited for a variable operand.", metrics.DefaultBuckets),
+	}
+}
+
+// registerGauges добавляет измерители состояния компонентов.
+func (m *serverMetrics) registerGauges(c *components) {
+	m.registry.NewGaugeFunc("calculator_executions_in_flight",
+		"Programs being executed right now.", func() float64 {
+			return float64(c.calculator.InFlight())
+		})
+	m.registry.NewGaugeFunc("calculator_pool_busy",
+		"Variables being evaluated in the shared worker pool.", func() float64 {
+			busy, _ := c.calculator.PoolUsage()
+			return float64(busy)
+		})
+	m.registry.NewGaugeFunc("calculator_pool_size",
+		"Size of the shared worker pool.", func() float64 {
+			_, size := c.calculator.PoolUsage()
+			return float64(size)
+		})
+	m.registry.NewGaugeFunc("calculator_pool_queue_depth",
+		"Variables waiting for a free worker pool slot.", func() float64 {
+			return float64(c.calculator.PoolQueue())
+		})
+	m.registry.NewGaugeFunc("calculator_jobs_queued",
+		"Asynchronous jobs waiting in the queue.", func() float64 {
+			queued, _ := c.jobs.QueueUsage()
+			return float64(queued)
+		})
+}
+
+func (m *serverMetrics) ExecutionFinished(instructions int, elapsed time.Duration, err error) {
+	m.instructions.With().Observe(float64(instructions))
+	m.executionTime.With().Observe(elapsed.Seconds())
+	m.executions.With(errorType(err)).Inc()
+}
+
+func (m *serverMetrics) OperatorEvaluated(op string, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	m.operators.With(op, result).Inc()
+}
+
+func (m *serverMetrics) DependencyWaited(d time.Duration) {
+	m.dependencyWait.With().Observe(d.Seconds())
+}
+
+func (m *serverMetrics) observeRequest(transport, method, code string, elapsed time.Duration) {
+	m.requests.With(transport, method, code).Inc()
+	m.requestDuration.With(transport, method, code).Observe(elapsed.Seconds())
+}
+
+// errorType — тип ошибки выполнения для метрик. Превышение
+// ограничения программы помечается именем ограничения, например
+// "limit:max_depth".
+func errorType(err error) string {
+	var evalErr *service.EvalError
+	var limitErr *service.LimitExceededError
+	var rateErr *ratelimit.LimitError
+	switch {
+	case err == nil:
+		return "none"
+	case errors.As(err, &limitErr):
+		return "limit:" + limitErr.Limit
+	case errors.Is(err, service.ErrOverloaded):
+		return "overloaded"
+	case errors.As(err, &rateErr) && rateErr.Limit == ratelimit.LimitDailyInstructions:
+		return "quota_exceeded"
+	case errors.Is(err, ratelimit.ErrLimited):
+		return "rate_limited"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	case errors.Is(err, service.ErrInvalidProgram):
+		return "invalid_program"
+	case errors.Is(err, service.ErrInvalidInputs):
+		return "invalid_inputs"
+	case errors.Is(err, service.ErrUndefinedVariable):
+		return "undefined_variable"
+	case errors.Is(err, service.ErrAlreadyAssigned):
+		return "already_assigned"
+	case errors.As(err, &evalErr):
+		return "evaluation"
+	}
+	return "internal"
+}
+
+// instrumentHTTP считает запросы и их длительность. Метод запроса
+// определяется по шаблону маршрута, чтобы число рядов не зависело от
+// идентификаторов в путях.
+func (m *serverMetrics) instrumentHTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		method := r.Pattern
+		if method == "" {
+			method = "unmatched"
+		}
+		m.observeRequest("http", method, strconv.Itoa(rec.code()), time.Since(start))
+	})
+}
+
+// statusRecorder запоминает код ответа. Unwrap сохраняет доступ к
+// Flush и другим возможностям исходного ResponseWriter через
+// http.ResponseController.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusRecorder) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *statusRecorder) code() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (m *serverMetrics) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	m.observeRequest("grpc", info.FullMethod, status.Code(err).String(), time.Since(start))
+	return resp, err
+}
+
+func (m *serverMetrics) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	m.observeRequest("grpc", info.FullMethod, status.Code(err).String(), time.Since(start))
+	return err
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"calculator/internal/ratelimit"
+	"calculator/internal/service"
+)
+
+func TestErrorType(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"no error", nil, "none"},
+		{"depth limit", &service.LimitExceededError{Limit: service.LimitDepth}, "limit:max_depth"},
+		{"limit inside evaluation", &service.EvalError{Var: "x", Err: &service.LimitExceededError{Limit: service.LimitValueBits}}, "limit:max_value_bits"},
+		// Превышение времени выполнения приходит вместе с отменой контекста.
+		{"evaluation time", fmt.Errorf("%w: %w", context.DeadlineExceeded, &service.LimitExceededError{Limit: service.LimitEvaluationTime}), "limit:max_evaluation_time"},
+		{"overloaded", fmt.Errorf("%w: 3 executions in flight", service.ErrOverloaded), "overloaded"},
+		{"daily quota", &ratelimit.LimitError{Limit: ratelimit.LimitDailyInstructions}, "quota_exceeded"},
+		{"rate limit", &ratelimit.LimitError{Limit: ratelimit.LimitInstructions}, "rate_limited"},
+		{"canceled", context.Canceled, "canceled"},
+		{"deadline", fmt.Errorf("run: %w", context.DeadlineExceeded), "deadline_exceeded"},
+		{"invalid program", fmt.Errorf("%w: unknown op %q", service.ErrInvalidProgram, "/"), "invalid_program"},
+		{"invalid inputs", &service.InputError{Missing: []string{"n"}}, "invalid_inputs"},
+		{"undefined variable", &service.EvalError{Var: "y", Err: fmt.Errorf("%w: x", service.ErrUndefinedVariable)}, "undefined_variable"},
+		{"already assigned", fmt.Errorf("%w: x", service.ErrAlreadyAssigned), "already_assigned"},
+		{"evaluation", &service.EvalError{Var: "x", Err: errors.New("boom")}, "evaluation"},
+		{"unknown", errors.New("boom"), "internal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorType(tt.err); got != tt.want {
+				t.Errorf("errorType(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// middleware — сквозная обработка запросов, общая для HTTP и gRPC.
+type middleware struct {
+	http   func(http.Handler) http.Handler
+	unary  grpc.UnaryServerInterceptor
+	stream grpc.StreamServerInterceptor
+}
+
+// chain — промежуточные обработчики от внешнего к внутреннему.
+type chain []middleware
+
+// middlewares возвращает доступные промежуточные обработчики по именам,
+// используемым в middleware.order.
+func (c *components) middlewares() map[string]middleware {
+	return map[string]middleware{
+		"tracing":   {c.tracer.traceHTTP, c.tracer.unaryInterceptor, c.tracer.streamInterceptor},
+		"logging":   {logHTTP, logUnary, logStream},
+		"metrics":   {c.metrics.instrumentHTTP, c.metrics.unaryInterceptor, c.metrics.streamInterceptor},
+		"recovery":  {recoverHTTP, recoverUnary, recoverStream},
+		"auth":      {c.auth.authHTTP, c.auth.unaryInterceptor, c.auth.streamInterceptor},
+		"authz":     {c.authz.authorizeHTTP, c.authz.unaryInterceptor, c.authz.streamInterceptor},
+		"ratelimit": {c.rateLimit.limitHTTP, c.rateLimit.unaryInterceptor, c.rateLimit.streamInterceptor},
+		"limits":    newSizeLimit(c.limits.MaxRequestBytes).middleware(),
+	}
+}
+
+// newChain собирает цепочку в заданном порядке.
+func newChain(available map[string]middleware, order []string) (chain, error) {
+	ch := make(chain, 0, len(order))
+	for _, name := range order {
+		m, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("middleware.order: unknown middleware %q", name)
+		}
+		ch = append(ch, m)
+	}
+	return ch, nil
+}
+
+// wrapHTTP оборачивает маршрутизатор цепочкой. Шаблон маршрута
+// определяется до цепочки: ServeMux заполняет Pattern только в своей
+// копии запроса, а обработчики, которые меняют контекст, передают дальше
+// новую копию, и внешние обработчики (журнал, метрики, трассировка)
+// иначе не увидели бы маршрут.
+func (ch chain) wrapHTTP(routes *http.ServeMux) http.Handler {
+	var h http.Handler = routes
+	for i := len(ch) - 1; i >= 0; i-- {
+		h = ch[i].http(h)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, r.Pattern = routes.Handler(r)
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (ch chain) serverOptions() []grpc.ServerOption {
+	unary := make([]grpc.UnaryServerInterceptor, len(ch))
+	stream := make([]grpc.StreamServerInterceptor, len(ch))
+	for i, m := range ch {
+		unary[i], stream[i] = m.unary, m.stream
+	}
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+}
+
+// recoverHTTP превращает панику обработчика в ответ 500, если заголовки
+// ответа ещё не отправлены. http.ErrAbortHandler пропускается дальше.
+func recoverHTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			logPanic(r.Context(), v)
+			if rec.status == 0 {
+				http.Error(rec, "internal server error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+func recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			logPanic(ctx, v)
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoverStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			logPanic(ss.Context(), v)
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+	return handler(srv, ss)
+}
+
+func logPanic(ctx context.Context, v any) {
+	slog.ErrorContext(ctx, "panic while handling request", "panic", fmt.Sprint(v), "stack", string(debug.Stack()))
+}
+
+// sizeLimit ограничивает размер тела HTTP запроса и каждого входящего
+// сообщения gRPC.
+type sizeLimit struct {
+	max int
+}
+
+func newSizeLimit(max int) sizeLimit {
+	return sizeLimit{max: max}
+}
+
+func (l sizeLimit) middleware() middleware {
+	return middleware{l.http, l.unary, l.stream}
+}
+
+// http ограничивает тело запроса. Потоковые обработчики могут снять
+// ограничение вызовом unlimitBody.
+func (l sizeLimit) http(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = &limitedBody{ReadCloser: r.Body, remaining: int64(l.max), limit: int64(l.max)}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l sizeLimit) check(msg any) error {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return nil
+	}
+	if size := proto.Size(m); size > l.max {
+		return status.Errorf(codes.ResourceExhausted, "message of %d bytes exceeds limit of %d bytes", size, l.max)
+	}
+	return nil
+}
+
+func (l sizeLimit) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := l.check(req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (l sizeLimit) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &limitedStream{ServerStream: ss, limit: l})
+}
+
+type limitedStream struct {
+	grpc.ServerStream
+	limit sizeLimit
+}
+
+func (s *limitedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.limit.check(m)
+}
+
+// limitedBody — тело запроса с ограничением размера. При превышении
+// Read возвращает *http.MaxBytesError.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+	unlimited bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.unlimited {
+		return b.ReadCloser.Read(p)
+	}
+	if b.remaining < 0 {
+		return 0, &http.MaxBytesError{Limit: b.limit}
+	}
+	// Читаем на байт больше остатка, чтобы отличить тело ровно предельного
+	// размера от превышающего.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		n += int(b.remaining)
+		return n, &http.MaxBytesError{Limit: b.limit}
+	}
+	return n, err
+}
+
+// unlimitBody снимает ограничение размера тела для потоковых
+// обработчиков, которые читают тело по частям.
+func unlimitBody(r *http.Request) {
+	if b, ok := r.Body.(*limitedBody); ok {
+		b.unlimited = true
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+
+	"calculator/internal/auth"
+	"calculator/internal/config"
+	"calculator/internal/ratelimit"
+	"calculator/internal/service"
+	"calculator/internal/storage"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Заголовки ответа с остатком ограничений клиента. В gRPC те же имена
+// передаются метаданными в нижнем регистре.
+const (
+	headerRateLimit      = "X-RateLimit-Limit"
+	headerRateRemaining  = "X-RateLimit-Remaining"
+	headerQuotaLimit     = "X-Quota-Limit"
+	headerQuotaRemaining = "X-Quota-Remaining"
+	headerQuotaReset     = "X-Quota-Reset"
+)
+
+// serverRateLimit ограничивает запросы и вычисления каждого клиента. nil
+// означает, что ограничения не заданы.
+type serverRateLimit struct {
+	limiter *ratelimit.Limiter
+}
+
+func newServerRateLimit(cfg config.RateLimit, store storage.Store) (*serverRateLimit, error) {
+	rl := ratelimit.Config{
+		RequestsPerSecond:     float64(cfg.RequestsPerSecond),
+		RequestBurst:          cfg.RequestBurst,
+		InstructionsPerSecond: float64(cfg.InstructionsPerSecond),
+		InstructionBurst:      cfg.InstructionBurst,
+		DailyInstructions:     int64(cfg.DailyInstructions),
+	}
+	if !rl.Enabled() {
+		return nil, nil
+	}
+	limiter, err := ratelimit.New(rl, store)
+	if err != nil {
+		return nil, err
+	}
+	return &serverRateLimit{limiter: limiter}, nil
+}
+
+// Close записывает несохранённый расход квот.
+func (r *serverRateLimit) Close() error {
+	if r == nil {
+		return nil
+	}
+	return r.limiter.Close()
+}
+
+// quota возвращает учёт инструкций для калькулятора.
+func (r *serverRateLimit) quota() service.Quota {
+	if r == nil {
+		return nil
+	}
+	return r.limiter
+}
+
+// clientKey определяет клиента: по Identity.Key, если запрос прошёл
+// аутентификацию, иначе по адресу без порта. Ключи клиентов разных
+// способов проверки с одинаковым Subject не совпадают.
+func clientKey(ctx context.Context, remote string) string {
+	if id, ok := auth.FromContext(ctx); ok {
+		return id.Key()
+	}
+	host, _, err := net.SplitHostPort(remote)
+	if err != nil {
+		host = remote
+	}
+	return "addr:" + host
+}
+
+// headers возвращает заголовки с остатком ограничений клиента.
+func (r *serverRateLimit) headers(key string) [][2]string {
+	st := r.limiter.Status(key)
+	var h [][2]string
+	if st.RequestLimit > 0 {
+		h = append(h,
+			[2]string{headerRateLimit, strconv.FormatInt(st.RequestLimit, 10)},
+			[2]string{headerRateRemaining, strconv.FormatInt(st.RequestsRemaining, 10)},
+		)
+	}
+	if st.QuotaLimit > 0 {
+		h = append(h,
+			[2]string{headerQuotaLimit, strconv.FormatInt(st.QuotaLimit, 10)},
+			[2]string{headerQuotaRemaining, strconv.FormatInt(st.QuotaRemaining, 10)},
+			[2]string{headerQuotaReset, strconv.FormatInt(st.QuotaReset.Unix(), 10)},
+		)
+	}
+	return h
+}
+
+func (r *serverRateLimit) setHeaders(h http.Header, key string) {
+	for _, kv := range r.headers(key) {
+		h.Set(kv[0], kv[1])
+	}
+}
+
+func (r *serverRateLimit) metadata(key string) metadata.MD {
+	md := metadata.MD{}
+	for _, kv := range r.headers(key) {
+		md.Set(kv[0], kv[1])
+	}
+	return md
+}
+
+func (r *serverRateLimit) limitHTTP(next http.Handler) http.Handler {
+	if r == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet && publicHTTP[req.URL.Path] {
+			next.ServeHTTP(w, req)
+			return
+		}
+		key := clientKey(req.Context(), req.RemoteAddr)
+		if err := r.limiter.Allow(key); err != nil {
+			r.setHeaders(w.Header(), key)
+			writeError(w, err)
+			return
+		}
+		// Остаток квоты вычисляется при отправке заголовков, чтобы учесть
+		// инструкции этого запроса.
+		qw := &quotaWriter{ResponseWriter: w, set: func(h http.Header) { r.setHeaders(h, key) }}
+		next.ServeHTTP(qw, req.WithContext(ratelimit.WithClient(req.Context(), key)))
+	})
+}
+
+// quotaWriter добавляет заголовки с остатком ограничений перед первой
+// записью ответа.
+type quotaWriter struct {
+	http.ResponseWriter
+	set  func(http.Header)
+	done bool
+}
+
+func (w *quotaWriter) setHeaders() {
+	if !w.done {
+		w.done = true
+		w.set(w.Header())
+	}
+}
+
+func (w *quotaWriter) WriteHeader(code int) {
+	w.setHeaders()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *quotaWriter) Write(p []byte) (int, error) {
+	w.setHeaders()
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *quotaWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (r *serverRateLimit) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if r == nil || publicRPC(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	key := clientKey(ctx, peerAddr(ctx))
+	if err := r.limiter.Allow(key); err != nil {
+		grpc.SetHeader(ctx, r.metadata(key))
+		return nil, grpcError(err)
+	}
+	resp, err := handler(ratelimit.WithClient(ctx, key), req)
+	grpc.SetHeader(ctx, r.metadata(key))
+	return resp, err
+}
+
+func (r *serverRateLimit) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if r == nil || publicRPC(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	ctx := ss.Context()
+	key := clientKey(ctx, peerAddr(ctx))
+	if err := r.limiter.Allow(key); err != nil {
+		ss.SetHeader(r.metadata(key))
+		return grpcError(err)
+	}
+	// Заголовки потока могут уйти раньше, чем будут списаны инструкции,
+	// поэтому остаток передаётся в трейлере.
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ratelimit.WithClient(ctx, key)})
+	ss.SetTrailer(r.metadata(key))
+	return err
+}
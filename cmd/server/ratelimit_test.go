@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"calculator/internal/auth"
+	"calculator/internal/config"
+	"calculator/internal/storage"
+)
+
+func TestLimitHTTP(t *testing.T) {
+	c := newTestComponents(t)
+	rl, err := newServerRateLimit(config.RateLimit{RequestsPerSecond: 1, RequestBurst: 2}, storage.NewMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { rl.Close() })
+	c.rateLimit = rl
+	useChain(t, c, "metrics", "ratelimit")
+	url := startHTTP(t, c)
+
+	for i, want := range []int{http.StatusNotFound, http.StatusNotFound, http.StatusTooManyRequests} {
+		resp, err := http.Get(url + "/sessions/x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("request %d: status = %d, want %d", i+1, resp.StatusCode, want)
+		}
+		if resp.Header.Get(headerRateLimit) != "2" {
+			t.Fatalf("request %d: %s = %q", i+1, headerRateLimit, resp.Header.Get(headerRateLimit))
+		}
+		if want == http.StatusTooManyRequests && resp.Header.Get("Retry-After") != "1" {
+			t.Fatalf("Retry-After = %q, want 1", resp.Header.Get("Retry-After"))
+		}
+	}
+	// Пробы балансировщика не ограничиваются.
+	resp, err := http.Get(url + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("/healthz: status = %d", resp.StatusCode)
+	}
+
+	// Метрики снаружи ratelimit видят маршрут запросов, прошедших дальше.
+	rec := httptest.NewRecorder()
+	c.metrics.registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `method="GET /sessions/{id}",status="404"`) {
+		t.Errorf("metrics do not contain the route:\n%s", rec.Body.String())
+	}
+}
+
+// Клиенты разных способов проверки с одинаковым Subject считаются
+// отдельно: сертификат с CN "ci" не расходует лимит API ключа "ci".
+func TestClientKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     *auth.Identity
+		remote string
+		want   string
+	}{
+		{name: "api key", id: &auth.Identity{Subject: "ci", Method: "api_key"}, remote: "10.0.0.1:5000", want: "api_key:ci"},
+		{name: "jwt", id: &auth.Identity{Subject: "ci", Method: "jwt"}, remote: "10.0.0.1:5000", want: "jwt:ci"},
+		{name: "certificate", id: &auth.Identity{Subject: "ci", Method: "mtls"}, remote: "10.0.0.1:5000", want: "mtls:ci"},
+		{name: "anonymous", remote: "10.0.0.1:5000", want: "addr:10.0.0.1"},
+		{name: "address without port", remote: "10.0.0.1", want: "addr:10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.id != nil {
+				ctx = auth.WithIdentity(ctx, *tt.id)
+			}
+			if got := clientKey(ctx, tt.remote); got != tt.want {
+				t.Fatalf("clientKey = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"calculator/internal/batch"
+	"calculator/internal/config"
+	"calculator/internal/jobs"
+	"calculator/internal/pb"
+	"calculator/internal/programs"
+	"calculator/internal/service"
+	"calculator/internal/session"
+	"calculator/internal/storage"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestComponents собирает компоненты сервера на хранилище в памяти без
+// аутентификации и ограничений частоты.
+func newTestComponents(t *testing.T, opts ...service.Option) *components {
+	t.Helper()
+	store := storage.NewMemoryStore()
+	calc := service.NewCalculatorService(append([]service.Option{service.WithLatency(time.Millisecond)}, opts...)...)
+	sessions, err := session.NewManager(calc, store, session.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(sessions.Close)
+	c := &components{
+		calculator: calc,
+		sessions:   sessions,
+		programs:   programs.NewRegistry(calc, store),
+		batch:      batch.NewRunner(calc, 4),
+		jobs:       jobs.NewManager(calc, jobs.DefaultConfig()),
+		metrics:    newServerMetrics(),
+		limits:     config.Default().Limits,
+	}
+	t.Cleanup(c.jobs.Close)
+	c.health = newHealthChecker(c, store)
+	c.routes = (&httpServer{components: c}).routes()
+	return c
+}
+
+// useChain собирает цепочку промежуточных обработчиков компонентов.
+func useChain(t *testing.T, c *components, order ...string) {
+	t.Helper()
+	ch, err := newChain(c.middlewares(), order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.chain = ch
+}
+
+// startHTTP запускает HTTP сервер компонентов и возвращает его адрес.
+func startHTTP(t *testing.T, c *components) string {
+	t.Helper()
+	srv := httptest.NewServer(c.chain.wrapHTTP(c.routes))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// startGRPC запускает gRPC сервер компонентов в памяти и возвращает
+// подключённого к нему клиента.
+func startGRPC(t *testing.T, c *components) pb.CalculatorServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(c.chain.serverOptions()...)
+	pb.RegisterCalculatorServiceServer(srv, &grpcServer{components: c})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewCalculatorServiceClient(conn)
+}
+
+// Одновременные вызовы по HTTP и gRPC присваивают одни и те же имена
+// переменных и должны видеть только свои значения.
+func TestCalculateIsolation(t *testing.T) {
+	c := newTestComponents(t)
+	url := startHTTP(t, c)
+	client := startGRPC(t, c)
+
+	const calls = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*calls)
+	for i := int64(1); i <= calls; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errs <- calculateHTTP(url, i)
+		}()
+		go func() {
+			defer wg.Done()
+			errs <- calculateGRPC(client, -i)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// calculateHTTP вычисляет x = n, y = x * 2 и проверяет результат.
+func calculateHTTP(url string, n int64) error {
+	program := []service.Instruction{
+		{Type: "calc", Op: "+", Var: "x", Left: n, Right: int64(0)},
+		{Type: "calc", Op: "*", Var: "y", Left: "x", Right: int64(2)},
+		{Type: "print", Var: "x"},
+		{Type: "print", Var: "y"},
+	}
+	payload, err := json.Marshal(program)
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(url+"/calculate", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d: status %s", n, resp.Status)
+	}
+	var out calculateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return err
+	}
+	want := []service.ResultItem{{Var: "x", Value: n}, {Var: "y", Value: 2 * n}}
+	if fmt.Sprint(out.Items) != fmt.Sprint(want) {
+		return fmt.Errorf("HTTP %d: got %v, want %v", n, out.Items, want)
+	}
+	return nil
+}
+
+func calculateGRPC(client pb.CalculatorServiceClient, n int64) error {
+	resp, err := client.Calculate(context.Background(), &pb.CalculateRequest{Instructions: []*pb.Instruction{
+		{Type: "calc", Op: "+", Var: "x", LeftType: &pb.Instruction_LeftInt{LeftInt: n}, RightType: &pb.Instruction_RightInt{RightInt: 0}},
+		{Type: "calc", Op: "*", Var: "y", LeftType: &pb.Instruction_LeftVar{LeftVar: "x"}, RightType: &pb.Instruction_RightInt{RightInt: 2}},
+		{Type: "print", Var: "x"},
+		{Type: "print", Var: "y"},
+	}})
+	if err != nil {
+		return fmt.Errorf("gRPC %d: %w", n, err)
+	}
+	got := make([]service.ResultItem, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		got = append(got, service.ResultItem{Var: item.Var, Value: item.Value})
+	}
+	want := []service.ResultItem{{Var: "x", Value: n}, {Var: "y", Value: 2 * n}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		return fmt.Errorf("gRPC %d: got %v, want %v", n, got, want)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"time"
+
+	"calculator/internal/certs"
+	"calculator/internal/config"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// newServerTLS загружает сертификаты слушателя. nil означает работу без
+// TLS.
+func newServerTLS(cfg config.TLS, nextProtos ...string) (*certs.Reloader, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	return certs.NewReloader(certs.Config{
+		CertFile:          cfg.CertFile,
+		KeyFile:           cfg.KeyFile,
+		ClientCAFile:      cfg.ClientCAFile,
+		RequireClientCert: cfg.RequireClientCert,
+		ReloadInterval:    time.Duration(cfg.ReloadInterval),
+		NextProtos:        nextProtos,
+	})
+}
+
+// verifiedClientCert возвращает сертификат клиента, прошедший проверку
+// при рукопожатии, или nil.
+func verifiedClientCert(state *tls.ConnectionState) *x509.Certificate {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return state.VerifiedChains[0][0]
+}
+
+// rpcClientCert возвращает проверенный сертификат клиента gRPC вызова.
+func rpcClientCert(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil
+	}
+	return verifiedClientCert(&info.State)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+
+	"calculator/internal/config"
+	"calculator/internal/tracing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// traceparentHeader — заголовок и ключ метаданных W3C Trace Context.
+const traceparentHeader = "traceparent"
+
+// serverTracer создаёт корневые спаны запросов. nil означает, что
+// трассировка выключена.
+type serverTracer struct {
+	tracer   *tracing.Tracer
+	exporter tracing.Exporter
+}
+
+func newServerTracer(cfg config.Tracing) (*serverTracer, error) {
+	var exporter tracing.Exporter
+	switch cfg.Exporter {
+	case "stdout":
+		exporter = tracing.NewWriterExporter(os.Stdout)
+	case "file":
+		e, err := tracing.NewFileExporter(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		exporter = e
+	default:
+		return nil, nil
+	}
+	return &serverTracer{tracer: tracing.NewTracer(exporter), exporter: exporter}, nil
+}
+
+func (t *serverTracer) Close() error {
+	if t == nil {
+		return nil
+	}
+	return t.exporter.Close()
+}
+
+// start начинает спан запроса, продолжая трассу клиента, если
+// traceparent корректен; некорректный заголовок игнорируется.
+func (t *serverTracer) start(ctx context.Context, traceparent, name string) (context.Context, *tracing.Span) {
+	if sc, err := tracing.ParseTraceparent(traceparent); err == nil {
+		ctx = tracing.ContextWithRemoteParent(ctx, sc)
+	}
+	return t.tracer.Start(ctx, name)
+}
+
+// traceHTTP начинает спан на каждый HTTP запрос. Имя спана — шаблон
+// маршрута, известный только после маршрутизации.
+func (t *serverTracer) traceHTTP(next http.Handler) http.Handler {
+	if t == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := t.start(r.Context(), r.Header.Get(traceparentHeader), "HTTP "+r.Method)
+		defer span.End()
+		span.SetAttribute("http.method", r.Method)
+		span.SetAttribute("http.target", r.URL.Path)
+
+		rec := &statusRecorder{ResponseWriter: w}
+		r = r.WithContext(ctx)
+		next.ServeHTTP(rec, r)
+
+		if r.Pattern != "" {
+			span.SetName(r.Pattern)
+		}
+		span.SetAttribute("http.status_code", rec.code())
+		if rec.code() >= http.StatusInternalServerError {
+			span.RecordError(errorStatus(rec.code()))
+		}
+	})
+}
+
+type errorStatus int
+
+func (e errorStatus) Error() string {
+	return strconv.Itoa(int(e)) + " " + http.StatusText(int(e))
+}
+
+func (t *serverTracer) startRPC(ctx context.Context, method string) (context.Context, *tracing.Span) {
+	var traceparent string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(traceparentHeader); len(v) > 0 {
+			traceparent = v[0]
+		}
+	}
+	ctx, span := t.start(ctx, traceparent, method)
+	span.SetAttribute("rpc.method", method)
+	return ctx, span
+}
+
+func endRPC(span *tracing.Span, err error) {
+	span.SetAttribute("rpc.grpc.status_code", status.Code(err).String())
+	span.RecordError(err)
+	span.End()
+}
+
+func (t *serverTracer) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if t == nil {
+		return handler(ctx, req)
+	}
+	ctx, span := t.startRPC(ctx, info.FullMethod)
+	resp, err := handler(ctx, req)
+	endRPC(span, err)
+	return resp, err
+}
+
+func (t *serverTracer) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if t == nil {
+		return handler(srv, ss)
+	}
+	ctx, span := t.startRPC(ss.Context(), info.FullMethod)
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	endRPC(span, err)
+	return err
+}
+
+// contextStream подменяет контекст потока, чтобы обработчик видел
+// значения, добавленные перехватчиком.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"calculator/internal/tracing"
+)
+
+// Корректный traceparent продолжает трассу клиента, некорректный —
+// в том числе с заглавными цифрами — начинает новую.
+func TestTracerStart(t *testing.T) {
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	tests := []struct {
+		name      string
+		header    string
+		continued bool
+	}{
+		{name: "valid", header: "00-" + traceID + "-00f067aa0ba902b7-01", continued: true},
+		{name: "missing", header: ""},
+		{name: "uppercase", header: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-01"},
+		{name: "zero trace id", header: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+	}
+	tr := &serverTracer{tracer: tracing.NewTracer(nil)}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, span := tr.start(context.Background(), tt.header, "HTTP POST")
+			sc := span.SpanContext()
+			if !sc.IsValid() {
+				t.Fatalf("span context %+v is not valid", sc)
+			}
+			if got := sc.TraceID.String() == traceID; got != tt.continued {
+				t.Fatalf("trace id %s, continued = %v, want %v", sc.TraceID, got, tt.continued)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// APIKey — запись файла ключей. Сам ключ в файле не хранится, только
+// его SHA-256 в шестнадцатеричном виде.
+type APIKey struct {
+	ID     string   `yaml:"id"`
+	SHA256 string   `yaml:"sha256"`
+	Roles  []string `yaml:"roles"`
+}
+
+type apiKeysFile struct {
+	Keys []APIKey `yaml:"keys"`
+}
+
+// APIKeys проверяет статические ключи. Ключ передаётся схемой ApiKey
+// или Bearer, если значение не похоже на JWT.
+type APIKeys struct {
+	byHash map[[sha256.Size]byte]APIKey
+}
+
+// LoadAPIKeys читает файл ключей в формате YAML:
+//
+//	keys:
+//	  - id: ci
+//	    sha256: <hex SHA-256 ключа>
+//	    roles: [operator]
+func LoadAPIKeys(path string) (*APIKeys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read api keys: %w", err)
+	}
+	var file apiKeysFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("parse api keys %s: %w", path, err)
+	}
+	return NewAPIKeys(file.Keys)
+}
+
+func NewAPIKeys(keys []APIKey) (*APIKeys, error) {
+	a := &APIKeys{byHash: make(map[[sha256.Size]byte]APIKey, len(keys))}
+	ids := make(map[string]bool, len(keys))
+	for i, k := range keys {
+		if k.ID == "" {
+			return nil, fmt.Errorf("api key %d: id is required", i+1)
+		}
+		if ids[k.ID] {
+			return nil, fmt.Errorf("api key %s: duplicate id", k.ID)
+		}
+		ids[k.ID] = true
+		raw, err := hex.DecodeString(strings.TrimSpace(k.SHA256))
+		if err != nil || len(raw) != sha256.Size {
+			return nil, fmt.Errorf("api key %s: sha256 must be %d hex characters", k.ID, 2*sha256.Size)
+		}
+		var sum [sha256.Size]byte
+		copy(sum[:], raw)
+		if _, ok := a.byHash[sum]; ok {
+			return nil, fmt.Errorf("api key %s: duplicate key hash", k.ID)
+		}
+		a.byHash[sum] = k
+	}
+	return a, nil
+}
+
+func (a *APIKeys) Authenticate(ctx context.Context, cred Credentials) (Identity, error) {
+	switch {
+	case cred.Scheme == SchemeAPIKey:
+	case cred.Scheme == SchemeBearer && !looksLikeJWT(cred.Value):
+	default:
+		return Identity{}, errUnsupported
+	}
+	// Ключи ищутся по хешу, поэтому время поиска не зависит от того,
+	// насколько присланный ключ похож на настоящий.
+	k, ok := a.byHash[sha256.Sum256([]byte(cred.Value))]
+	if !ok {
+		return Identity{}, fmt.Errorf("%w: unknown api key", ErrUnauthenticated)
+	}
+	return Identity{Subject: k.ID, Method: "api_key", Roles: k.Roles}, nil
+}
+
+func looksLikeJWT(s string) bool {
+	return strings.Count(s, ".") == 2
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func keyHash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAPIKeysAuthenticate(t *testing.T) {
+	keys, err := NewAPIKeys([]APIKey{
+		{ID: "ci", SHA256: keyHash("ci-secret"), Roles: []string{"operator"}},
+		{ID: "viewer", SHA256: keyHash("viewer-secret")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		header      string
+		wantSubject string
+		wantErr     error
+	}{
+		{name: "api key scheme", header: "ApiKey ci-secret", wantSubject: "ci"},
+		{name: "scheme case", header: "apikey viewer-secret", wantSubject: "viewer"},
+		{name: "bearer", header: "Bearer ci-secret", wantSubject: "ci"},
+		{name: "unknown key", header: "ApiKey nope", wantErr: ErrUnauthenticated},
+		{name: "jwt-looking bearer", header: "Bearer a.b.c", wantErr: errUnsupported},
+		{name: "basic", header: "Basic Y2k6c2VjcmV0", wantErr: errUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cred, err := ParseAuthorization(tt.header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			id, err := keys.Authenticate(context.Background(), cred)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Authenticate = %+v, %v; want %v", id, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id.Subject != tt.wantSubject || id.Method != "api_key" {
+				t.Fatalf("identity = %+v", id)
+			}
+		})
+	}
+}
+
+func TestNewAPIKeysRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []APIKey
+	}{
+		{name: "no id", keys: []APIKey{{SHA256: keyHash("a")}}},
+		{name: "duplicate id", keys: []APIKey{{ID: "a", SHA256: keyHash("a")}, {ID: "a", SHA256: keyHash("b")}}},
+		{name: "duplicate hash", keys: []APIKey{{ID: "a", SHA256: keyHash("a")}, {ID: "b", SHA256: keyHash("a")}}},
+		{name: "short hash", keys: []APIKey{{ID: "a", SHA256: "abcd"}}},
+		{name: "not hex", keys: []APIKey{{ID: "a", SHA256: "zz"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAPIKeys(tt.keys); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestChainAuthenticate(t *testing.T) {
+	keys, err := NewAPIKeys([]APIKey{{ID: "ci", SHA256: keyHash("ci-secret")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := Chain{NewJWT(JWTConfig{HS256Secret: testSecret}), keys}
+	id, err := chain.Authenticate(context.Background(), Credentials{Scheme: SchemeBearer, Value: "ci-secret"})
+	if err != nil || id.Subject != "ci" {
+		t.Fatalf("Authenticate = %+v, %v", id, err)
+	}
+	_, err = chain.Authenticate(context.Background(), Credentials{Scheme: "Basic", Value: "x"})
+	if !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("got %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestParseAuthorization(t *testing.T) {
+	for _, header := range []string{"", "Bearer", "Bearer   "} {
+		if _, err := ParseAuthorization(header); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("ParseAuthorization(%q) = %v, want ErrUnauthenticated", header, err)
+		}
+	}
+}
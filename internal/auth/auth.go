@@ -0,0 +1,101 @@
+// Package auth проверяет учётные данные клиентов: статические API ключи,
+// JWT и сертификаты клиентов mTLS. Личность проверенного клиента хранится в контексте запроса.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrUnauthenticated — учётные данные отсутствуют или неверны.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// errUnsupported сообщает, что способ проверки не принимает такие
+	// учётные данные и их следует передать следующему.
+	errUnsupported = errors.New("unsupported credentials")
+)
+
+// Identity — проверенная личность клиента.
+type Identity struct {
+	// Subject — идентификатор клиента: имя ключа, sub из JWT или имя
+	// субъекта сертификата.
+	Subject string
+	// Method — способ проверки: api_key, jwt или mtls.
+	Method string
+	Roles  []string
+}
+
+// Key возвращает идентификатор клиента с префиксом способа проверки,
+// например "api_key:ci", "jwt:alice" или "mtls:reporting.internal".
+// Префикс не даёт клиенту одного способа получить роли или лимиты
+// клиента другого способа с тем же Subject.
+func (id Identity) Key() string {
+	return id.Method + ":" + id.Subject
+}
+
+type identityKey struct{}
+
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext возвращает личность клиента, если запрос прошёл проверку.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// Credentials — учётные данные из заголовка Authorization или
+// метаданных gRPC вида "<scheme> <value>".
+type Credentials struct {
+	Scheme string
+	Value  string
+}
+
+// ParseAuthorization разбирает значение заголовка Authorization. Схема
+// сравнивается без учёта регистра и приводится к каноническому виду.
+func ParseAuthorization(header string) (Credentials, error) {
+	if header == "" {
+		return Credentials{}, fmt.Errorf("%w: missing credentials", ErrUnauthenticated)
+	}
+	scheme, value, ok := strings.Cut(strings.TrimSpace(header), " ")
+	value = strings.TrimSpace(value)
+	if !ok || value == "" {
+		return Credentials{}, fmt.Errorf("%w: malformed authorization header", ErrUnauthenticated)
+	}
+	switch {
+	case strings.EqualFold(scheme, SchemeBearer):
+		scheme = SchemeBearer
+	case strings.EqualFold(scheme, SchemeAPIKey):
+		scheme = SchemeAPIKey
+	}
+	return Credentials{Scheme: scheme, Value: value}, nil
+}
+
+// Схемы заголовка Authorization.
+const (
+	SchemeBearer = "Bearer"
+	SchemeAPIKey = "ApiKey"
+)
+
+// Authenticator — способ проверки учётных данных. Если способ не
+// принимает такие данные, он возвращает errUnsupported.
+type Authenticator interface {
+	Authenticate(ctx context.Context, cred Credentials) (Identity, error)
+}
+
+// Chain проверяет учётные данные первым способом, который их принимает.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(ctx context.Context, cred Credentials) (Identity, error) {
+	for _, a := range c {
+		id, err := a.Authenticate(ctx, cred)
+		if errors.Is(err, errUnsupported) {
+			continue
+		}
+		return id, err
+	}
+	return Identity{}, fmt.Errorf("%w: unsupported authorization scheme %q", ErrUnauthenticated, cred.Scheme)
+}
@@ -0,0 +1,27 @@
+package auth
+
+import "crypto/x509"
+
+// CertificateIdentity возвращает личность клиента по сертификату,
+// проверенному при рукопожатии TLS. Subject — CommonName субъекта, а
+// если его нет — первое из альтернативных имён (URI, DNS, email) или
+// полное имя субъекта вида "CN=...,O=...". Роли клиенту назначает
+// политика по subjects с ключом "mtls:<Subject>", см. Identity.Key, так
+// что сертификат не получает роли API ключа или JWT с тем же именем.
+func CertificateIdentity(cert *x509.Certificate) Identity {
+	return Identity{Subject: certificateSubject(cert), Method: "mtls"}
+}
+
+func certificateSubject(cert *x509.Certificate) string {
+	switch {
+	case cert.Subject.CommonName != "":
+		return cert.Subject.CommonName
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String()
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0]
+	case len(cert.EmailAddresses) > 0:
+		return cert.EmailAddresses[0]
+	}
+	return cert.Subject.String()
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+)
+
+func TestCertificateIdentity(t *testing.T) {
+	spiffe, err := url.Parse("spiffe://example.org/reporting")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		cert x509.Certificate
+		want string
+	}{
+		{
+			name: "common name",
+			cert: x509.Certificate{
+				Subject:  pkix.Name{CommonName: "reporting.internal", Organization: []string{"Example"}},
+				DNSNames: []string{"reporting.example.org"},
+			},
+			want: "mtls:reporting.internal",
+		},
+		{
+			name: "uri san",
+			cert: x509.Certificate{URIs: []*url.URL{spiffe}, DNSNames: []string{"reporting.example.org"}},
+			want: "mtls:spiffe://example.org/reporting",
+		},
+		{
+			name: "dns san",
+			cert: x509.Certificate{DNSNames: []string{"reporting.example.org", "reporting"}},
+			want: "mtls:reporting.example.org",
+		},
+		{
+			name: "email san",
+			cert: x509.Certificate{EmailAddresses: []string{"ops@example.org"}},
+			want: "mtls:ops@example.org",
+		},
+		{
+			name: "subject without common name",
+			cert: x509.Certificate{Subject: pkix.Name{Organization: []string{"Example"}, OrganizationalUnit: []string{"Ops"}}},
+			want: "mtls:OU=Ops,O=Example",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := CertificateIdentity(&tt.cert)
+			if id.Method != "mtls" || len(id.Roles) != 0 {
+				t.Fatalf("identity = %+v, want method mtls without roles", id)
+			}
+			if got := id.Key(); got != tt.want {
+				t.Fatalf("Key() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
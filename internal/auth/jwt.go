@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// clockSkew — допустимое расхождение часов при проверке exp и nbf.
+const clockSkew = 30 * time.Second
+
+// JWTConfig — ключи и ожидаемые значения полей токена. Для HS256
+// используется общий секрет, для RS256 — открытый ключ RSA; алгоритм
+// без настроенного ключа не принимается.
+type JWTConfig struct {
+	HS256Secret    []byte
+	RS256PublicKey *rsa.PublicKey
+	// Issuer и Audience, если заданы, должны совпадать с iss и aud.
+	Issuer   string
+	Audience string
+}
+
+// LoadHS256Secret читает секрет HS256 из файла; пробельные символы по
+// краям отбрасываются.
+func LoadHS256Secret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwt secret: %w", err)
+	}
+	secret := []byte(strings.TrimSpace(string(data)))
+	if len(secret) < 32 {
+		return nil, fmt.Errorf("jwt secret %s: must be at least 32 bytes", path)
+	}
+	return secret, nil
+}
+
+// LoadRS256PublicKey читает открытый ключ RSA в формате PEM (PKIX или
+// PKCS #1).
+func LoadRS256PublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwt public key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwt public key %s: no PEM block", path)
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("jwt public key %s: %w", path, err)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("jwt public key %s: not an RSA key", path)
+	}
+	return key, nil
+}
+
+// JWT проверяет токены, переданные схемой Bearer.
+type JWT struct {
+	cfg JWTConfig
+	now func() time.Time
+}
+
+func NewJWT(cfg JWTConfig) *JWT {
+	return &JWT{cfg: cfg, now: time.Now}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+type jwtClaims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *float64 `json:"exp"`
+	NotBefore *float64 `json:"nbf"`
+	Roles     []string `json:"roles"`
+}
+
+// audience — поле aud: строка или массив строк.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return errors.New("aud must be a string or an array of strings")
+	}
+	*a = many
+	return nil
+}
+
+func (j *JWT) Authenticate(ctx context.Context, cred Credentials) (Identity, error) {
+	if cred.Scheme != SchemeBearer || !looksLikeJWT(cred.Value) {
+		return Identity{}, errUnsupported
+	}
+	claims, err := j.verify(cred.Value)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: invalid token: %v", ErrUnauthenticated, err)
+	}
+	return Identity{Subject: claims.Subject, Method: "jwt", Roles: claims.Roles}, nil
+}
+
+func (j *JWT) verify(token string) (jwtClaims, error) {
+	var claims jwtClaims
+	parts := strings.Split(token, ".")
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return claims, fmt.Errorf("header: %v", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return claims, errors.New("malformed signature")
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+
+	switch header.Alg {
+	case "HS256":
+		if j.cfg.HS256Secret == nil {
+			return claims, errors.New("HS256 tokens are not accepted")
+		}
+		mac := hmac.New(sha256.New, j.cfg.HS256Secret)
+		mac.Write(signed)
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return claims, errors.New("signature mismatch")
+		}
+	case "RS256":
+		if j.cfg.RS256PublicKey == nil {
+			return claims, errors.New("RS256 tokens are not accepted")
+		}
+		sum := sha256.Sum256(signed)
+		if rsa.VerifyPKCS1v15(j.cfg.RS256PublicKey, crypto.SHA256, sum[:], sig) != nil {
+			return claims, errors.New("signature mismatch")
+		}
+	default:
+		return claims, fmt.Errorf("unsupported algorithm %q", header.Alg)
+	}
+
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return claims, fmt.Errorf("claims: %v", err)
+	}
+	now := j.now()
+	switch {
+	case claims.Subject == "":
+		return claims, errors.New("sub is required")
+	case claims.ExpiresAt == nil:
+		return claims, errors.New("exp is required")
+	case now.After(unixTime(*claims.ExpiresAt).Add(clockSkew)):
+		return claims, errors.New("token expired")
+	case claims.NotBefore != nil && now.Add(clockSkew).Before(unixTime(*claims.NotBefore)):
+		return claims, errors.New("token not valid yet")
+	case j.cfg.Issuer != "" && claims.Issuer != j.cfg.Issuer:
+		return claims, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	case j.cfg.Audience != "" && !contains(claims.Audience, j.cfg.Audience):
+		return claims, errors.New("token is not intended for this audience")
+	}
+	return claims, nil
+}
+
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return errors.New("malformed base64url")
+	}
+	return json.Unmarshal(data, v)
+}
+
+func unixTime(sec float64) time.Time {
+	return time.Unix(0, int64(sec*float64(time.Second)))
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
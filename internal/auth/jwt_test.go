@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	testSecret = []byte("0123456789abcdef0123456789abcdef")
+	testNow    = time.Unix(1700000000, 0)
+)
+
+// signToken собирает токен; alg "none" даёт пустую подпись.
+func signToken(t *testing.T, alg string, key any, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(jwtHeader{Alg: alg, Typ: "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	var sig []byte
+	switch alg {
+	case "HS256":
+		mac := hmac.New(sha256.New, key.([]byte))
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case "RS256":
+		sum := sha256.Sum256([]byte(signed))
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key.(*rsa.PrivateKey), crypto.SHA256, sum[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func validClaims(override map[string]any) map[string]any {
+	claims := map[string]any{
+		"sub":   "alice",
+		"iss":   "https://issuer.example.com",
+		"aud":   "calculator",
+		"exp":   testNow.Add(time.Hour).Unix(),
+		"roles": []string{"operator"},
+	}
+	for k, v := range override {
+		if v == nil {
+			delete(claims, k)
+			continue
+		}
+		claims[k] = v
+	}
+	return claims
+}
+
+func TestJWTAuthenticate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := NewJWT(JWTConfig{
+		HS256Secret:    testSecret,
+		RS256PublicKey: &rsaKey.PublicKey,
+		Issuer:         "https://issuer.example.com",
+		Audience:       "calculator",
+	})
+	j.now = func() time.Time { return testNow }
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "hs256", token: signToken(t, "HS256", testSecret, validClaims(nil))},
+		{name: "rs256", token: signToken(t, "RS256", rsaKey, validClaims(nil))},
+		{name: "audience list", token: signToken(t, "HS256", testSecret, validClaims(map[string]any{"aud": []string{"other", "calculator"}}))},
+		{name: "expired within skew", token: signToken(t, "HS256", testSecret, validClaims(map[string]any{"exp": testNow.Add(-10 * time.Second).Unix()}))},
+		{name: "expired", token: signToken(t, "HS256", testSecret, validClaims(map[string]any{"exp": testNow.Add(-time.Minute).Unix()})), wantErr: true},
+		{name: "no exp", token: signToken(t, "HS256", testSecret, validClaims(map[string]any{"exp": nil})), wantErr: true},
+		{name: "not yet valid", token: signToken(t, "HS256", testSecret, validClaims(map[string]any{"nbf": testNow.Add(time.Minute).Unix()})), wantErr: true},
+		{name: "no sub", token: signToken(t, "HS256", testSecret, validClaims(map[string]any{"sub": nil})), wantErr: true},
+		{name: "wrong issuer", token: signToken(t, "HS256", testSecret, validClaims(map[string]any{"iss": "https://evil.example.com"})), wantErr: true},
+		{name: "wrong audience", token: signToken(t, "HS256", testSecret, validClaims(map[string]any{"aud": "other"})), wantErr: true},
+		{name: "wrong secret", token: signToken(t, "HS256", []byte("another secret of thirty-two byt"), validClaims(nil)), wantErr: true},
+		{name: "wrong rsa key", token: signToken(t, "RS256", otherKey, validClaims(nil)), wantErr: true},
+		{name: "alg none", token: signToken(t, "none", nil, validClaims(nil)), wantErr: true},
+		{name: "malformed", token: "a.b.c", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := j.Authenticate(context.Background(), Credentials{Scheme: SchemeBearer, Value: tt.token})
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnauthenticated) {
+					t.Fatalf("Authenticate = %+v, %v; want ErrUnauthenticated", id, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id.Subject != "alice" || id.Method != "jwt" || len(id.Roles) != 1 || id.Roles[0] != "operator" {
+				t.Fatalf("identity = %+v", id)
+			}
+		})
+	}
+}
+
+// Алгоритм без настроенного ключа не принимается, даже если подпись
+// верна.
+func TestJWTRequiresConfiguredAlgorithm(t *testing.T) {
+	j := NewJWT(JWTConfig{HS256Secret: testSecret})
+	j.now = func() time.Time { return testNow }
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := signToken(t, "RS256", rsaKey, validClaims(nil))
+	if _, err := j.Authenticate(context.Background(), Credentials{Scheme: SchemeBearer, Value: token}); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("got %v, want ErrUnauthenticated", err)
+	}
+}
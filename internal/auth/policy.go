@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrPermissionDenied — у клиента нет права на операцию.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Permission — право на группу операций.
+type Permission string
+
+const (
+	// PermCalculate — вычисление программы без состояния.
+	PermCalculate Permission = "calculate"
+	// PermSessions — создание сессий и вычисления в них.
+	PermSessions Permission = "sessions"
+	// PermProgramsRead — просмотр сохранённых программ и их версий.
+	PermProgramsRead Permission = "programs.read"
+	// PermProgramsWrite — сохранение и удаление программ.
+	PermProgramsWrite Permission = "programs.write"
+	// PermProgramsExecute — выполнение сохранённых программ.
+	PermProgramsExecute Permission = "programs.execute"
+	// PermBatch — пакетное вычисление, самый затратный режим.
+	PermBatch Permission = "batch"
+	// PermJobs — асинхронные задания.
+	PermJobs Permission = "jobs"
+	// PermAdmin — служебные возможности: описание API и gRPC reflection.
+	PermAdmin Permission = "admin"
+)
+
+// allPermissions — известные права; "*" в политике означает все.
+var allPermissions = map[Permission]bool{
+	PermCalculate:       true,
+	PermSessions:        true,
+	PermProgramsRead:    true,
+	PermProgramsWrite:   true,
+	PermProgramsExecute: true,
+	PermBatch:           true,
+	PermJobs:            true,
+	PermAdmin:           true,
+}
+
+const anyPermission = "*"
+
+// subjectMethods — допустимые префиксы ключей subjects, см. Identity.Key.
+var subjectMethods = map[string]bool{"api_key": true, "jwt": true, "mtls": true}
+
+type policyFile struct {
+	// Roles — права каждой роли.
+	Roles map[string][]string `yaml:"roles"`
+	// Subjects — роли клиентов по Identity.Key в дополнение к ролям
+	// из ключа или токена.
+	Subjects map[string][]string `yaml:"subjects"`
+	// DefaultRoles — роли любого прошедшего аутентификацию клиента.
+	DefaultRoles []string `yaml:"default_roles"`
+}
+
+// Policy решает, какие операции доступны клиенту. Роли клиента — это
+// роли из API ключа или JWT, роли из subjects и default_roles политики.
+// Роли, не описанные в политике, прав не дают.
+type Policy struct {
+	roles        map[string]map[Permission]bool
+	subjects     map[string][]string
+	defaultRoles []string
+}
+
+// LoadPolicy читает политику в формате YAML:
+//
+//	roles:
+//	  admin: ["*"]
+//	  operator: [calculate, sessions, programs.read, programs.execute]
+//	subjects:
+//	  jwt:alice: [admin]
+//	default_roles: [operator]
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy: %w", err)
+	}
+	var file policyFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("parse policy %s: %w", path, err)
+	}
+	p, err := newPolicy(file)
+	if err != nil {
+		return nil, fmt.Errorf("policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+func newPolicy(file policyFile) (*Policy, error) {
+	p := &Policy{
+		roles:        make(map[string]map[Permission]bool, len(file.Roles)),
+		subjects:     file.Subjects,
+		defaultRoles: file.DefaultRoles,
+	}
+	for role, perms := range file.Roles {
+		set := make(map[Permission]bool, len(perms))
+		for _, perm := range perms {
+			if perm == anyPermission {
+				for known := range allPermissions {
+					set[known] = true
+				}
+				continue
+			}
+			if !allPermissions[Permission(perm)] {
+				return nil, fmt.Errorf("role %s: unknown permission %q", role, perm)
+			}
+			set[Permission(perm)] = true
+		}
+		p.roles[role] = set
+	}
+	check := func(where string, roles []string) error {
+		for _, role := range roles {
+			if _, ok := p.roles[role]; !ok {
+				return fmt.Errorf("%s: undefined role %q", where, role)
+			}
+		}
+		return nil
+	}
+	if err := check("default_roles", file.DefaultRoles); err != nil {
+		return nil, err
+	}
+	for subject, roles := range file.Subjects {
+		method, name, ok := strings.Cut(subject, ":")
+		if !ok || name == "" || !subjectMethods[method] {
+			return nil, fmt.Errorf("subject %q: want api_key:, jwt: or mtls: prefix", subject)
+		}
+		if err := check("subject "+subject, roles); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Roles возвращает все роли клиента в порядке возрастания.
+func (p *Policy) Roles(id Identity) []string {
+	seen := make(map[string]bool)
+	var roles []string
+	for _, list := range [][]string{id.Roles, p.subjects[id.Key()], p.defaultRoles} {
+		for _, role := range list {
+			if !seen[role] {
+				seen[role] = true
+				roles = append(roles, role)
+			}
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// Authorize возвращает ErrPermissionDenied, если ни одна роль клиента не
+// даёт права perm.
+func (p *Policy) Authorize(id Identity, perm Permission) error {
+	for _, role := range p.Roles(id) {
+		if p.roles[role][perm] {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s requires %s", ErrPermissionDenied, id.Key(), perm)
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func testPolicy(t *testing.T) *Policy {
+	t.Helper()
+	p, err := newPolicy(policyFile{
+		Roles: map[string][]string{
+			"admin":    {"*"},
+			"operator": {"calculate", "sessions", "programs.read", "programs.execute"},
+			"viewer":   {"programs.read"},
+		},
+		Subjects: map[string][]string{
+			"jwt:alice":  {"admin"},
+			"api_key:ci": {"operator"},
+		},
+		DefaultRoles: []string{"viewer"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPolicyAuthorize(t *testing.T) {
+	p := testPolicy(t)
+	tests := []struct {
+		name   string
+		id     Identity
+		perm   Permission
+		denied bool
+	}{
+		{name: "default role", id: Identity{Subject: "bob", Method: "jwt"}, perm: PermProgramsRead},
+		{name: "default role denied", id: Identity{Subject: "bob", Method: "jwt"}, perm: PermCalculate, denied: true},
+		{name: "role from credentials", id: Identity{Subject: "deploy", Method: "api_key", Roles: []string{"operator"}}, perm: PermCalculate},
+		{name: "role from credentials denied", id: Identity{Subject: "deploy", Method: "api_key", Roles: []string{"operator"}}, perm: PermProgramsWrite, denied: true},
+		{name: "batch needs its own permission", id: Identity{Subject: "deploy", Method: "api_key", Roles: []string{"operator"}}, perm: PermBatch, denied: true},
+		{name: "subject role", id: Identity{Subject: "alice", Method: "jwt"}, perm: PermAdmin},
+		{name: "subject role by key", id: Identity{Subject: "ci", Method: "api_key"}, perm: PermCalculate},
+		{name: "wildcard", id: Identity{Subject: "alice", Method: "jwt"}, perm: PermJobs},
+		{name: "undefined role grants nothing", id: Identity{Subject: "eve", Method: "jwt", Roles: []string{"root"}}, perm: PermAdmin, denied: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.Authorize(tt.id, tt.perm)
+			if tt.denied != (err != nil) {
+				t.Fatalf("Authorize(%+v, %s) = %v, denied %v", tt.id, tt.perm, err, tt.denied)
+			}
+			if err != nil && !errors.Is(err, ErrPermissionDenied) {
+				t.Fatalf("error %v is not ErrPermissionDenied", err)
+			}
+		})
+	}
+}
+
+func TestPolicyRoles(t *testing.T) {
+	got := testPolicy(t).Roles(Identity{Subject: "alice", Method: "jwt", Roles: []string{"operator", "viewer"}})
+	if want := []string{"admin", "operator", "viewer"}; !slices.Equal(got, want) {
+		t.Fatalf("Roles = %v, want %v", got, want)
+	}
+}
+
+// Клиент, прошедший проверку другим способом, не получает роли
+// subjects, назначенные клиенту с тем же Subject: CommonName сертификата
+// "ci" — не API ключ "ci".
+func TestPolicySubjectsByMethod(t *testing.T) {
+	p := testPolicy(t)
+	tests := []struct {
+		id   Identity
+		want []string
+	}{
+		{id: Identity{Subject: "ci", Method: "api_key"}, want: []string{"operator", "viewer"}},
+		{id: Identity{Subject: "ci", Method: "mtls"}, want: []string{"viewer"}},
+		{id: Identity{Subject: "ci", Method: "jwt"}, want: []string{"viewer"}},
+		{id: Identity{Subject: "alice", Method: "jwt"}, want: []string{"admin", "viewer"}},
+		{id: Identity{Subject: "alice", Method: "mtls"}, want: []string{"viewer"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id.Key(), func(t *testing.T) {
+			if got := p.Roles(tt.id); !slices.Equal(got, tt.want) {
+				t.Fatalf("Roles = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if err := p.Authorize(Identity{Subject: "ci", Method: "mtls"}, PermCalculate); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("mtls:ci calculate: got %v, want ErrPermissionDenied", err)
+	}
+}
+
+func TestLoadPolicyRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "unknown permission", data: "roles:\n  ops: [calculate, deploy]\n"},
+		{name: "undefined default role", data: "roles:\n  ops: [calculate]\ndefault_roles: [viewer]\n"},
+		{name: "undefined subject role", data: "roles:\n  ops: [calculate]\nsubjects:\n  jwt:bob: [viewer]\n"},
+		{name: "subject without method", data: "roles:\n  ops: [calculate]\nsubjects:\n  bob: [ops]\n"},
+		{name: "subject with unknown method", data: "roles:\n  ops: [calculate]\nsubjects:\n  ldap:bob: [ops]\n"},
+		{name: "subject without name", data: "roles:\n  ops: [calculate]\nsubjects:\n  \"jwt:\": [ops]\n"},
+		{name: "unknown field", data: "roles:\n  ops: [calculate]\nusers: {}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadPolicy(path); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestLoadPolicyExample(t *testing.T) {
+	p, err := LoadPolicy(filepath.Join("..", "..", "configs", "policy.example.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Authorize(Identity{Subject: "ci", Method: "api_key"}, PermBatch); err != nil {
+		t.Fatalf("api_key:ci batch: %v", err)
+	}
+	if err := p.Authorize(Identity{Subject: "ci", Method: "mtls"}, PermBatch); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("mtls:ci batch: got %v, want ErrPermissionDenied", err)
+	}
+}
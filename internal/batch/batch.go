@@ -0,0 +1,125 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"calculator/internal/service"
+)
+
+const defaultConcurrency = 16
+
+// Result — результат вычисления одной строки.
+type Result struct {
+	Row    int                  `json:"row"`
+	Inputs map[string]int64     `json:"inputs,omitempty"`
+	Items  []service.ResultItem `json:"items,omitempty"`
+	Error  string               `json:"error,omitempty"`
+}
+
+type Summary struct {
+	Rows      int `json:"rows"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+// Runner выполняет одну программу для каждой строки входных данных.
+// Одновременно обрабатывается не больше concurrency строк, а результаты
+// выдаются в порядке строк, поэтому память не растёт с числом строк.
+type Runner struct {
+	calc        *service.CalculatorService
+	concurrency int
+}
+
+func NewRunner(calc *service.CalculatorService, concurrency int) *Runner {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	return &Runner{calc: calc, concurrency: concurrency}
+}
+
+// Run проверяет программу, затем вычисляет её для каждой строки rows и
+// передаёт результаты в emit. Ошибки отдельных строк попадают в Result;
+// Run завершается ошибкой только при некорректной программе, ошибке
+// чтения, ошибке emit или отмене ctx.
+func (r *Runner) Run(ctx context.Context, instructions []service.Instruction, rows RowReader, emit func(Result) error) (Summary, error) {
+	var summary Summary
+	if err := r.calc.Validate(instructions); err != nil {
+		return summary, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// pending хранит результаты строк в порядке чтения; его ёмкость
+	// ограничивает число строк в обработке.
+	pending := make(chan chan Result, r.concurrency)
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(pending)
+		for {
+			row, err := rows.Next()
+			if errors.Is(err, io.EOF) {
+				readErr <- nil
+				return
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+
+			out := make(chan Result, 1)
+			select {
+			case pending <- out:
+			case <-ctx.Done():
+				readErr <- ctx.Err()
+				return
+			}
+			go func() {
+				out <- r.evaluate(ctx, instructions, row)
+			}()
+		}
+	}()
+
+	for out := range pending {
+		var res Result
+		select {
+		case res = <-out:
+		case <-ctx.Done():
+			return summary, ctx.Err()
+		}
+		summary.Rows++
+		if res.Error != "" {
+			summary.Failed++
+		} else {
+			summary.Succeeded++
+		}
+		if err := emit(res); err != nil {
+			return summary, err
+		}
+	}
+	return summary, <-readErr
+}
+
+func (r *Runner) evaluate(ctx context.Context, instructions []service.Instruction, row Row) Result {
+	res := Result{Row: row.Index, Inputs: row.Inputs}
+	if row.Err != nil {
+		res.Error = row.Err.Error()
+		return res
+	}
+	inputs, err := service.Bind(instructions, row.Inputs)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Inputs = inputs
+	items, err := r.calc.Run(ctx, instructions, inputs)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Items = items
+	return res
+}
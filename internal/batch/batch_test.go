@@ -0,0 +1,162 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"calculator/internal/service"
+)
+
+// total = price * qty
+var program = []service.Instruction{
+	{Type: "input", Var: "price"},
+	{Type: "input", Var: "qty"},
+	{Type: "calc", Op: "*", Var: "total", Left: "price", Right: "qty"},
+	{Type: "print", Var: "total"},
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		rows   string
+		want   []string
+	}{
+		{
+			name:   "csv",
+			format: FormatCSV,
+			rows:   "price,qty\n2,3\n4, 5\n",
+			want:   []string{"1: total=6", "2: total=20"},
+		},
+		{
+			name:   "csv row errors",
+			format: FormatCSV,
+			rows:   "price,qty\n2,x\n1,1\n3,\n",
+			want: []string{
+				`1: error column qty: expected integer, got "x"`,
+				"2: total=1",
+				"3: error invalid inputs: missing: qty",
+			},
+		},
+		{
+			name:   "jsonl",
+			format: FormatJSONL,
+			rows:   "{\"price\":2,\"qty\":3}\n\n{\"price\":1,\"qty\":7,\"extra\":1}\nnot json\n{\"price\":-1,\"qty\":2}",
+			want: []string{
+				"1: total=6",
+				"2: error invalid inputs: unexpected: extra",
+				"3: error line 3: invalid character 'o' in literal null (expecting 'u')",
+				"4: total=-2",
+			},
+		},
+	}
+	runner := NewRunner(service.NewCalculatorService(service.WithLatency(0)), 2)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := NewReader(tt.format, strings.NewReader(tt.rows))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0)
+			summary, err := runner.Run(context.Background(), program, rows, func(res Result) error {
+				got = append(got, formatResult(res))
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("results:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			failed := 0
+			for _, line := range tt.want {
+				if strings.Contains(line, "error") {
+					failed++
+				}
+			}
+			if want := (Summary{Rows: len(tt.want), Succeeded: len(tt.want) - failed, Failed: failed}); summary != want {
+				t.Fatalf("summary = %+v, want %+v", summary, want)
+			}
+		})
+	}
+}
+
+// Результаты выдаются по мере готовности, не дожидаясь конца ввода.
+func TestRunEmitsBeforeEndOfInput(t *testing.T) {
+	runner := NewRunner(service.NewCalculatorService(service.WithLatency(0)), 4)
+	rows := &chanRows{ch: make(chan Row)}
+	emitted := make(chan Result)
+	done := make(chan error, 1)
+	go func() {
+		_, err := runner.Run(context.Background(), program, rows, func(res Result) error {
+			emitted <- res
+			return nil
+		})
+		done <- err
+	}()
+
+	for i := 1; i <= 3; i++ {
+		rows.ch <- Row{Index: i, Inputs: map[string]int64{"price": int64(i), "qty": 10}}
+		res := <-emitted
+		if res.Row != i || res.Error != "" || res.Items[0].Value != int64(10*i) {
+			t.Fatalf("row %d: got %+v", i, res)
+		}
+	}
+	close(rows.ch)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunStopsOnEmitError(t *testing.T) {
+	runner := NewRunner(service.NewCalculatorService(service.WithLatency(0)), 2)
+	rows := NewJSONLReader(strings.NewReader(strings.Repeat("{\"price\":1,\"qty\":1}\n", 100)))
+	stop := errors.New("client gone")
+	emitted := 0
+	_, err := runner.Run(context.Background(), program, rows, func(Result) error {
+		emitted++
+		if emitted == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || emitted != 3 {
+		t.Fatalf("got %v after %d rows, want emit error after 3", err, emitted)
+	}
+}
+
+func TestRunRejectsInvalidProgram(t *testing.T) {
+	runner := NewRunner(service.NewCalculatorService(service.WithLatency(0)), 2)
+	invalid := []service.Instruction{{Type: "calc", Op: "/", Var: "x", Left: int64(1), Right: int64(2)}}
+	_, err := runner.Run(context.Background(), invalid, NewJSONLReader(strings.NewReader("{}\n")), func(Result) error { return nil })
+	if !errors.Is(err, service.ErrInvalidProgram) {
+		t.Fatalf("got %v, want ErrInvalidProgram", err)
+	}
+}
+
+type chanRows struct {
+	ch chan Row
+}
+
+func (r *chanRows) Next() (Row, error) {
+	row, ok := <-r.ch
+	if !ok {
+		return Row{}, io.EOF
+	}
+	return row, nil
+}
+
+func formatResult(res Result) string {
+	if res.Error != "" {
+		return fmt.Sprintf("%d: error %s", res.Row, res.Error)
+	}
+	parts := make([]string, 0, len(res.Items))
+	for _, item := range res.Items {
+		parts = append(parts, fmt.Sprintf("%s=%d", item.Var, item.Value))
+	}
+	return fmt.Sprintf("%d: %s", res.Row, strings.Join(parts, " "))
+}
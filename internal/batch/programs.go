@@ -0,0 +1,89 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"calculator/internal/service"
+)
+
+var ErrInvalidBatch = errors.New("invalid batch")
+
+// Program — одна из независимых программ пакета; ID задаёт клиент.
+type Program struct {
+	ID           string                `json:"id"`
+	Instructions []service.Instruction `json:"instructions"`
+	Inputs       map[string]int64      `json:"inputs,omitempty"`
+}
+
+// ProgramResult — результат одной программы пакета.
+type ProgramResult struct {
+	Items  []service.ResultItem `json:"items,omitempty"`
+	Inputs map[string]int64     `json:"inputs,omitempty"`
+	Error  string               `json:"error,omitempty"`
+}
+
+// RunPrograms выполняет независимые программы параллельно, не больше
+// concurrency одновременно. Ошибка одной программы не влияет на
+// остальные и попадает в её результат; программы, не успевшие
+// выполниться до отмены ctx, завершаются ошибкой контекста. Сам вызов
+// завершается ошибкой, только если пакет некорректен.
+func (r *Runner) RunPrograms(ctx context.Context, programs []Program) (map[string]ProgramResult, error) {
+	seen := make(map[string]bool, len(programs))
+	for i, p := range programs {
+		if p.ID == "" {
+			return nil, fmt.Errorf("%w: program %d has no id", ErrInvalidBatch, i)
+		}
+		if seen[p.ID] {
+			return nil, fmt.Errorf("%w: duplicate program id %q", ErrInvalidBatch, p.ID)
+		}
+		seen[p.ID] = true
+	}
+
+	results := make(map[string]ProgramResult, len(programs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, r.concurrency)
+	for _, p := range programs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var res ProgramResult
+			select {
+			case slots <- struct{}{}:
+				res = r.evaluateProgram(ctx, p)
+				<-slots
+			case <-ctx.Done():
+				res.Error = ctx.Err().Error()
+			}
+			mu.Lock()
+			results[p.ID] = res
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results, nil
+}
+
+func (r *Runner) evaluateProgram(ctx context.Context, p Program) ProgramResult {
+	var res ProgramResult
+	if err := r.calc.Validate(p.Instructions); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	inputs, err := service.Bind(p.Instructions, p.Inputs)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Inputs = inputs
+	items, err := r.calc.Run(ctx, p.Instructions, inputs)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Items = items
+	return res
+}
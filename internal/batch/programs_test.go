@@ -0,0 +1,96 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"calculator/internal/service"
+)
+
+func TestRunPrograms(t *testing.T) {
+	r := NewRunner(service.NewCalculatorService(service.WithLatency(0)), 2)
+	programs := []Program{
+		{ID: "a", Instructions: program, Inputs: map[string]int64{"price": 2, "qty": 3}},
+		{ID: "b", Instructions: program, Inputs: map[string]int64{"price": 5, "qty": 5}},
+		{ID: "missing input", Instructions: program, Inputs: map[string]int64{"price": 1}},
+		{ID: "invalid", Instructions: []service.Instruction{{Type: "calc", Op: "+", Var: "x", Left: "y", Right: int64(1)}}},
+		{ID: "c", Instructions: []service.Instruction{
+			{Type: "calc", Op: "+", Var: "x", Left: int64(1), Right: int64(1)},
+			{Type: "print", Var: "x"},
+		}},
+	}
+	results, err := r.RunPrograms(context.Background(), programs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(programs) {
+		t.Fatalf("got %d results, want %d", len(results), len(programs))
+	}
+	want := map[string]string{
+		"a": "[{total 6}]",
+		"b": "[{total 25}]",
+		"c": "[{x 2}]",
+	}
+	for id, items := range want {
+		res := results[id]
+		if res.Error != "" || fmt.Sprint(res.Items) != items {
+			t.Errorf("%s = %+v, want %s", id, res, items)
+		}
+	}
+	if res := results["a"]; fmt.Sprint(res.Inputs) != "map[price:2 qty:3]" {
+		t.Errorf("a inputs = %v", res.Inputs)
+	}
+	for _, id := range []string{"missing input", "invalid"} {
+		if res := results[id]; res.Error == "" || res.Items != nil {
+			t.Errorf("%s = %+v, want an error", id, res)
+		}
+	}
+}
+
+func TestRunProgramsInvalidBatch(t *testing.T) {
+	r := NewRunner(service.NewCalculatorService(service.WithLatency(0)), 2)
+	tests := []struct {
+		name     string
+		programs []Program
+	}{
+		{name: "missing id", programs: []Program{{ID: "a", Instructions: program}, {Instructions: program}}},
+		{name: "duplicate id", programs: []Program{{ID: "a", Instructions: program}, {ID: "a", Instructions: program}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := r.RunPrograms(context.Background(), tt.programs)
+			if !errors.Is(err, ErrInvalidBatch) || results != nil {
+				t.Fatalf("RunPrograms = %v, %v, want ErrInvalidBatch", results, err)
+			}
+		})
+	}
+}
+
+// Общий срок прерывает и выполняющиеся программы, и те, что ждут своей
+// очереди; каждая получает ошибку контекста в своём результате.
+func TestRunProgramsDeadline(t *testing.T) {
+	r := NewRunner(service.NewCalculatorService(service.WithLatency(time.Hour)), 1)
+	slow := []service.Instruction{
+		{Type: "calc", Op: "+", Var: "x", Left: int64(1), Right: int64(1)},
+		{Type: "print", Var: "x"},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	results, err := r.RunPrograms(ctx, []Program{{ID: "a", Instructions: slow}, {ID: "b", Instructions: slow}, {ID: "c", Instructions: slow}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("RunPrograms took %s after the deadline", elapsed)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if res := results[id]; res.Error != context.DeadlineExceeded.Error() {
+			t.Errorf("%s = %+v, want %v", id, res, context.DeadlineExceeded)
+		}
+	}
+}
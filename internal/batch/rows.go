@@ -0,0 +1,142 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Row — одна строка входных данных. Err заполняется, если строку не
+// удалось разобрать; такая строка не вычисляется, но попадает в вывод.
+type Row struct {
+	Index  int
+	Inputs map[string]int64
+	Err    error
+}
+
+// RowReader последовательно читает строки; по окончании возвращает io.EOF.
+type RowReader interface {
+	Next() (Row, error)
+}
+
+// Format — формат потока входных строк.
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+// FormatFromContentType определяет формат по заголовку Content-Type.
+func FormatFromContentType(contentType string) (Format, error) {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	switch strings.TrimSpace(strings.ToLower(mediaType)) {
+	case "text/csv":
+		return FormatCSV, nil
+	case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return FormatJSONL, nil
+	}
+	return "", fmt.Errorf("unsupported rows content type %q", contentType)
+}
+
+func NewReader(format Format, r io.Reader) (RowReader, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVReader(r)
+	case FormatJSONL:
+		return NewJSONLReader(r), nil
+	}
+	return nil, fmt.Errorf("unsupported rows format %q", format)
+}
+
+type csvReader struct {
+	r      *csv.Reader
+	header []string
+	index  int
+}
+
+// NewCSVReader читает CSV, первая строка которого содержит имена входов.
+func NewCSVReader(r io.Reader) (RowReader, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv: missing header row")
+		}
+		return nil, fmt.Errorf("csv header: %w", err)
+	}
+	return &csvReader{r: cr, header: append([]string(nil), header...)}, nil
+}
+
+func (c *csvReader) Next() (Row, error) {
+	record, err := c.r.Read()
+	if errors.Is(err, io.EOF) {
+		return Row{}, io.EOF
+	}
+	c.index++
+	row := Row{Index: c.index}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			row.Err = err
+			return row, nil
+		}
+		return Row{}, err
+	}
+
+	row.Inputs = make(map[string]int64, len(record))
+	for i, field := range record {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		val, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			row.Err = fmt.Errorf("column %s: expected integer, got %q", c.header[i], field)
+			return row, nil
+		}
+		row.Inputs[c.header[i]] = val
+	}
+	return row, nil
+}
+
+type jsonlReader struct {
+	r     *bufio.Reader
+	index int
+}
+
+// NewJSONLReader читает по одному JSON-объекту входов на строку.
+// Пустые строки пропускаются.
+func NewJSONLReader(r io.Reader) RowReader {
+	return &jsonlReader{r: bufio.NewReader(r)}
+}
+
+func (j *jsonlReader) Next() (Row, error) {
+	for {
+		line, err := j.r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) == 0 {
+			if err != nil {
+				return Row{}, err
+			}
+			continue
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return Row{}, err
+		}
+
+		j.index++
+		row := Row{Index: j.index}
+		if err := json.Unmarshal(line, &row.Inputs); err != nil {
+			row.Err = fmt.Errorf("line %d: %v", j.index, err)
+		}
+		return row, nil
+	}
+}
@@ -0,0 +1,180 @@
+// Package certs загружает сертификат сервера и сертификаты центров,
+// которыми проверяются клиенты, и перечитывает их, когда файлы меняются
+// на диске.
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Config struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile — сертификаты центров в формате PEM, которыми
+	// проверяются сертификаты клиентов; пусто — сертификат у клиента не
+	// запрашивается.
+	ClientCAFile string
+	// RequireClientCert отклоняет соединения без сертификата клиента.
+	RequireClientCert bool
+	// ReloadInterval — период проверки файлов; ноль отключает
+	// перечитывание.
+	ReloadInterval time.Duration
+	// NextProtos — протоколы ALPN, например h2 и http/1.1.
+	NextProtos []string
+}
+
+// fileStamp — признаки изменения файла.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Reloader отдаёт TLS соединениям актуальные сертификаты. Новое
+// соединение получает конфигурацию, загруженную последней; уже
+// установленные соединения не затрагиваются.
+type Reloader struct {
+	cfg     Config
+	current atomic.Pointer[tls.Config]
+	stamps  map[string]fileStamp
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewReloader загружает файлы и, если задан ReloadInterval, начинает
+// следить за ними.
+func NewReloader(cfg Config) (*Reloader, error) {
+	r := &Reloader{
+		cfg:  cfg,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	r.stamps = r.stat()
+	current, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	r.current.Store(current)
+	if cfg.ReloadInterval > 0 {
+		go r.watch()
+	} else {
+		close(r.done)
+	}
+	return r, nil
+}
+
+// TLSConfig возвращает конфигурацию для сервера: каждое соединение
+// получает сертификаты, действующие на момент рукопожатия.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: r.cfg.NextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+	}
+}
+
+// Close прекращает слежение за файлами. Вызов у nil допустим.
+func (r *Reloader) Close() {
+	if r == nil {
+		return
+	}
+	r.once.Do(func() { close(r.stop) })
+	<-r.done
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	return files
+}
+
+func (r *Reloader) stat() map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	for _, path := range r.files() {
+		if fi, err := os.Stat(path); err == nil {
+			stamps[path] = fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+		}
+	}
+	return stamps
+}
+
+func (r *Reloader) load() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load certificate: %w", err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   r.cfg.NextProtos,
+	}
+	if r.cfg.ClientCAFile != "" {
+		data, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.New("client CA " + r.cfg.ClientCAFile + ": no certificates found")
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		if r.cfg.RequireClientCert {
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return cfg, nil
+}
+
+// watch перечитывает файлы, когда меняется время изменения или размер
+// любого из них. Если новые файлы не загружаются, например ключ ещё не
+// дописан, остаётся прежняя конфигурация до следующего изменения.
+func (r *Reloader) watch() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.cfg.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			stamps := r.stat()
+			if sameStamps(stamps, r.stamps) {
+				continue
+			}
+			r.stamps = stamps
+			current, err := r.load()
+			if err != nil {
+				slog.Error("failed to reload TLS certificates", "cert_file", r.cfg.CertFile, "error", err)
+				continue
+			}
+			r.current.Store(current)
+			slog.Info("TLS certificates reloaded", "cert_file", r.cfg.CertFile)
+		}
+	}
+}
+
+func sameStamps(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, s := range a {
+		if other, ok := b[path]; !ok || !other.modTime.Equal(s.modTime) || other.size != s.size {
+			return false
+		}
+	}
+	return true
+}
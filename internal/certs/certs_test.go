@@ -0,0 +1,337 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// authority — тестовый центр сертификации.
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newAuthority(t *testing.T, name string) *authority {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &authority{cert: cert, key: key}
+}
+
+// issue выпускает сертификат сервера для localhost или сертификат
+// клиента с CommonName name.
+func (a *authority) issue(t *testing.T, name string, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	if usage == x509.ExtKeyUsageServerAuth {
+		tmpl.DNSNames = []string{"localhost"}
+		tmpl.IPAddresses = []net.IP{net.IPv4(127, 0, 0, 1)}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, a.cert, &key.PublicKey, a.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func (a *authority) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(a.cert)
+	return pool
+}
+
+func writePEM(t *testing.T, path, kind string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writePair записывает сертификат и ключ и сдвигает время изменения
+// файлов, чтобы перезапись была заметна при любой точности времени
+// файловой системы.
+func writePair(t *testing.T, cfg Config, cert tls.Certificate, modTime time.Time) {
+	t.Helper()
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, cfg.CertFile, "CERTIFICATE", cert.Certificate[0])
+	writePEM(t, cfg.KeyFile, "PRIVATE KEY", key)
+	for _, path := range []string{cfg.CertFile, cfg.KeyFile} {
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// serve принимает TLS соединения с конфигурацией cfg и отвечает одним
+// байтом после успешного рукопожатия.
+func serve(t *testing.T, cfg *tls.Config) string {
+	t.Helper()
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.SetDeadline(time.Now().Add(5 * time.Second))
+				if conn.(*tls.Conn).Handshake() == nil {
+					conn.Write([]byte{1})
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// dial выполняет рукопожатие и ждёт ответа сервера. Отказ сервера в
+// TLS 1.3 клиент видит только при чтении.
+func dial(addr string, cfg *tls.Config) (*tls.ConnectionState, error) {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", addr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		return nil, err
+	}
+	state := conn.ConnectionState()
+	return &state, nil
+}
+
+func newTestConfig(t *testing.T) Config {
+	dir := t.TempDir()
+	return Config{
+		CertFile: filepath.Join(dir, "server.crt"),
+		KeyFile:  filepath.Join(dir, "server.key"),
+	}
+}
+
+func TestReload(t *testing.T) {
+	ca := newAuthority(t, "Test CA")
+	cfg := newTestConfig(t)
+	cfg.ReloadInterval = 10 * time.Millisecond
+	start := time.Now().Add(-time.Minute)
+	writePair(t, cfg, ca.issue(t, "server-1", x509.ExtKeyUsageServerAuth), start)
+
+	r, err := NewReloader(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	addr := serve(t, r.TLSConfig())
+	client := &tls.Config{RootCAs: ca.pool(), ServerName: "localhost"}
+
+	serverName := func() string {
+		t.Helper()
+		state, err := dial(addr, client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return state.PeerCertificates[0].Subject.CommonName
+	}
+	waitFor := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for serverName() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("server certificate is not %s", want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if got := serverName(); got != "server-1" {
+		t.Fatalf("server certificate = %s, want server-1", got)
+	}
+
+	writePair(t, cfg, ca.issue(t, "server-2", x509.ExtKeyUsageServerAuth), start.Add(time.Second))
+	waitFor("server-2")
+
+	// Недописанный ключ не загружается: соединения получают прежний
+	// сертификат, пока файлы не станут согласованными.
+	writePEM(t, cfg.KeyFile, "PRIVATE KEY", []byte("truncated"))
+	if err := os.Chtimes(cfg.KeyFile, start.Add(2*time.Second), start.Add(2*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := serverName(); got != "server-2" {
+		t.Fatalf("server certificate after a broken rewrite = %s, want server-2", got)
+	}
+
+	writePair(t, cfg, ca.issue(t, "server-3", x509.ExtKeyUsageServerAuth), start.Add(3*time.Second))
+	waitFor("server-3")
+}
+
+func TestClientCertificate(t *testing.T) {
+	serverCA := newAuthority(t, "Server CA")
+	clientCA := newAuthority(t, "Client CA")
+	otherCA := newAuthority(t, "Untrusted CA")
+
+	tests := []struct {
+		name    string
+		require bool
+		cert    *tls.Certificate
+		wantErr bool
+		wantCN  string
+	}{
+		{name: "trusted", require: true, cert: ptr(clientCA.issue(t, "reporting.internal", x509.ExtKeyUsageClientAuth)), wantCN: "reporting.internal"},
+		{name: "untrusted CA", require: true, cert: ptr(otherCA.issue(t, "reporting.internal", x509.ExtKeyUsageClientAuth)), wantErr: true},
+		{name: "missing when required", require: true, wantErr: true},
+		{name: "untrusted CA when optional", cert: ptr(otherCA.issue(t, "reporting.internal", x509.ExtKeyUsageClientAuth)), wantErr: true},
+		{name: "missing when optional"},
+		{name: "trusted when optional", cert: ptr(clientCA.issue(t, "ci", x509.ExtKeyUsageClientAuth)), wantCN: "ci"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			cfg.ClientCAFile = filepath.Join(filepath.Dir(cfg.CertFile), "clients.pem")
+			cfg.RequireClientCert = tt.require
+			writePair(t, cfg, serverCA.issue(t, "server", x509.ExtKeyUsageServerAuth), time.Now())
+			writePEM(t, cfg.ClientCAFile, "CERTIFICATE", clientCA.cert.Raw)
+
+			r, err := NewReloader(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			// Обёртка над конфигурацией соединения сообщает, чей сертификат
+			// сервер проверил.
+			verified := make(chan string, 1)
+			tlsCfg := r.TLSConfig()
+			getConfig := tlsCfg.GetConfigForClient
+			tlsCfg.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+				cfg, err := getConfig(hello)
+				if err != nil {
+					return nil, err
+				}
+				cfg = cfg.Clone()
+				cfg.VerifyConnection = func(state tls.ConnectionState) error {
+					if len(state.VerifiedChains) > 0 {
+						verified <- state.VerifiedChains[0][0].Subject.CommonName
+					}
+					return nil
+				}
+				return cfg, nil
+			}
+			addr := serve(t, tlsCfg)
+
+			// Клиент предъявляет сертификат, даже если центр не из списка
+			// сервера: иначе Go не отправил бы его вовсе.
+			client := &tls.Config{
+				RootCAs:    serverCA.pool(),
+				ServerName: "localhost",
+				GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+					if tt.cert == nil {
+						return &tls.Certificate{}, nil
+					}
+					return tt.cert, nil
+				},
+			}
+			_, err = dial(addr, client)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("handshake succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantCN == "" {
+				select {
+				case cn := <-verified:
+					t.Fatalf("verified client %s, want none", cn)
+				default:
+				}
+				return
+			}
+			if cn := <-verified; cn != tt.wantCN {
+				t.Fatalf("verified client = %s, want %s", cn, tt.wantCN)
+			}
+		})
+	}
+}
+
+func TestNewReloaderErrors(t *testing.T) {
+	ca := newAuthority(t, "Test CA")
+	tests := []struct {
+		name  string
+		setup func(cfg *Config)
+	}{
+		{name: "missing certificate", setup: func(cfg *Config) { cfg.CertFile += ".missing" }},
+		{name: "missing client CA", setup: func(cfg *Config) { cfg.ClientCAFile = cfg.CertFile + ".missing" }},
+		{name: "client CA without certificates", setup: func(cfg *Config) { cfg.ClientCAFile = cfg.KeyFile }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			writePair(t, cfg, ca.issue(t, "server", x509.ExtKeyUsageServerAuth), time.Now())
+			tt.setup(&cfg)
+			if r, err := NewReloader(cfg); err == nil {
+				r.Close()
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
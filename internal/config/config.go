@@ -0,0 +1,386 @@
+// Package config собирает настройки сервера из файла, переменных
+// окружения и флагов командной строки.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"time"
+)
+
+// Duration — длительность, которая в файле конфигурации записывается
+// строкой вида "50ms" или "5m".
+type Duration time.Duration
+
+func (d Duration) String() string { return time.Duration(d).String() }
+
+func (d Duration) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// TLS — сертификат и ключ сервера. Пустые пути означают работу без TLS.
+type TLS struct {
+	CertFile string `yaml:"cert_file" json:"cert_file"`
+	KeyFile  string `yaml:"key_file" json:"key_file"`
+	// ClientCAFile — центры сертификации клиентов (mTLS). Проверенный
+	// сертификат клиента служит его учётными данными.
+	ClientCAFile string `yaml:"client_ca_file" json:"client_ca_file"`
+	// RequireClientCert отклоняет соединения без сертификата клиента.
+	RequireClientCert bool `yaml:"require_client_cert" json:"require_client_cert"`
+	// ReloadInterval — период проверки файлов на изменение; ноль
+	// отключает перечитывание.
+	ReloadInterval Duration `yaml:"reload_interval" json:"reload_interval"`
+}
+
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+type Listener struct {
+	Addr string `yaml:"addr" json:"addr"`
+	TLS  TLS    `yaml:"tls" json:"tls"`
+}
+
+type GRPC struct {
+	Listener `yaml:",inline"`
+	// Reflection включает сервис gRPC server reflection и выдачу набора
+	// дескрипторов по GET /api/descriptor.
+	Reflection bool `yaml:"reflection" json:"reflection"`
+}
+
+type Calculator struct {
+	// Workers — размер общего пула вычисления переменных.
+	Workers int `yaml:"workers" json:"workers"`
+	// Latency — искусственная задержка вычисления одной переменной.
+	Latency Duration `yaml:"latency" json:"latency"`
+}
+
+type Sessions struct {
+	TTL          Duration `yaml:"ttl" json:"ttl"`
+	MaxVariables int      `yaml:"max_variables" json:"max_variables"`
+}
+
+type Jobs struct {
+	Workers   int      `yaml:"workers" json:"workers"`
+	QueueSize int      `yaml:"queue_size" json:"queue_size"`
+	Retention Duration `yaml:"retention" json:"retention"`
+	Webhook   Webhook  `yaml:"webhook" json:"webhook"`
+}
+
+// Webhook ограничивает адреса уведомлений о завершении заданий.
+type Webhook struct {
+	// AllowedHosts — разрешённые узлы: имя или "*.example.com"; пусто —
+	// любой узел.
+	AllowedHosts []string `yaml:"allowed_hosts" json:"allowed_hosts"`
+	// AllowPrivateNetworks разрешает адреса loopback, частных и
+	// link-local сетей.
+	AllowPrivateNetworks bool `yaml:"allow_private_networks" json:"allow_private_networks"`
+}
+
+type Batch struct {
+	Concurrency int `yaml:"concurrency" json:"concurrency"`
+}
+
+type Storage struct {
+	// DataDir — каталог хранилища; пусто — хранить данные в памяти.
+	DataDir         string   `yaml:"data_dir" json:"data_dir"`
+	CompactEvery    int      `yaml:"compact_every" json:"compact_every"`
+	CompactInterval Duration `yaml:"compact_interval" json:"compact_interval"`
+	NoSync          bool     `yaml:"no_sync" json:"no_sync"`
+}
+
+type Shutdown struct {
+	// Timeout — сколько ждать завершения текущих вызовов при остановке.
+	Timeout Duration `yaml:"timeout" json:"timeout"`
+	// Delay — пауза между переходом в состояние неготовности и
+	// прекращением приёма запросов.
+	Delay Duration `yaml:"delay" json:"delay"`
+}
+
+type Log struct {
+	// Level — debug, info, warn или error.
+	Level string `yaml:"level" json:"level"`
+}
+
+type Tracing struct {
+	// Exporter — none, stdout или file.
+	Exporter string `yaml:"exporter" json:"exporter"`
+	// File — файл спанов для экспортёра file.
+	File string `yaml:"file" json:"file"`
+}
+
+type Middleware struct {
+	// Order — промежуточные обработчики HTTP и gRPC от внешнего к
+	// внутреннему.
+	Order []string `yaml:"order" json:"order"`
+}
+
+// Limits — ограничения размера запросов и программ. Кроме
+// max_request_bytes, ноль отключает ограничение.
+type Limits struct {
+	// MaxRequestBytes — наибольший размер тела HTTP запроса и сообщения
+	// gRPC.
+	MaxRequestBytes int `yaml:"max_request_bytes" json:"max_request_bytes"`
+	// MaxInstructions — инструкций в одной программе.
+	MaxInstructions int `yaml:"max_instructions" json:"max_instructions"`
+	// MaxNameLength — длина имени переменной в байтах.
+	MaxNameLength int `yaml:"max_name_length" json:"max_name_length"`
+	// MaxDepth — длина самой длинной цепочки зависимостей.
+	MaxDepth int `yaml:"max_depth" json:"max_depth"`
+	// MaxFanIn — сколько инструкций может ссылаться на одну переменную.
+	MaxFanIn int `yaml:"max_fan_in" json:"max_fan_in"`
+	// MaxValueBits — разрядность модуля литералов, входов и результатов,
+	// не больше 64.
+	MaxValueBits int `yaml:"max_value_bits" json:"max_value_bits"`
+	// MaxEvaluationTime — время выполнения одной программы.
+	MaxEvaluationTime Duration `yaml:"max_evaluation_time" json:"max_evaluation_time"`
+	// MaxInFlightExecutions и MaxInFlightInstructions — сколько программ
+	// и инструкций сервер выполняет одновременно; сверх этого новая
+	// работа отклоняется.
+	MaxInFlightExecutions   int `yaml:"max_in_flight_executions" json:"max_in_flight_executions"`
+	MaxInFlightInstructions int `yaml:"max_in_flight_instructions" json:"max_in_flight_instructions"`
+}
+
+// RateLimit — ограничения для каждого клиента: клиент определяется по
+// личности после аутентификации, иначе по адресу. Ноль отключает
+// ограничение.
+type RateLimit struct {
+	RequestsPerSecond int `yaml:"requests_per_second" json:"requests_per_second"`
+	// RequestBurst — запросы подряд сверх средней частоты; ноль — равно
+	// requests_per_second.
+	RequestBurst          int `yaml:"request_burst" json:"request_burst"`
+	InstructionsPerSecond int `yaml:"instructions_per_second" json:"instructions_per_second"`
+	InstructionBurst      int `yaml:"instruction_burst" json:"instruction_burst"`
+	// DailyInstructions — квота инструкций за сутки по UTC. Расход
+	// сохраняется в хранилище.
+	DailyInstructions int `yaml:"daily_instructions" json:"daily_instructions"`
+}
+
+type JWT struct {
+	// HS256SecretFile — файл общего секрета HS256.
+	HS256SecretFile string `yaml:"hs256_secret_file" json:"hs256_secret_file"`
+	// RS256PublicKeyFile — открытый ключ RSA в формате PEM для RS256.
+	RS256PublicKeyFile string `yaml:"rs256_public_key_file" json:"rs256_public_key_file"`
+	Issuer             string `yaml:"issuer" json:"issuer"`
+	Audience           string `yaml:"audience" json:"audience"`
+}
+
+type Auth struct {
+	// Enabled требует учётные данные во всех вызовах, кроме проверок
+	// живости, готовности и метрик.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// APIKeysFile — файл с SHA-256 хешами API ключей.
+	APIKeysFile string `yaml:"api_keys_file" json:"api_keys_file"`
+	JWT         JWT    `yaml:"jwt" json:"jwt"`
+	// PolicyFile — политика ролей и прав; пусто — любой прошедший
+	// аутентификацию клиент может всё.
+	PolicyFile string `yaml:"policy_file" json:"policy_file"`
+	// AuditFile — журнал отказов в доступе; пусто — общий журнал.
+	AuditFile string `yaml:"audit_file" json:"audit_file"`
+}
+
+type Config struct {
+	HTTP       Listener   `yaml:"http" json:"http"`
+	GRPC       GRPC       `yaml:"grpc" json:"grpc"`
+	Calculator Calculator `yaml:"calculator" json:"calculator"`
+	Sessions   Sessions   `yaml:"sessions" json:"sessions"`
+	Jobs       Jobs       `yaml:"jobs" json:"jobs"`
+	Batch      Batch      `yaml:"batch" json:"batch"`
+	Storage    Storage    `yaml:"storage" json:"storage"`
+	Shutdown   Shutdown   `yaml:"shutdown" json:"shutdown"`
+	Log        Log        `yaml:"log" json:"log"`
+	Tracing    Tracing    `yaml:"tracing" json:"tracing"`
+	Middleware Middleware `yaml:"middleware" json:"middleware"`
+	Limits     Limits     `yaml:"limits" json:"limits"`
+	RateLimit  RateLimit  `yaml:"rate_limit" json:"rate_limit"`
+	Auth       Auth       `yaml:"auth" json:"auth"`
+}
+
+func Default() Config {
+	return Config{
+		HTTP: Listener{Addr: ":8081", TLS: TLS{ReloadInterval: Duration(10 * time.Second)}},
+		GRPC: GRPC{Listener: Listener{Addr: ":50051", TLS: TLS{ReloadInterval: Duration(10 * time.Second)}}},
+		Calculator: Calculator{
+			Workers: 64,
+			Latency: Duration(50 * time.Millisecond),
+		},
+		Sessions: Sessions{
+			TTL:          Duration(30 * time.Minute),
+			MaxVariables: 1000,
+		},
+		Jobs: Jobs{
+			Workers:   4,
+			QueueSize: 100,
+			Retention: Duration(time.Hour),
+		},
+		Batch: Batch{Concurrency: 16},
+		Storage: Storage{
+			CompactEvery:    1000,
+			CompactInterval: Duration(5 * time.Minute),
+		},
+		Shutdown: Shutdown{Timeout: Duration(30 * time.Second)},
+		Log:      Log{Level: "info"},
+		Tracing:  Tracing{Exporter: "none"},
+		Middleware: Middleware{
+			Order: slices.Clone(middlewares),
+		},
+		Limits: Limits{
+			MaxRequestBytes: 4 << 20,
+			MaxInstructions: 10000,
+			MaxNameLength:   256,
+			MaxDepth:        1000,
+			MaxFanIn:        1000,
+		},
+	}
+}
+
+// middlewares — имена промежуточных обработчиков для middleware.order.
+var middlewares = []string{"tracing", "logging", "metrics", "recovery", "auth", "authz", "ratelimit", "limits"}
+
+var logLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// LogLevel возвращает уровень журнала; неизвестное значение отклоняет
+// Validate.
+func (c Config) LogLevel() slog.Level {
+	return logLevels[c.Log.Level]
+}
+
+// Validate проверяет настройки и возвращает все найденные ошибки сразу.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	listeners := []struct {
+		key string
+		l   Listener
+	}{{"http", c.HTTP}, {"grpc", c.GRPC.Listener}}
+	for _, ln := range listeners {
+		_, port, err := net.SplitHostPort(ln.l.Addr)
+		check(err == nil && port != "", ln.key+".addr", "must be host:port, got %q", ln.l.Addr)
+		check(ln.l.TLS.ReloadInterval >= 0, ln.key+".tls.reload_interval", "must not be negative, got %s", ln.l.TLS.ReloadInterval)
+		check(!ln.l.TLS.RequireClientCert || ln.l.TLS.ClientCAFile != "", ln.key+".tls.require_client_cert", "requires client_ca_file")
+		if !ln.l.TLS.Enabled() {
+			check(ln.l.TLS.ClientCAFile == "", ln.key+".tls.client_ca_file", "requires cert_file and key_file")
+			continue
+		}
+		check(ln.l.TLS.CertFile != "" && ln.l.TLS.KeyFile != "", ln.key+".tls", "cert_file and key_file must be set together")
+		for _, path := range []string{ln.l.TLS.CertFile, ln.l.TLS.KeyFile, ln.l.TLS.ClientCAFile} {
+			if path == "" {
+				continue
+			}
+			_, err := os.Stat(path)
+			check(err == nil, ln.key+".tls", "%v", err)
+		}
+	}
+	check(c.HTTP.Addr != c.GRPC.Addr, "grpc.addr", "must differ from http.addr %q", c.HTTP.Addr)
+
+	check(c.Calculator.Workers > 0, "calculator.workers", "must be positive, got %d", c.Calculator.Workers)
+	check(c.Calculator.Latency >= 0, "calculator.latency", "must not be negative, got %s", c.Calculator.Latency)
+	check(c.Sessions.TTL > 0, "sessions.ttl", "must be positive, got %s", c.Sessions.TTL)
+	check(c.Sessions.MaxVariables >= 0, "sessions.max_variables", "must not be negative, got %d", c.Sessions.MaxVariables)
+	check(c.Jobs.Workers > 0, "jobs.workers", "must be positive, got %d", c.Jobs.Workers)
+	check(c.Jobs.QueueSize > 0, "jobs.queue_size", "must be positive, got %d", c.Jobs.QueueSize)
+	check(c.Jobs.Retention > 0, "jobs.retention", "must be positive, got %s", c.Jobs.Retention)
+	check(c.Batch.Concurrency > 0, "batch.concurrency", "must be positive, got %d", c.Batch.Concurrency)
+	check(c.Storage.CompactEvery >= 0, "storage.compact_every", "must not be negative, got %d", c.Storage.CompactEvery)
+	check(c.Storage.CompactInterval >= 0, "storage.compact_interval", "must not be negative, got %s", c.Storage.CompactInterval)
+	check(c.Shutdown.Timeout > 0, "shutdown.timeout", "must be positive, got %s", c.Shutdown.Timeout)
+	check(c.Shutdown.Delay >= 0, "shutdown.delay", "must not be negative, got %s", c.Shutdown.Delay)
+
+	_, ok := logLevels[c.Log.Level]
+	check(ok, "log.level", "must be one of debug, info, warn, error, got %q", c.Log.Level)
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "file":
+		check(c.Tracing.File != "", "tracing.file", "must be set for exporter file")
+	default:
+		check(false, "tracing.exporter", "must be one of none, stdout, file, got %q", c.Tracing.Exporter)
+	}
+
+	position := make(map[string]int, len(c.Middleware.Order))
+	for i, name := range c.Middleware.Order {
+		_, dup := position[name]
+		check(!dup, "middleware.order", "duplicate middleware %q", name)
+		check(slices.Contains(middlewares, name), "middleware.order", "unknown middleware %q", name)
+		if !dup {
+			position[name] = i
+		}
+	}
+	// Включённые настройками обработчики должны быть в цепочке, иначе
+	// настройки молча не действуют.
+	required := []struct {
+		name   string
+		needed bool
+		reason string
+	}{
+		{"auth", c.Auth.Enabled, "auth.enabled"},
+		{"authz", c.Auth.PolicyFile != "", "auth.policy_file"},
+		{"ratelimit", c.RateLimit.RequestsPerSecond > 0 || c.RateLimit.InstructionsPerSecond > 0 || c.RateLimit.DailyInstructions > 0, "rate_limit"},
+		{"limits", true, "limits.max_request_bytes"},
+	}
+	for _, r := range required {
+		_, ok := position[r.name]
+		check(!r.needed || ok, "middleware.order", "%q is required by %s", r.name, r.reason)
+	}
+	// Права и ограничения клиента определяются по личности, которую
+	// устанавливает auth.
+	if authPos, ok := position["auth"]; ok {
+		for _, name := range []string{"authz", "ratelimit"} {
+			pos, ok := position[name]
+			check(!ok || pos > authPos, "middleware.order", "%q must come after \"auth\"", name)
+		}
+	}
+	if c.Auth.Enabled {
+		check(c.Auth.APIKeysFile != "" || c.Auth.JWT.HS256SecretFile != "" || c.Auth.JWT.RS256PublicKeyFile != "" ||
+			c.HTTP.TLS.ClientCAFile != "" || c.GRPC.TLS.ClientCAFile != "",
+			"auth", "enabled but none of api_keys_file, a jwt key or tls.client_ca_file is set")
+	} else {
+		check(c.Auth.PolicyFile == "", "auth.policy_file", "requires auth.enabled")
+	}
+	check(c.Limits.MaxRequestBytes > 0, "limits.max_request_bytes", "must be positive, got %d", c.Limits.MaxRequestBytes)
+	check(c.Limits.MaxEvaluationTime >= 0, "limits.max_evaluation_time", "must not be negative, got %s", c.Limits.MaxEvaluationTime)
+	counts := []struct {
+		key string
+		v   int
+	}{
+		{"limits.max_instructions", c.Limits.MaxInstructions},
+		{"limits.max_name_length", c.Limits.MaxNameLength},
+		{"limits.max_depth", c.Limits.MaxDepth},
+		{"limits.max_fan_in", c.Limits.MaxFanIn},
+		{"limits.max_value_bits", c.Limits.MaxValueBits},
+		{"limits.max_in_flight_executions", c.Limits.MaxInFlightExecutions},
+		{"limits.max_in_flight_instructions", c.Limits.MaxInFlightInstructions},
+		{"rate_limit.requests_per_second", c.RateLimit.RequestsPerSecond},
+		{"rate_limit.request_burst", c.RateLimit.RequestBurst},
+		{"rate_limit.instructions_per_second", c.RateLimit.InstructionsPerSecond},
+		{"rate_limit.instruction_burst", c.RateLimit.InstructionBurst},
+		{"rate_limit.daily_instructions", c.RateLimit.DailyInstructions},
+	}
+	for _, n := range counts {
+		check(n.v >= 0, n.key, "must not be negative, got %d", n.v)
+	}
+	check(c.Limits.MaxValueBits <= 64, "limits.max_value_bits", "must not exceed 64, got %d", c.Limits.MaxValueBits)
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateMiddlewareOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   []string
+		setup   func(*Config)
+		wantErr string
+	}{
+		{name: "default", order: Default().Middleware.Order},
+		{name: "minimal", order: []string{"limits"}},
+		{name: "unknown", order: []string{"limits", "gzip"}, wantErr: `unknown middleware "gzip"`},
+		{name: "duplicate", order: []string{"limits", "logging", "logging"}, wantErr: `duplicate middleware "logging"`},
+		{name: "limits missing", order: []string{"logging"}, wantErr: `"limits" is required`},
+		{
+			name:    "auth missing",
+			order:   []string{"limits"},
+			setup:   func(c *Config) { c.Auth.Enabled = true },
+			wantErr: `"auth" is required by auth.enabled`,
+		},
+		{
+			name:    "authz missing",
+			order:   []string{"auth", "limits"},
+			setup:   func(c *Config) { c.Auth.Enabled, c.Auth.PolicyFile = true, "policy.yaml" },
+			wantErr: `"authz" is required by auth.policy_file`,
+		},
+		{
+			name:    "ratelimit missing",
+			order:   []string{"limits"},
+			setup:   func(c *Config) { c.RateLimit.DailyInstructions = 1000 },
+			wantErr: `"ratelimit" is required by rate_limit`,
+		},
+		{
+			name:    "authz before auth",
+			order:   []string{"authz", "auth", "limits"},
+			setup:   func(c *Config) { c.Auth.Enabled, c.Auth.PolicyFile = true, "policy.yaml" },
+			wantErr: `"authz" must come after "auth"`,
+		},
+		{
+			name:    "ratelimit before auth",
+			order:   []string{"ratelimit", "auth", "limits"},
+			setup:   func(c *Config) { c.Auth.Enabled = true },
+			wantErr: `"ratelimit" must come after "auth"`,
+		},
+		{
+			name:  "ratelimit without auth",
+			order: []string{"ratelimit", "limits"},
+			setup: func(c *Config) { c.RateLimit.RequestsPerSecond = 10 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Default()
+			c.Auth.APIKeysFile = "keys.txt"
+			if tt.setup != nil {
+				tt.setup(&c)
+			}
+			c.Middleware.Order = tt.order
+			err := c.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error containing %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix — префикс переменных окружения: ключ http.addr читается из
+// CALCULATOR_HTTP_ADDR.
+const EnvPrefix = "CALCULATOR_"
+
+// setting связывает ключ конфигурации с полем Config. Имя флага
+// совпадает с ключом, в котором точки заменены дефисами, если не задано
+// явно.
+type setting struct {
+	key   string
+	flag  string
+	usage string
+	value flag.Value
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{key: "http.addr", usage: "адрес HTTP сервера", value: (*stringValue)(&c.HTTP.Addr)},
+		{key: "http.tls.cert_file", usage: "сертификат TLS HTTP сервера", value: (*stringValue)(&c.HTTP.TLS.CertFile)},
+		{key: "http.tls.key_file", usage: "ключ TLS HTTP сервера", value: (*stringValue)(&c.HTTP.TLS.KeyFile)},
+		{key: "http.tls.client_ca_file", usage: "центры сертификации клиентов HTTP сервера (mTLS)", value: (*stringValue)(&c.HTTP.TLS.ClientCAFile)},
+		{key: "http.tls.require_client_cert", usage: "требовать сертификат клиента HTTP сервера", value: (*boolValue)(&c.HTTP.TLS.RequireClientCert)},
+		{key: "http.tls.reload_interval", usage: "период проверки файлов TLS HTTP сервера; 0 — не перечитывать", value: &c.HTTP.TLS.ReloadInterval},
+		{key: "grpc.addr", usage: "адрес gRPC сервера", value: (*stringValue)(&c.GRPC.Addr)},
+		{key: "grpc.tls.cert_file", usage: "сертификат TLS gRPC сервера", value: (*stringValue)(&c.GRPC.TLS.CertFile)},
+		{key: "grpc.tls.key_file", usage: "ключ TLS gRPC сервера", value: (*stringValue)(&c.GRPC.TLS.KeyFile)},
+		{key: "grpc.tls.client_ca_file", usage: "центры сертификации клиентов gRPC сервера (mTLS)", value: (*stringValue)(&c.GRPC.TLS.ClientCAFile)},
+		{key: "grpc.tls.require_client_cert", usage: "требовать сертификат клиента gRPC сервера", value: (*boolValue)(&c.GRPC.TLS.RequireClientCert)},
+		{key: "grpc.tls.reload_interval", usage: "период проверки файлов TLS gRPC сервера; 0 — не перечитывать", value: &c.GRPC.TLS.ReloadInterval},
+		{key: "grpc.reflection", usage: "включить gRPC server reflection и GET /api/descriptor", value: (*boolValue)(&c.GRPC.Reflection)},
+		{key: "calculator.workers", usage: "размер пула вычисления переменных", value: (*intValue)(&c.Calculator.Workers)},
+		{key: "calculator.latency", usage: "задержка вычисления одной переменной", value: &c.Calculator.Latency},
+		{key: "sessions.ttl", usage: "время жизни сессии", value: &c.Sessions.TTL},
+		{key: "sessions.max_variables", usage: "максимум переменных в сессии", value: (*intValue)(&c.Sessions.MaxVariables)},
+		{key: "jobs.workers", usage: "число одновременно выполняемых заданий", value: (*intValue)(&c.Jobs.Workers)},
+		{key: "jobs.queue_size", usage: "размер очереди заданий", value: (*intValue)(&c.Jobs.QueueSize)},
+		{key: "jobs.retention", usage: "срок хранения завершённых заданий", value: &c.Jobs.Retention},
+		{key: "jobs.webhook.allowed_hosts", usage: "узлы для уведомлений о заданиях через запятую; пусто — любой", value: (*listValue)(&c.Jobs.Webhook.AllowedHosts)},
+		{key: "jobs.webhook.allow_private_networks", usage: "разрешить уведомления на loopback, частные и link-local адреса", value: (*boolValue)(&c.Jobs.Webhook.AllowPrivateNetworks)},
+		{key: "batch.concurrency", usage: "число одновременно вычисляемых строк пакета", value: (*intValue)(&c.Batch.Concurrency)},
+		{key: "storage.data_dir", flag: "data-dir", usage: "каталог для хранения данных; пусто — хранить в памяти", value: (*stringValue)(&c.Storage.DataDir)},
+		{key: "storage.compact_every", usage: "число записей журнала до сжатия", value: (*intValue)(&c.Storage.CompactEvery)},
+		{key: "storage.compact_interval", usage: "период фонового сжатия журнала", value: &c.Storage.CompactInterval},
+		{key: "storage.no_sync", usage: "не вызывать fsync после записи журнала", value: (*boolValue)(&c.Storage.NoSync)},
+		{key: "shutdown.timeout", usage: "время на завершение текущих вызовов при остановке", value: &c.Shutdown.Timeout},
+		{key: "shutdown.delay", usage: "пауза между переходом в неготовность и остановкой приёма запросов", value: &c.Shutdown.Delay},
+		{key: "log.level", usage: "уровень журнала: debug, info, warn, error", value: (*stringValue)(&c.Log.Level)},
+		{key: "tracing.exporter", usage: "экспорт спанов: none, stdout, file", value: (*stringValue)(&c.Tracing.Exporter)},
+		{key: "tracing.file", usage: "файл спанов для экспортёра file", value: (*stringValue)(&c.Tracing.File)},
+		{key: "middleware.order", usage: "промежуточные обработчики через запятую, от внешнего к внутреннему", value: (*listValue)(&c.Middleware.Order)},
+		{key: "rate_limit.requests_per_second", usage: "запросов в секунду от одного клиента; 0 — без ограничения", value: (*intValue)(&c.RateLimit.RequestsPerSecond)},
+		{key: "rate_limit.request_burst", usage: "запросов подряд от одного клиента", value: (*intValue)(&c.RateLimit.RequestBurst)},
+		{key: "rate_limit.instructions_per_second", usage: "инструкций в секунду от одного клиента; 0 — без ограничения", value: (*intValue)(&c.RateLimit.InstructionsPerSecond)},
+		{key: "rate_limit.instruction_burst", usage: "инструкций подряд от одного клиента", value: (*intValue)(&c.RateLimit.InstructionBurst)},
+		{key: "rate_limit.daily_instructions", usage: "квота инструкций клиента за сутки (UTC); 0 — без квоты", value: (*intValue)(&c.RateLimit.DailyInstructions)},
+		{key: "auth.enabled", usage: "требовать аутентификацию клиентов", value: (*boolValue)(&c.Auth.Enabled)},
+		{key: "auth.api_keys_file", usage: "файл с SHA-256 хешами API ключей", value: (*stringValue)(&c.Auth.APIKeysFile)},
+		{key: "auth.jwt.hs256_secret_file", usage: "файл секрета для JWT HS256", value: (*stringValue)(&c.Auth.JWT.HS256SecretFile)},
+		{key: "auth.jwt.rs256_public_key_file", usage: "открытый ключ RSA (PEM) для JWT RS256", value: (*stringValue)(&c.Auth.JWT.RS256PublicKeyFile)},
+		{key: "auth.jwt.issuer", usage: "ожидаемое значение iss в JWT", value: (*stringValue)(&c.Auth.JWT.Issuer)},
+		{key: "auth.jwt.audience", usage: "ожидаемое значение aud в JWT", value: (*stringValue)(&c.Auth.JWT.Audience)},
+		{key: "auth.policy_file", usage: "файл политики ролей и прав", value: (*stringValue)(&c.Auth.PolicyFile)},
+		{key: "auth.audit_file", usage: "журнал отказов в доступе; пусто — общий журнал", value: (*stringValue)(&c.Auth.AuditFile)},
+		{key: "limits.max_request_bytes", usage: "наибольший размер тела HTTP запроса и сообщения gRPC", value: (*intValue)(&c.Limits.MaxRequestBytes)},
+		{key: "limits.max_instructions", usage: "инструкций в одной программе; 0 — без ограничения", value: (*intValue)(&c.Limits.MaxInstructions)},
+		{key: "limits.max_name_length", usage: "длина имени переменной в байтах; 0 — без ограничения", value: (*intValue)(&c.Limits.MaxNameLength)},
+		{key: "limits.max_depth", usage: "длина цепочки зависимостей; 0 — без ограничения", value: (*intValue)(&c.Limits.MaxDepth)},
+		{key: "limits.max_fan_in", usage: "ссылок на одну переменную; 0 — без ограничения", value: (*intValue)(&c.Limits.MaxFanIn)},
+		{key: "limits.max_value_bits", usage: "разрядность значений переменных, до 64; 0 — без ограничения", value: (*intValue)(&c.Limits.MaxValueBits)},
+		{key: "limits.max_evaluation_time", usage: "время выполнения одной программы; 0 — без ограничения", value: &c.Limits.MaxEvaluationTime},
+		{key: "limits.max_in_flight_executions", usage: "программ, выполняемых одновременно; 0 — без ограничения", value: (*intValue)(&c.Limits.MaxInFlightExecutions)},
+		{key: "limits.max_in_flight_instructions", usage: "инструкций, выполняемых одновременно; 0 — без ограничения", value: (*intValue)(&c.Limits.MaxInFlightInstructions)},
+	}
+}
+
+func (s setting) flagName() string {
+	if s.flag != "" {
+		return s.flag
+	}
+	return strings.NewReplacer(".", "-", "_", "-").Replace(s.key)
+}
+
+func (s setting) envName() string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(s.key, ".", "_"))
+}
+
+// Load собирает конфигурацию. Источники применяются по возрастанию
+// приоритета: значения по умолчанию, файл (-config или
+// CALCULATOR_CONFIG), переменные окружения, флаги командной строки.
+// Результат проверяется Validate.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	path := fs.String("config", getenv(EnvPrefix+"CONFIG"), "файл конфигурации YAML или JSON")
+
+	// Флаги разбираются в отдельную копию, чтобы применить их после
+	// файла и окружения, но только те, что заданы явно.
+	flagged := Default()
+	flagSettings := flagged.settings()
+	for _, s := range flagSettings {
+		fs.Var(s.value, s.flagName(), s.usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+	if fs.NArg() > 0 {
+		return cfg, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	if *path != "" {
+		if err := cfg.loadFile(*path); err != nil {
+			return cfg, err
+		}
+	}
+
+	settings := cfg.settings()
+	for _, s := range settings {
+		raw, ok := lookupEnv(getenv, s.envName())
+		if !ok {
+			continue
+		}
+		if err := s.value.Set(raw); err != nil {
+			return cfg, fmt.Errorf("%s: invalid value %q: %v", s.envName(), raw, err)
+		}
+	}
+
+	byFlag := make(map[string]setting, len(settings))
+	for _, s := range settings {
+		byFlag[s.flagName()] = s
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if s, ok := byFlag[f.Name]; ok {
+			s.value.Set(f.Value.String())
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+func lookupEnv(getenv func(string) string, name string) (string, bool) {
+	v := getenv(name)
+	return v, v != ""
+}
+
+// loadFile читает файл конфигурации. Формат определяется расширением:
+// .json — JSON, остальные — YAML. Неизвестные ключи считаются ошибкой.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(c)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(c)
+		if err == io.EOF {
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return nil
+}
+
+type stringValue string
+
+func (v *stringValue) String() string     { return string(*v) }
+func (v *stringValue) Set(s string) error { *v = stringValue(s); return nil }
+
+type intValue int
+
+func (v *intValue) String() string { return strconv.Itoa(int(*v)) }
+
+func (v *intValue) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("not an integer")
+	}
+	*v = intValue(n)
+	return nil
+}
+
+// listValue — список строк; во флагах и переменных окружения значения
+// перечисляются через запятую.
+type listValue []string
+
+func (v *listValue) String() string { return strings.Join(*v, ",") }
+
+func (v *listValue) Set(s string) error {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*v = list
+	return nil
+}
+
+type boolValue bool
+
+func (v *boolValue) String() string   { return strconv.FormatBool(bool(*v)) }
+func (v *boolValue) IsBoolFlag() bool { return true }
+
+func (v *boolValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("not a boolean")
+	}
+	*v = boolValue(b)
+	return nil
+}
+
+// Set позволяет задавать Duration флагом и переменной окружения.
+func (d *Duration) Set(s string) error {
+	return d.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func envFunc(env map[string]string) func(string) string {
+	return func(name string) string { return env[name] }
+}
+
+// Источники применяются по возрастанию приоритета: значения по
+// умолчанию, файл, окружение, флаги.
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, "config.yaml", `
+http:
+  addr: ":1001"
+calculator:
+  workers: 10
+sessions:
+  ttl: 1m
+jobs:
+  webhook:
+    allowed_hosts: [file.example.com]
+`)
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		addr    string
+		workers int
+		ttl     time.Duration
+		hosts   string
+	}{
+		{
+			name:    "defaults",
+			addr:    Default().HTTP.Addr,
+			workers: Default().Calculator.Workers,
+			ttl:     time.Duration(Default().Sessions.TTL),
+		},
+		{
+			name:    "file over defaults",
+			args:    []string{"-config", file},
+			addr:    ":1001",
+			workers: 10,
+			ttl:     time.Minute,
+			hosts:   "file.example.com",
+		},
+		{
+			name:    "file from environment",
+			env:     map[string]string{"CALCULATOR_CONFIG": file},
+			addr:    ":1001",
+			workers: 10,
+			ttl:     time.Minute,
+			hosts:   "file.example.com",
+		},
+		{
+			name: "environment over file",
+			args: []string{"-config", file},
+			env: map[string]string{
+				"CALCULATOR_HTTP_ADDR":                  ":2002",
+				"CALCULATOR_CALCULATOR_WORKERS":         "20",
+				"CALCULATOR_JOBS_WEBHOOK_ALLOWED_HOSTS": "a.example.com, b.example.com",
+			},
+			addr:    ":2002",
+			workers: 20,
+			ttl:     time.Minute,
+			hosts:   "a.example.com,b.example.com",
+		},
+		{
+			name:    "flags over environment",
+			args:    []string{"-config", file, "-http-addr", ":3003", "-sessions-ttl", "2m"},
+			env:     map[string]string{"CALCULATOR_HTTP_ADDR": ":2002", "CALCULATOR_CALCULATOR_WORKERS": "20"},
+			addr:    ":3003",
+			workers: 20,
+			ttl:     2 * time.Minute,
+			hosts:   "file.example.com",
+		},
+		{
+			name:    "flag equal to default still overrides",
+			args:    []string{"-config", file, "-calculator-workers", "64"},
+			addr:    ":1001",
+			workers: 64,
+			ttl:     time.Minute,
+			hosts:   "file.example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load(tt.args, envFunc(tt.env))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.HTTP.Addr != tt.addr || cfg.Calculator.Workers != tt.workers || time.Duration(cfg.Sessions.TTL) != tt.ttl {
+				t.Fatalf("addr %q, workers %d, ttl %s; want %q, %d, %s", cfg.HTTP.Addr, cfg.Calculator.Workers, cfg.Sessions.TTL, tt.addr, tt.workers, tt.ttl)
+			}
+			if hosts := strings.Join(cfg.Jobs.Webhook.AllowedHosts, ","); hosts != tt.hosts {
+				t.Fatalf("allowed hosts = %q, want %q", hosts, tt.hosts)
+			}
+		})
+	}
+}
+
+// JSON файл разбирается так же, как YAML.
+func TestLoadJSONFile(t *testing.T) {
+	file := writeFile(t, "config.json", `{"grpc": {"addr": ":4004", "reflection": true}, "limits": {"max_value_bits": 32}}`)
+	cfg, err := Load([]string{"-config", file}, envFunc(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GRPC.Addr != ":4004" || !cfg.GRPC.Reflection || cfg.Limits.MaxValueBits != 32 {
+		t.Fatalf("config = %+v", cfg)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		args    []string
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "malformed yaml", file: "config.yaml", content: "http: [addr", wantErr: "parse config"},
+		{name: "unknown yaml key", file: "config.yaml", content: "http:\n  address: :80\n", wantErr: "field address not found"},
+		{name: "unknown json key", file: "config.json", content: `{"http": {"address": ":80"}}`, wantErr: `unknown field "address"`},
+		{name: "wrong yaml type", file: "config.yaml", content: "calculator:\n  workers: many\n", wantErr: "parse config"},
+		{name: "bad yaml duration", file: "config.yaml", content: "sessions:\n  ttl: forever\n", wantErr: "parse config"},
+		{name: "missing file", args: []string{"-config", "/nonexistent/config.yaml"}, wantErr: "read config"},
+		{name: "bad env integer", env: map[string]string{"CALCULATOR_CALCULATOR_WORKERS": "ten"}, wantErr: `CALCULATOR_CALCULATOR_WORKERS: invalid value "ten": not an integer`},
+		{name: "bad env boolean", env: map[string]string{"CALCULATOR_STORAGE_NO_SYNC": "maybe"}, wantErr: `CALCULATOR_STORAGE_NO_SYNC: invalid value "maybe": not a boolean`},
+		{name: "bad env duration", env: map[string]string{"CALCULATOR_SESSIONS_TTL": "soon"}, wantErr: "CALCULATOR_SESSIONS_TTL: invalid value"},
+		{name: "bad flag", args: []string{"-calculator-workers", "x"}, wantErr: "invalid value"},
+		{name: "unknown flag", args: []string{"-no-such-flag"}, wantErr: "flag provided but not defined"},
+		{name: "extra arguments", args: []string{"serve"}, wantErr: "unexpected arguments"},
+		{name: "invalid result", env: map[string]string{"CALCULATOR_LIMITS_MAX_VALUE_BITS": "65"}, wantErr: "invalid configuration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, tt.file, tt.content)}, args...)
+			}
+			_, err := Load(args, envFunc(tt.env))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Package health определяет готовность сервера принимать запросы.
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// Check проверяет одну зависимость; nil означает, что она исправна.
+type Check func(ctx context.Context) error
+
+// Report — результат проверки готовности. Checks содержит "ok" или
+// текст ошибки для каждой проверки.
+type Report struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Checker собирает проверки готовности. После SetShuttingDown сервер
+// считается неготовым независимо от проверок.
+type Checker struct {
+	mu     sync.Mutex
+	checks map[string]Check
+
+	shuttingDown atomic.Bool
+}
+
+func NewChecker() *Checker {
+	return &Checker{checks: make(map[string]Check)}
+}
+
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// SetShuttingDown отмечает начало остановки сервера.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+func (c *Checker) ShuttingDown() bool {
+	return c.shuttingDown.Load()
+}
+
+// Ready выполняет все проверки.
+func (c *Checker) Ready(ctx context.Context) Report {
+	c.mu.Lock()
+	names := make([]string, 0, len(c.checks))
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		names = append(names, name)
+		checks[name] = check
+	}
+	c.mu.Unlock()
+	sort.Strings(names)
+
+	report := Report{Ready: true, Checks: make(map[string]string, len(names)+1)}
+	record := func(name string, err error) {
+		if err != nil {
+			report.Ready = false
+			report.Checks[name] = err.Error()
+			return
+		}
+		report.Checks[name] = "ok"
+	}
+
+	if c.ShuttingDown() {
+		record("shutdown", ErrShuttingDown)
+	} else {
+		record("shutdown", nil)
+	}
+	for _, name := range names {
+		record(name, checks[name](ctx))
+	}
+	return report
+}
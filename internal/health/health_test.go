@@ -0,0 +1,36 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReady(t *testing.T) {
+	c := NewChecker()
+	c.Add("db", func(context.Context) error { return nil })
+	report := c.Ready(context.Background())
+	if !report.Ready || report.Checks["db"] != "ok" || report.Checks["shutdown"] != "ok" {
+		t.Fatalf("report = %+v, want ready", report)
+	}
+
+	c.Add("queue", func(context.Context) error { return errors.New("queue full") })
+	report = c.Ready(context.Background())
+	if report.Ready || report.Checks["queue"] != "queue full" || report.Checks["db"] != "ok" {
+		t.Fatalf("report = %+v, want not ready because of queue", report)
+	}
+}
+
+// После SetShuttingDown сервер неготов, даже если все проверки проходят.
+func TestShuttingDown(t *testing.T) {
+	c := NewChecker()
+	c.Add("db", func(context.Context) error { return nil })
+	if c.ShuttingDown() {
+		t.Fatal("new checker is shutting down")
+	}
+	c.SetShuttingDown()
+	report := c.Ready(context.Background())
+	if !c.ShuttingDown() || report.Ready || report.Checks["shutdown"] != ErrShuttingDown.Error() || report.Checks["db"] != "ok" {
+		t.Fatalf("report = %+v, want not ready because of shutdown", report)
+	}
+}
@@ -0,0 +1,403 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"calculator/internal/logging"
+	"calculator/internal/ratelimit"
+	"calculator/internal/service"
+)
+
+var (
+	ErrNotFound  = errors.New("job not found")
+	ErrQueueFull = errors.New("job queue is full")
+	ErrFinished  = errors.New("job already finished")
+	ErrClosed    = errors.New("job manager closed")
+)
+
+type State string
+
+const (
+	StateQueued    State = "queued"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+	StateCanceled  State = "canceled"
+)
+
+func (s State) Finished() bool {
+	return s == StateSucceeded || s == StateFailed || s == StateCanceled
+}
+
+type Config struct {
+	// Workers — число одновременно выполняемых заданий.
+	Workers int
+	// QueueSize — максимальное число заданий в очереди.
+	QueueSize int
+	// Retention — сколько хранить завершённое задание.
+	Retention time.Duration
+	// Webhook — параметры доставки уведомлений о завершении.
+	Webhook WebhookConfig
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Workers:   4,
+		QueueSize: 100,
+		Retention: time.Hour,
+		Webhook:   DefaultWebhookConfig(),
+	}
+}
+
+type Progress struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+}
+
+// Info — снимок состояния задания.
+type Info struct {
+	ID         string               `json:"id"`
+	State      State                `json:"state"`
+	Progress   Progress             `json:"progress"`
+	Items      []service.ResultItem `json:"items,omitempty"`
+	Inputs     map[string]int64     `json:"inputs,omitempty"`
+	Error      string               `json:"error,omitempty"`
+	CreatedAt  time.Time            `json:"created_at"`
+	StartedAt  *time.Time           `json:"started_at,omitempty"`
+	FinishedAt *time.Time           `json:"finished_at,omitempty"`
+	Webhook    *Webhook             `json:"webhook,omitempty"`
+}
+
+type job struct {
+	instructions []service.Instruction
+	ctx          context.Context
+	cancel       context.CancelFunc
+	callback     *Callback
+
+	// Поля ниже защищены мьютексом менеджера.
+	info       Info
+	deliveries int
+}
+
+// snapshot копирует состояние задания, которое может меняться после
+// возврата из менеджера.
+func (j *job) snapshot() Info {
+	info := j.info
+	if j.info.Webhook != nil {
+		webhook := *j.info.Webhook
+		webhook.Attempts = append([]Attempt(nil), webhook.Attempts...)
+		info.Webhook = &webhook
+	}
+	return info
+}
+
+// Manager выполняет программы асинхронно: задания попадают в
+// ограниченную очередь и выполняются фиксированным числом исполнителей
+// на общем пуле калькулятора.
+type Manager struct {
+	calc   *service.CalculatorService
+	cfg    Config
+	client *http.Client
+
+	mu   sync.Mutex
+	jobs map[string]*job
+	// queue — задания, ожидающие исполнителя; отменённое задание сразу
+	// убирается из очереди и не занимает места.
+	queue  []*job
+	wake   *sync.Cond
+	closed bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewManager(calc *service.CalculatorService, cfg Config) *Manager {
+	def := DefaultConfig()
+	if cfg.Workers <= 0 {
+		cfg.Workers = def.Workers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = def.QueueSize
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = def.Retention
+	}
+	if cfg.Webhook.MaxAttempts <= 0 {
+		cfg.Webhook.MaxAttempts = def.Webhook.MaxAttempts
+	}
+	if cfg.Webhook.InitialBackoff <= 0 {
+		cfg.Webhook.InitialBackoff = def.Webhook.InitialBackoff
+	}
+	if cfg.Webhook.MaxBackoff < cfg.Webhook.InitialBackoff {
+		cfg.Webhook.MaxBackoff = max(def.Webhook.MaxBackoff, cfg.Webhook.InitialBackoff)
+	}
+	if cfg.Webhook.Timeout <= 0 {
+		cfg.Webhook.Timeout = def.Webhook.Timeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		calc:   calc,
+		cfg:    cfg,
+		client: newWebhookClient(cfg.Webhook),
+		jobs:   make(map[string]*job),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	m.wake = sync.NewCond(&m.mu)
+	for i := 0; i < cfg.Workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+	m.wg.Add(1)
+	go m.sweep()
+	return m
+}
+
+// Close отменяет выполняющиеся задания и доставку уведомлений и
+// останавливает исполнителей. Выполняющиеся задания не дорабатывают до
+// конца, а завершаются в состоянии canceled; задания из очереди
+// остаются в состоянии queued.
+func (m *Manager) Close() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	m.cancel()
+	m.wake.Broadcast()
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// Submit проверяет программу и ставит её в очередь. Если задан
+// callback, по завершении задания на его адрес отправляется уведомление.
+// Из ctx заданию передаётся идентификатор запроса для журнала; отмена
+// ctx на задание не влияет.
+func (m *Manager) Submit(ctx context.Context, instructions []service.Instruction, inputs map[string]int64, callback *Callback) (Info, error) {
+	if err := m.calc.Validate(instructions); err != nil {
+		return Info{}, err
+	}
+	if callback != nil {
+		if err := callback.validate(m.cfg.Webhook); err != nil {
+			return Info{}, err
+		}
+	}
+	bound, err := service.Bind(instructions, inputs)
+	if err != nil {
+		return Info{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return Info{}, err
+	}
+
+	total := 0
+	for _, instr := range instructions {
+		if instr.Type == "calc" || instr.Type == "input" {
+			total++
+		}
+	}
+
+	// Задание живёт дольше запроса, но сохраняет его идентификатор и
+	// клиента, с квоты которого списываются инструкции.
+	jobCtx := logging.WithRequestID(m.ctx, logging.RequestID(ctx))
+	if client, ok := ratelimit.ClientFromContext(ctx); ok {
+		jobCtx = ratelimit.WithClient(jobCtx, client)
+	}
+	jobCtx, cancel := context.WithCancel(jobCtx)
+	j := &job{
+		instructions: instructions,
+		ctx:          jobCtx,
+		cancel:       cancel,
+		callback:     callback,
+		info: Info{
+			ID:        id,
+			State:     StateQueued,
+			Progress:  Progress{Total: total},
+			Inputs:    bound,
+			CreatedAt: time.Now().UTC(),
+		},
+	}
+	if callback != nil {
+		j.info.Webhook = &Webhook{URL: callback.URL}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		cancel()
+		return Info{}, ErrClosed
+	}
+	if len(m.queue) >= m.cfg.QueueSize {
+		cancel()
+		return Info{}, ErrQueueFull
+	}
+	m.queue = append(m.queue, j)
+	m.wake.Signal()
+	m.jobs[id] = j
+	return j.snapshot(), nil
+}
+
+func (m *Manager) Get(id string) (Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return Info{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return j.snapshot(), nil
+}
+
+// QueueUsage возвращает число заданий в очереди и её размер.
+func (m *Manager) QueueUsage() (queued, size int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.queue), m.cfg.QueueSize
+}
+
+// Cancel отменяет задание. Задание из очереди отменяется сразу,
+// выполняющееся — через отмену контекста его вычислений.
+func (m *Manager) Cancel(id string) (Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return Info{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if j.info.State.Finished() {
+		return j.snapshot(), fmt.Errorf("%w: %s is %s", ErrFinished, id, j.info.State)
+	}
+	j.cancel()
+	if j.info.State == StateQueued {
+		m.dequeueLocked(j)
+		m.finishLocked(j, StateCanceled, nil, context.Canceled)
+	}
+	return j.snapshot(), nil
+}
+
+func (m *Manager) dequeueLocked(j *job) {
+	for i, queued := range m.queue {
+		if queued == j {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+func (m *Manager) worker() {
+	defer m.wg.Done()
+	for {
+		m.mu.Lock()
+		for len(m.queue) == 0 && m.ctx.Err() == nil {
+			m.wake.Wait()
+		}
+		if m.ctx.Err() != nil {
+			m.mu.Unlock()
+			return
+		}
+		j := m.queue[0]
+		m.queue[0] = nil
+		m.queue = m.queue[1:]
+		m.mu.Unlock()
+		m.run(j)
+	}
+}
+
+func (m *Manager) run(j *job) {
+	m.mu.Lock()
+	if j.info.State != StateQueued {
+		m.mu.Unlock()
+		return
+	}
+	now := time.Now().UTC()
+	j.info.State = StateRunning
+	j.info.StartedAt = &now
+	m.mu.Unlock()
+
+	items, err := m.calc.Run(j.ctx, j.instructions, j.info.Inputs, service.WithEvents(func(ev service.Event) {
+		if ev.Type != service.EventCompleted {
+			return
+		}
+		m.mu.Lock()
+		j.info.Progress.Completed++
+		m.mu.Unlock()
+	}))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case err == nil:
+		m.finishLocked(j, StateSucceeded, items, nil)
+	case j.ctx.Err() != nil:
+		m.finishLocked(j, StateCanceled, nil, j.ctx.Err())
+	default:
+		m.finishLocked(j, StateFailed, nil, err)
+	}
+	j.cancel()
+}
+
+func (m *Manager) finishLocked(j *job, state State, items []service.ResultItem, err error) {
+	now := time.Now().UTC()
+	j.info.State = state
+	j.info.Items = items
+	j.info.FinishedAt = &now
+	if err != nil {
+		j.info.Error = err.Error()
+		slog.InfoContext(j.ctx, "job finished", "job_id", j.info.ID, "state", state, "error", err)
+	} else {
+		slog.InfoContext(j.ctx, "job finished", "job_id", j.info.ID, "state", state)
+	}
+	// При остановке менеджера уведомления не отправляются.
+	if j.callback != nil && m.ctx.Err() == nil {
+		m.startDeliveryLocked(j)
+	}
+}
+
+// Период проверки устаревших заданий — половина Retention в этих
+// пределах.
+const (
+	minSweepInterval = 10 * time.Millisecond
+	maxSweepInterval = time.Minute
+)
+
+// sweep удаляет завершённые задания старше Retention.
+func (m *Manager) sweep() {
+	defer m.wg.Done()
+	interval := min(max(m.cfg.Retention/2, minSweepInterval), maxSweepInterval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.mu.Lock()
+			for id, j := range m.jobs {
+				if j.info.FinishedAt != nil && now.Sub(*j.info.FinishedAt) > m.cfg.Retention && !j.info.Webhook.delivering() {
+					delete(m.jobs, id)
+				}
+			}
+			m.mu.Unlock()
+		}
+	}
+}
+
+func newID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,201 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"calculator/internal/service"
+)
+
+// startManager запускает менеджер на калькуляторе без задержки операций,
+// если opts не задают иное.
+func startManager(t *testing.T, cfg Config, opts ...service.Option) *Manager {
+	t.Helper()
+	m := NewManager(service.NewCalculatorService(append([]service.Option{service.WithLatency(0)}, opts...)...), cfg)
+	t.Cleanup(m.Close)
+	return m
+}
+
+func mustSubmit(t *testing.T, m *Manager, instructions []service.Instruction, inputs map[string]int64) Info {
+	t.Helper()
+	info, err := m.Submit(context.Background(), instructions, inputs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+// waitState ждёт, пока задание перейдёт в состояние state.
+func waitState(t *testing.T, m *Manager, id string, state State) Info {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		info, err := m.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.State == state {
+			return info
+		}
+		time.Sleep(time.Millisecond)
+	}
+	info, _ := m.Get(id)
+	t.Fatalf("job %s is %s, want %s", id, info.State, state)
+	return Info{}
+}
+
+func TestSubmitAndPoll(t *testing.T) {
+	m := startManager(t, DefaultConfig())
+	program := []service.Instruction{
+		{Type: "input", Var: "n"},
+		{Type: "calc", Op: "*", Var: "sq", Left: "n", Right: "n"},
+		{Type: "calc", Op: "+", Var: "x", Left: "sq", Right: int64(1)},
+		{Type: "print", Var: "x"},
+	}
+	info := mustSubmit(t, m, program, map[string]int64{"n": 3})
+	if info.State != StateQueued || info.Progress != (Progress{Total: 3}) || info.Inputs["n"] != 3 {
+		t.Fatalf("submitted = %+v", info)
+	}
+
+	info = waitState(t, m, info.ID, StateSucceeded)
+	if fmt.Sprint(info.Items) != fmt.Sprint([]service.ResultItem{{Var: "x", Value: 10}}) {
+		t.Fatalf("items = %v", info.Items)
+	}
+	if info.Progress != (Progress{Total: 3, Completed: 3}) {
+		t.Fatalf("progress = %+v, want 3 of 3", info.Progress)
+	}
+	if info.StartedAt == nil || info.FinishedAt == nil || info.FinishedAt.Before(*info.StartedAt) || info.Error != "" {
+		t.Fatalf("finished job = %+v", info)
+	}
+
+	if _, err := m.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get(missing) = %v, want ErrNotFound", err)
+	}
+	if _, err := m.Submit(context.Background(), []service.Instruction{{Type: "calc", Op: "+", Var: "y", Left: "z", Right: int64(1)}}, nil, nil); !errors.Is(err, service.ErrInvalidProgram) {
+		t.Fatalf("Submit(invalid) = %v, want ErrInvalidProgram", err)
+	}
+}
+
+// Ошибка выполнения завершает задание в состоянии failed, а прогресс
+// учитывает только вычисленные переменные.
+func TestFailedJobProgress(t *testing.T) {
+	m := startManager(t, DefaultConfig(), service.WithLimits(service.Limits{MaxValueBits: 8}))
+	info := mustSubmit(t, m, []service.Instruction{
+		{Type: "calc", Op: "+", Var: "a", Left: int64(100), Right: int64(0)},
+		{Type: "calc", Op: "*", Var: "b", Left: "a", Right: "a"},
+	}, nil)
+	info = waitState(t, m, info.ID, StateFailed)
+	if info.Progress != (Progress{Total: 2, Completed: 1}) || info.Error == "" || info.Items != nil {
+		t.Fatalf("failed job = %+v", info)
+	}
+}
+
+var slowProgram = []service.Instruction{{Type: "calc", Op: "+", Var: "x", Left: int64(1), Right: int64(2)}}
+
+func TestCancel(t *testing.T) {
+	m := startManager(t, Config{Workers: 1}, service.WithLatency(time.Hour))
+	running := mustSubmit(t, m, slowProgram, nil)
+	waitState(t, m, running.ID, StateRunning)
+	queued := mustSubmit(t, m, slowProgram, nil)
+
+	// Задание из очереди отменяется сразу и освобождает место.
+	info, err := m.Cancel(queued.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.State != StateCanceled || info.StartedAt != nil {
+		t.Fatalf("canceled queued job = %+v", info)
+	}
+	if n, _ := m.QueueUsage(); n != 0 {
+		t.Fatalf("queue length after cancel = %d, want 0", n)
+	}
+
+	// Выполняющееся задание отменяется через контекст вычислений.
+	if _, err := m.Cancel(running.ID); err != nil {
+		t.Fatal(err)
+	}
+	info = waitState(t, m, running.ID, StateCanceled)
+	if info.StartedAt == nil || info.Error == "" {
+		t.Fatalf("canceled running job = %+v", info)
+	}
+
+	if _, err := m.Cancel(running.ID); !errors.Is(err, ErrFinished) {
+		t.Fatalf("second Cancel = %v, want ErrFinished", err)
+	}
+	if _, err := m.Cancel("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Cancel(missing) = %v, want ErrNotFound", err)
+	}
+}
+
+// Отменённые задания не занимают место в очереди.
+func TestQueueFull(t *testing.T) {
+	m := startManager(t, Config{Workers: 1, QueueSize: 2}, service.WithLatency(time.Hour))
+	running := mustSubmit(t, m, slowProgram, nil)
+	waitState(t, m, running.ID, StateRunning)
+
+	first := mustSubmit(t, m, slowProgram, nil)
+	mustSubmit(t, m, slowProgram, nil)
+	if _, err := m.Submit(context.Background(), slowProgram, nil, nil); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Submit to a full queue = %v, want ErrQueueFull", err)
+	}
+	if n, size := m.QueueUsage(); n != 2 || size != 2 {
+		t.Fatalf("QueueUsage = %d, %d, want 2, 2", n, size)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := m.Cancel(first.ID); err != nil {
+			t.Fatal(err)
+		}
+		first = mustSubmit(t, m, slowProgram, nil)
+	}
+	if _, err := m.Submit(context.Background(), slowProgram, nil, nil); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Submit to a full queue = %v, want ErrQueueFull", err)
+	}
+}
+
+func TestRetention(t *testing.T) {
+	m := startManager(t, Config{Retention: time.Nanosecond})
+	info := mustSubmit(t, m, slowProgram, nil)
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := m.Get(info.ID); errors.Is(err, ErrNotFound) {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("job %s was not removed after retention", info.ID)
+}
+
+// Close отменяет выполняющиеся задания, не дожидаясь их завершения, а
+// задания из очереди оставляет в состоянии queued.
+func TestClose(t *testing.T) {
+	m := NewManager(service.NewCalculatorService(service.WithLatency(time.Hour)), Config{Workers: 1})
+	running := mustSubmit(t, m, slowProgram, nil)
+	waitState(t, m, running.ID, StateRunning)
+	queued := mustSubmit(t, m, slowProgram, nil)
+
+	done := make(chan struct{})
+	go func() {
+		m.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return")
+	}
+	m.Close()
+
+	if info, _ := m.Get(running.ID); info.State != StateCanceled {
+		t.Fatalf("running job after Close = %+v, want canceled", info)
+	}
+	if info, _ := m.Get(queued.ID); info.State != StateQueued {
+		t.Fatalf("queued job after Close = %+v, want queued", info)
+	}
+	if _, err := m.Submit(context.Background(), slowProgram, nil, nil); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Submit after Close = %v, want ErrClosed", err)
+	}
+}
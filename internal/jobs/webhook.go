@@ -0,0 +1,318 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInvalidCallback = errors.New("invalid callback")
+	ErrNoCallback      = errors.New("job has no callback")
+	ErrNotFinished     = errors.New("job not finished yet")
+	ErrDelivering      = errors.New("webhook delivery in progress")
+)
+
+// Заголовки запроса с уведомлением о завершении задания.
+const (
+	HeaderSignature = "X-Calculator-Signature-256"
+	HeaderJob       = "X-Calculator-Job"
+	HeaderDelivery  = "X-Calculator-Delivery"
+)
+
+// Callback — адрес уведомления о завершении задания и общий секрет для
+// подписи.
+type Callback struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// validate проверяет адрес уведомления. Адрес, заданный именем узла,
+// проверяется ещё раз при каждом соединении, после разрешения имени.
+func (c Callback) validate(cfg WebhookConfig) error {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCallback, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be absolute http(s): %q", ErrInvalidCallback, c.URL)
+	}
+	if c.Secret == "" {
+		return fmt.Errorf("%w: secret is required", ErrInvalidCallback)
+	}
+	host := u.Hostname()
+	if !cfg.hostAllowed(host) {
+		return fmt.Errorf("%w: host %q is not in the allowed list", ErrInvalidCallback, host)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return cfg.checkAddr(addr)
+	}
+	return nil
+}
+
+// WebhookConfig — параметры доставки уведомлений.
+type WebhookConfig struct {
+	// MaxAttempts — число попыток одной доставки.
+	MaxAttempts int
+	// InitialBackoff — пауза перед второй попыткой; каждая следующая
+	// вдвое длиннее, но не больше MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Timeout — ограничение времени одной попытки.
+	Timeout time.Duration
+	// AllowedHosts — узлы, на которые разрешены уведомления: имя узла или
+	// "*.example.com" для всех его поддоменов. Пусто — любой узел.
+	AllowedHosts []string
+	// AllowPrivateNetworks разрешает уведомления на адреса loopback,
+	// частных и link-local сетей. По умолчанию они запрещены, чтобы
+	// клиент не мог обратиться через сервер к внутренним сервисам.
+	AllowPrivateNetworks bool
+}
+
+func DefaultWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Timeout:        10 * time.Second,
+	}
+}
+
+func (cfg WebhookConfig) hostAllowed(host string) bool {
+	if len(cfg.AllowedHosts) == 0 {
+		return true
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, pattern := range cfg.AllowedHosts {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedAddressSpace — адреса операторских NAT (RFC 6598); IsPrivate их
+// не включает.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// checkAddr отклоняет адреса, через которые уведомление попало бы во
+// внутреннюю сеть: loopback, частные и link-local сети (в том числе
+// адрес метаданных облака 169.254.169.254), неуказанный адрес и
+// групповые адреса.
+func (cfg WebhookConfig) checkAddr(addr netip.Addr) error {
+	if cfg.AllowPrivateNetworks {
+		return nil
+	}
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsUnspecified() || addr.IsMulticast() || sharedAddressSpace.Contains(addr) ||
+		(addr.Is4() && addr.As4()[0] == 0) {
+		return fmt.Errorf("%w: address %s is not publicly routable", ErrInvalidCallback, addr)
+	}
+	return nil
+}
+
+// newWebhookClient возвращает клиент доставки уведомлений. Адрес
+// проверяется при соединении, уже после разрешения имени, поэтому имя,
+// указывающее на внутренний адрес, не обходит проверку. Перенаправления
+// не выполняются, а ответ 3xx считается неудачной попыткой; прокси из
+// окружения не используется.
+func newWebhookClient(cfg WebhookConfig) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: cfg.Timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			return cfg.checkAddr(addr)
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Attempt — одна попытка доставки уведомления.
+type Attempt struct {
+	// Delivery — номер доставки: первая выполняется после завершения
+	// задания, следующие — по запросу повторной доставки.
+	Delivery   int       `json:"delivery"`
+	Attempt    int       `json:"attempt"`
+	Time       time.Time `json:"time"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Webhook — состояние уведомлений задания. Секрет клиентам не
+// возвращается.
+type Webhook struct {
+	URL        string    `json:"url"`
+	Delivered  bool      `json:"delivered"`
+	Delivering bool      `json:"delivering"`
+	Attempts   []Attempt `json:"attempts,omitempty"`
+}
+
+// Sign возвращает значение заголовка подписи для тела уведомления.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Redeliver заново отправляет уведомление о завершённом задании.
+func (m *Manager) Redeliver(id string) (Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return Info{}, ErrClosed
+	}
+	j, ok := m.jobs[id]
+	if !ok {
+		return Info{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	switch {
+	case j.callback == nil:
+		return j.snapshot(), fmt.Errorf("%w: %s", ErrNoCallback, id)
+	case !j.info.State.Finished():
+		return j.snapshot(), fmt.Errorf("%w: %s is %s", ErrNotFinished, id, j.info.State)
+	case j.info.Webhook.Delivering:
+		return j.snapshot(), fmt.Errorf("%w: %s", ErrDelivering, id)
+	}
+	m.startDeliveryLocked(j)
+	return j.snapshot(), nil
+}
+
+// startDeliveryLocked запускает доставку уведомления в фоне.
+func (m *Manager) startDeliveryLocked(j *job) {
+	j.deliveries++
+	j.info.Webhook.Delivering = true
+	// Уведомление содержит итог задания без сведений о самих доставках.
+	info := j.snapshot()
+	info.Webhook = nil
+	payload, err := json.Marshal(info)
+	if err != nil {
+		// Info всегда сериализуется; ошибка означает ошибку программы.
+		panic(err)
+	}
+
+	m.wg.Add(1)
+	go m.deliver(j, j.deliveries, payload)
+}
+
+func (m *Manager) deliver(j *job, delivery int, payload []byte) {
+	defer m.wg.Done()
+	cfg := m.cfg.Webhook
+	backoff := cfg.InitialBackoff
+
+	delivered := false
+	for attempt := 1; attempt <= cfg.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			if !sleep(m.ctx, backoff) {
+				break
+			}
+			backoff *= 2
+			if backoff > cfg.MaxBackoff {
+				backoff = cfg.MaxBackoff
+			}
+		}
+
+		rec := Attempt{Delivery: delivery, Attempt: attempt, Time: time.Now().UTC()}
+		code, err := m.post(j, delivery, payload)
+		rec.StatusCode = code
+		if err != nil {
+			rec.Error = err.Error()
+		}
+		m.mu.Lock()
+		j.info.Webhook.Attempts = append(j.info.Webhook.Attempts, rec)
+		m.mu.Unlock()
+		if err == nil {
+			delivered = true
+			break
+		}
+		slog.WarnContext(j.ctx, "webhook delivery attempt failed", "job_id", j.info.ID, "delivery", delivery, "attempt", attempt, "error", err)
+	}
+	if !delivered && m.ctx.Err() == nil {
+		slog.ErrorContext(j.ctx, "webhook delivery gave up", "job_id", j.info.ID, "delivery", delivery)
+	}
+
+	m.mu.Lock()
+	j.info.Webhook.Delivering = false
+	if delivered {
+		j.info.Webhook.Delivered = true
+	}
+	m.mu.Unlock()
+}
+
+// post выполняет одну попытку доставки. Успехом считается ответ 2xx.
+func (m *Manager) post(j *job, delivery int, payload []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(m.ctx, m.cfg.Webhook.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.callback.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderSignature, Sign(j.callback.Secret, payload))
+	req.Header.Set(HeaderJob, j.info.ID)
+	req.Header.Set(HeaderDelivery, strconv.Itoa(delivery))
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+func (w *Webhook) delivering() bool {
+	return w != nil && w.Delivering
+}
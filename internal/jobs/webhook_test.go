@@ -0,0 +1,217 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"calculator/internal/service"
+)
+
+func TestSign(t *testing.T) {
+	got := Sign("key", []byte("The quick brown fox jumps over the lazy dog"))
+	want := "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if got != want {
+		t.Fatalf("Sign = %s, want %s", got, want)
+	}
+}
+
+func TestCallbackValidate(t *testing.T) {
+	allowed := WebhookConfig{AllowedHosts: []string{"hooks.example.com", "*.example.org"}}
+	tests := []struct {
+		name    string
+		url     string
+		secret  string
+		cfg     WebhookConfig
+		wantErr bool
+	}{
+		{name: "public host", url: "https://hooks.example.com/job", secret: "s"},
+		{name: "public address", url: "http://203.0.113.10/job", secret: "s"},
+		{name: "no secret", url: "https://hooks.example.com/job", wantErr: true},
+		{name: "relative url", url: "/job", secret: "s", wantErr: true},
+		{name: "unsupported scheme", url: "ftp://hooks.example.com/job", secret: "s", wantErr: true},
+		{name: "loopback", url: "http://127.0.0.1:8080/job", secret: "s", wantErr: true},
+		{name: "loopback v6", url: "http://[::1]/job", secret: "s", wantErr: true},
+		{name: "mapped loopback", url: "http://[::ffff:127.0.0.1]/job", secret: "s", wantErr: true},
+		{name: "private", url: "http://10.1.2.3/job", secret: "s", wantErr: true},
+		{name: "metadata", url: "http://169.254.169.254/latest", secret: "s", wantErr: true},
+		{name: "shared address space", url: "http://100.64.0.1/job", secret: "s", wantErr: true},
+		{name: "unspecified", url: "http://0.0.0.0/job", secret: "s", wantErr: true},
+		{name: "private allowed", url: "http://10.1.2.3/job", secret: "s", cfg: WebhookConfig{AllowPrivateNetworks: true}},
+		{name: "allowed host", url: "https://hooks.example.com/job", secret: "s", cfg: allowed},
+		{name: "allowed host case", url: "https://HOOKS.example.com./job", secret: "s", cfg: allowed},
+		{name: "allowed subdomain", url: "https://a.b.example.org/job", secret: "s", cfg: allowed},
+		{name: "wildcard excludes apex", url: "https://example.org/job", secret: "s", cfg: allowed, wantErr: true},
+		{name: "host not allowed", url: "https://evil.example.net/job", secret: "s", cfg: allowed, wantErr: true},
+		{name: "suffix is not subdomain", url: "https://evilexample.org/job", secret: "s", cfg: allowed, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Callback{URL: tt.url, Secret: tt.secret}.validate(tt.cfg)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("validate(%q) = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCallback) {
+				t.Fatalf("error %v is not ErrInvalidCallback", err)
+			}
+		})
+	}
+}
+
+func newTestManager(t *testing.T, webhook WebhookConfig) *Manager {
+	t.Helper()
+	calc := service.NewCalculatorService(service.WithLatency(0))
+	cfg := DefaultConfig()
+	webhook.InitialBackoff = time.Millisecond
+	webhook.MaxBackoff = 4 * time.Millisecond
+	webhook.Timeout = time.Second
+	cfg.Webhook = webhook
+	m := NewManager(calc, cfg)
+	t.Cleanup(m.Close)
+	return m
+}
+
+var program = []service.Instruction{
+	{Type: "calc", Op: "+", Var: "x", Left: int64(1), Right: int64(2)},
+	{Type: "print", Var: "x"},
+}
+
+// waitDelivery ждёт окончания доставки уведомления и возвращает
+// состояние задания.
+func waitDelivery(t *testing.T, m *Manager, id string) Info {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		info, err := m.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.State.Finished() && info.Webhook != nil && !info.Webhook.Delivering && len(info.Webhook.Attempts) > 0 {
+			return info
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("job %s: webhook delivery did not finish", id)
+	return Info{}
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got := r.Header.Get(HeaderSignature); got != Sign("secret", body) {
+			t.Errorf("signature = %q, want %q", got, Sign("secret", body))
+		}
+		if r.Header.Get(HeaderJob) == "" || r.Header.Get(HeaderDelivery) != "1" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		// Первые две попытки неудачны.
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+	}))
+	defer srv.Close()
+
+	m := newTestManager(t, WebhookConfig{MaxAttempts: 5, AllowPrivateNetworks: true})
+	info, err := m.Submit(context.Background(), program, nil, &Callback{URL: srv.URL, Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info = waitDelivery(t, m, info.ID)
+	if !info.Webhook.Delivered {
+		t.Fatalf("webhook = %+v, want delivered", info.Webhook)
+	}
+	attempts := info.Webhook.Attempts
+	if len(attempts) != 3 {
+		t.Fatalf("attempts = %+v, want 3", attempts)
+	}
+	for i, a := range attempts[:2] {
+		if a.StatusCode != http.StatusServiceUnavailable || a.Error == "" {
+			t.Fatalf("attempt %d = %+v", i+1, a)
+		}
+	}
+	if last := attempts[2]; last.StatusCode != http.StatusOK || last.Error != "" {
+		t.Fatalf("attempt 3 = %+v", last)
+	}
+}
+
+func TestWebhookGivesUp(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	m := newTestManager(t, WebhookConfig{MaxAttempts: 3, AllowPrivateNetworks: true})
+	info, err := m.Submit(context.Background(), program, nil, &Callback{URL: srv.URL, Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info = waitDelivery(t, m, info.ID)
+	if info.Webhook.Delivered || len(info.Webhook.Attempts) != 3 {
+		t.Fatalf("webhook = %+v, want 3 failed attempts", info.Webhook)
+	}
+}
+
+func TestWebhookDoesNotFollowRedirects(t *testing.T) {
+	var internal atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		internal.Add(1)
+	}))
+	defer target.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer srv.Close()
+
+	m := newTestManager(t, WebhookConfig{MaxAttempts: 2, AllowPrivateNetworks: true})
+	info, err := m.Submit(context.Background(), program, nil, &Callback{URL: srv.URL, Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info = waitDelivery(t, m, info.ID)
+	if info.Webhook.Delivered {
+		t.Fatalf("webhook = %+v, want not delivered", info.Webhook)
+	}
+	if info.Webhook.Attempts[0].StatusCode != http.StatusTemporaryRedirect {
+		t.Fatalf("attempt = %+v, want status 307", info.Webhook.Attempts[0])
+	}
+	if n := internal.Load(); n != 0 {
+		t.Fatalf("redirect target received %d requests", n)
+	}
+}
+
+func TestWebhookRefusesPrivateAddresses(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+	m := newTestManager(t, WebhookConfig{MaxAttempts: 1})
+
+	// Адрес-литерал отклоняется при постановке задания.
+	_, err := m.Submit(context.Background(), program, nil, &Callback{URL: srv.URL, Secret: "secret"})
+	if !errors.Is(err, ErrInvalidCallback) {
+		t.Fatalf("Submit(%s) = %v, want ErrInvalidCallback", srv.URL, err)
+	}
+
+	// Имя узла разрешается во внутренний адрес только при соединении.
+	url := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	info, err := m.Submit(context.Background(), program, nil, &Callback{URL: url, Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info = waitDelivery(t, m, info.ID)
+	if info.Webhook.Delivered || !strings.Contains(info.Webhook.Attempts[0].Error, "not publicly routable") {
+		t.Fatalf("webhook = %+v, want refused connection", info.Webhook)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Fatalf("receiver got %d requests", n)
+	}
+}
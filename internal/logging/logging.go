@@ -0,0 +1,85 @@
+// Package logging связывает журнал slog с запросами: идентификатор
+// запроса хранится в контексте и добавляется ко всем записям, сделанным
+// с этим контекстом.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+
+	"calculator/internal/tracing"
+)
+
+// HeaderRequestID — HTTP заголовок идентификатора запроса. В
+// метаданных gRPC используется тот же ключ в нижнем регистре.
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestID ограничивает длину идентификатора, принятого от клиента.
+const maxRequestID = 128
+
+type requestIDKey struct{}
+
+// WithRequestID возвращает контекст с идентификатором запроса.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID возвращает идентификатор запроса или пустую строку.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID создаёт случайный идентификатор запроса.
+func NewRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// AcceptRequestID возвращает идентификатор, присланный клиентом, если он
+// непустой, не длиннее 128 символов и состоит из печатных ASCII
+// символов, иначе — новый.
+func AcceptRequestID(id string) string {
+	if id == "" || len(id) > maxRequestID {
+		return NewRequestID()
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return NewRequestID()
+		}
+	}
+	return id
+}
+
+// New создаёт журнал в формате JSON с уровнем level, добавляющий к
+// записям request_id и trace_id из контекста.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})})
+}
+
+// contextHandler дополняет записи значениями из контекста.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := tracing.SpanFromContext(ctx).SpanContext(); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID.String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"calculator/internal/tracing"
+)
+
+func TestAcceptRequestID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		keep bool
+	}{
+		{name: "uuid", id: "0b5c7a3e-9f1d-4c2b-8e6a-1d2f3a4b5c6d", keep: true},
+		{name: "printable ascii", id: "req!#$%&'*+.^_`|~42", keep: true},
+		{name: "max length", id: strings.Repeat("a", maxRequestID), keep: true},
+		{name: "empty", id: ""},
+		{name: "too long", id: strings.Repeat("a", maxRequestID+1)},
+		{name: "space", id: "req 1"},
+		{name: "log injection", id: "req\n{\"level\":\"ERROR\"}"},
+		{name: "carriage return", id: "req\r1"},
+		{name: "control character", id: "req\x001"},
+		{name: "delete", id: "req\x7f"},
+		{name: "non-ascii", id: "запрос"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AcceptRequestID(tt.id)
+			if tt.keep {
+				if got != tt.id {
+					t.Fatalf("AcceptRequestID(%q) = %q, want it kept", tt.id, got)
+				}
+				return
+			}
+			if got == tt.id || len(got) != 32 || strings.Trim(got, "0123456789abcdef") != "" {
+				t.Fatalf("AcceptRequestID(%q) = %q, want a new id", tt.id, got)
+			}
+		})
+	}
+}
+
+func decodeLine(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log line %q: %v", buf.String(), err)
+	}
+	buf.Reset()
+	return entry
+}
+
+func TestContextAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+
+	// Без идентификаторов в контексте поля не добавляются.
+	logger.InfoContext(context.Background(), "plain")
+	entry := decodeLine(t, &buf)
+	if _, ok := entry["request_id"]; ok {
+		t.Fatalf("unexpected request_id in %v", entry)
+	}
+	if _, ok := entry["trace_id"]; ok {
+		t.Fatalf("unexpected trace_id in %v", entry)
+	}
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx, span := tracing.NewTracer(nil).Start(ctx, "HTTP POST")
+	traceID := span.SpanContext().TraceID.String()
+
+	logger.InfoContext(ctx, "request completed", "status", 200)
+	entry = decodeLine(t, &buf)
+	if entry["msg"] != "request completed" || entry["request_id"] != "req-1" || entry["trace_id"] != traceID {
+		t.Fatalf("entry = %v, want request_id req-1 and trace_id %s", entry, traceID)
+	}
+
+	// Журнал с дополнительными атрибутами по-прежнему берёт значения
+	// из контекста.
+	logger.With("component", "jobs").InfoContext(ctx, "job finished")
+	entry = decodeLine(t, &buf)
+	if entry["component"] != "jobs" || entry["request_id"] != "req-1" || entry["trace_id"] != traceID {
+		t.Fatalf("entry = %v, want component, request_id and trace_id", entry)
+	}
+
+	// Записи ниже уровня журнала не выводятся.
+	logger.DebugContext(ctx, "hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("debug record written: %q", buf.String())
+	}
+}
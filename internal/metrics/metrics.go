@@ -0,0 +1,296 @@
+// Package metrics — минимальная реализация метрик в текстовом формате
+// Prometheus без внешних зависимостей: счётчики, измерители и
+// гистограммы с метками.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets — границы гистограмм длительности в секундах.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+// Registry хранит метрики и выводит их в текстовом формате.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+	names      map[string]bool
+}
+
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+func (r *Registry) register(name string, c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic("metrics: duplicate metric " + name)
+	}
+	r.names[name] = true
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteText выводит все метрики в текстовом формате Prometheus 0.0.4.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(bw)
+	}
+	return bw.Flush()
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+// desc — имя, описание и имена меток семейства метрик.
+type desc struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (d desc) header(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.name, escapeHelp(d.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.name, d.kind)
+}
+
+func (d desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", d.name, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// series форматирует имя ряда с метками; extra — дополнительная пара
+// метки вида le="0.5".
+func (d desc) series(suffix string, values []string, extra string) string {
+	var b strings.Builder
+	b.WriteString(d.name)
+	b.WriteString(suffix)
+	if len(values) == 0 && extra == "" {
+		return b.String()
+	}
+	b.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(d.labels[i])
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(v))
+		b.WriteByte('"')
+	}
+	if extra != "" {
+		if len(values) > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(extra)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// CounterVec — семейство монотонно растущих счётчиков.
+type CounterVec struct {
+	desc
+	mu     sync.Mutex
+	values map[string]*Counter
+}
+
+type Counter struct {
+	labels []string
+	mu     sync.Mutex
+	v      float64
+}
+
+func (c *Counter) Inc() { c.Add(1) }
+
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		panic("metrics: counter cannot decrease")
+	}
+	c.mu.Lock()
+	c.v += v
+	c.mu.Unlock()
+}
+
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{
+		desc:   desc{name: name, help: help, kind: "counter", labels: labels},
+		values: make(map[string]*Counter),
+	}
+	r.register(name, c)
+	return c
+}
+
+// With возвращает счётчик с заданными значениями меток.
+func (c *CounterVec) With(values ...string) *Counter {
+	key := c.key(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counter, ok := c.values[key]
+	if !ok {
+		counter = &Counter{labels: append([]string(nil), values...)}
+		c.values[key] = counter
+	}
+	return counter
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.header(w)
+	for _, counter := range sortedSeries(&c.mu, c.values) {
+		counter.mu.Lock()
+		v := counter.v
+		counter.mu.Unlock()
+		fmt.Fprintf(w, "%s %s\n", c.series("", counter.labels, ""), formatFloat(v))
+	}
+}
+
+// GaugeFunc — измеритель, значение которого читается при выводе.
+type GaugeFunc struct {
+	desc
+	fn func() float64
+}
+
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	r.register(name, &GaugeFunc{desc: desc{name: name, help: help, kind: "gauge"}, fn: fn})
+}
+
+func (g *GaugeFunc) write(w *bufio.Writer) {
+	g.header(w)
+	fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.fn()))
+}
+
+// HistogramVec — семейство гистограмм с общими границами корзин.
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*Histogram
+}
+
+type Histogram struct {
+	labels  []string
+	buckets []float64
+	mu      sync.Mutex
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, upper := range h.buckets {
+		if v <= upper {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	h := &HistogramVec{
+		desc:    desc{name: name, help: help, kind: "histogram", labels: labels},
+		buckets: buckets,
+		values:  make(map[string]*Histogram),
+	}
+	r.register(name, h)
+	return h
+}
+
+func (h *HistogramVec) With(values ...string) *Histogram {
+	key := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hist, ok := h.values[key]
+	if !ok {
+		hist = &Histogram{
+			labels:  append([]string(nil), values...),
+			buckets: h.buckets,
+			counts:  make([]uint64, len(h.buckets)),
+		}
+		h.values[key] = hist
+	}
+	return hist
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.header(w)
+	for _, hist := range sortedSeries(&h.mu, h.values) {
+		hist.mu.Lock()
+		counts := append([]uint64(nil), hist.counts...)
+		sum, count := hist.sum, hist.count
+		hist.mu.Unlock()
+
+		for i, upper := range h.buckets {
+			le := `le="` + formatFloat(upper) + `"`
+			fmt.Fprintf(w, "%s %d\n", h.series("_bucket", hist.labels, le), counts[i])
+		}
+		fmt.Fprintf(w, "%s %d\n", h.series("_bucket", hist.labels, `le="+Inf"`), count)
+		fmt.Fprintf(w, "%s %s\n", h.series("_sum", hist.labels, ""), formatFloat(sum))
+		fmt.Fprintf(w, "%s %d\n", h.series("_count", hist.labels, ""), count)
+	}
+}
+
+// sortedSeries возвращает ряды семейства в порядке значений меток,
+// чтобы вывод был стабильным.
+func sortedSeries[T any](mu *sync.Mutex, values map[string]T) []T {
+	mu.Lock()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	out := make([]T, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, values[key])
+	}
+	mu.Unlock()
+	return out
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func writeText(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestCounterExposition(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("calc_requests_total", "Requests.\nBy \\ method.", "method", "code")
+	// Ряды добавляются не по порядку, а выводятся отсортированными.
+	c.With("stream", "OK").Add(2)
+	c.With("calculate", "OK").Inc()
+	c.With(`a"b\c`+"\nd", "ERR").Inc()
+
+	want := `# HELP calc_requests_total Requests.\nBy \\ method.
+# TYPE calc_requests_total counter
+calc_requests_total{method="a\"b\\c\nd",code="ERR"} 1
+calc_requests_total{method="calculate",code="OK"} 1
+calc_requests_total{method="stream",code="OK"} 2
+`
+	if got := writeText(t, r); got != want {
+		t.Fatalf("exposition:\n%s\nwant:\n%s", got, want)
+	}
+	// Повторный вывод не меняет порядок рядов.
+	if got := writeText(t, r); got != want {
+		t.Fatalf("second exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHistogramExposition(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogramVec("calc_duration_seconds", "Duration.", []float64{1, 0.1, 0.5}, "method")
+	for _, v := range []float64{0.05, 0.1, 0.3, 0.7, 2} {
+		h.With("calculate").Observe(v)
+	}
+
+	want := `# HELP calc_duration_seconds Duration.
+# TYPE calc_duration_seconds histogram
+calc_duration_seconds_bucket{method="calculate",le="0.1"} 2
+calc_duration_seconds_bucket{method="calculate",le="0.5"} 3
+calc_duration_seconds_bucket{method="calculate",le="1"} 4
+calc_duration_seconds_bucket{method="calculate",le="+Inf"} 5
+calc_duration_seconds_sum{method="calculate"} 3.15
+calc_duration_seconds_count{method="calculate"} 5
+`
+	if got := writeText(t, r); got != want {
+		t.Fatalf("exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGaugeFunc(t *testing.T) {
+	r := NewRegistry()
+	r.NewGaugeFunc("calc_pool_busy", "Busy workers.", func() float64 { return 3 })
+
+	want := "# HELP calc_pool_busy Busy workers.\n# TYPE calc_pool_busy gauge\ncalc_pool_busy 3\n"
+	if got := writeText(t, r); got != want {
+		t.Fatalf("exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(r *Registry)
+	}{
+		{"duplicate name", func(r *Registry) {
+			r.NewCounterVec("calc_total", "A.")
+			r.NewGaugeFunc("calc_total", "B.", func() float64 { return 0 })
+		}},
+		{"too few label values", func(r *Registry) {
+			r.NewCounterVec("calc_total", "A.", "method", "code").With("calculate")
+		}},
+		{"too many label values", func(r *Registry) {
+			r.NewHistogramVec("calc_seconds", "A.", []float64{1}, "method").With("calculate", "OK")
+		}},
+		{"counter decrease", func(r *Registry) {
+			r.NewCounterVec("calc_total", "A.").With().Add(-1)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("no panic")
+				}
+			}()
+			tt.fn(NewRegistry())
+		})
+	}
+}
@@ -9,6 +9,7 @@ package pb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,6 +22,64 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type JobState int32
+
+const (
+	JobState_JOB_STATE_UNSPECIFIED JobState = 0
+	JobState_JOB_STATE_QUEUED      JobState = 1
+	JobState_JOB_STATE_RUNNING     JobState = 2
+	JobState_JOB_STATE_SUCCEEDED   JobState = 3
+	JobState_JOB_STATE_FAILED      JobState = 4
+	JobState_JOB_STATE_CANCELED    JobState = 5
+)
+
+// Enum value maps for JobState.
+var (
+	JobState_name = map[int32]string{
+		0: "JOB_STATE_UNSPECIFIED",
+		1: "JOB_STATE_QUEUED",
+		2: "JOB_STATE_RUNNING",
+		3: "JOB_STATE_SUCCEEDED",
+		4: "JOB_STATE_FAILED",
+		5: "JOB_STATE_CANCELED",
+	}
+	JobState_value = map[string]int32{
+		"JOB_STATE_UNSPECIFIED": 0,
+		"JOB_STATE_QUEUED":      1,
+		"JOB_STATE_RUNNING":     2,
+		"JOB_STATE_SUCCEEDED":   3,
+		"JOB_STATE_FAILED":      4,
+		"JOB_STATE_CANCELED":    5,
+	}
+)
+
+func (x JobState) Enum() *JobState {
+	p := new(JobState)
+	*p = x
+	return p
+}
+
+func (x JobState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_calculator_proto_enumTypes[0].Descriptor()
+}
+
+func (JobState) Type() protoreflect.EnumType {
+	return &file_proto_calculator_proto_enumTypes[0]
+}
+
+func (x JobState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobState.Descriptor instead.
+func (JobState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{0}
+}
+
 type Instruction struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	Type  string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
@@ -35,7 +94,10 @@ type Instruction struct {
 	//
 	//	*Instruction_RightInt
 	//	*Instruction_RightVar
-	RightType     isInstruction_RightType `protobuf_oneof:"right_type"`
+	RightType isInstruction_RightType `protobuf_oneof:"right_type"`
+	// Для инструкций input: значение по умолчанию и тип параметра.
+	Default       *int64 `protobuf:"varint,8,opt,name=default,proto3,oneof" json:"default,omitempty"`
+	ValueType     string `protobuf:"bytes,9,opt,name=value_type,json=valueType,proto3" json:"value_type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -141,6 +203,20 @@ func (x *Instruction) GetRightVar() string {
 	return ""
 }
 
+func (x *Instruction) GetDefault() int64 {
+	if x != nil && x.Default != nil {
+		return *x.Default
+	}
+	return 0
+}
+
+func (x *Instruction) GetValueType() string {
+	if x != nil {
+		return x.ValueType
+	}
+	return ""
+}
+
 type isInstruction_LeftType interface {
 	isInstruction_LeftType()
 }
@@ -176,6 +252,7 @@ func (*Instruction_RightVar) isInstruction_RightType() {}
 type CalculateRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Instructions  []*Instruction         `protobuf:"bytes,1,rep,name=instructions,proto3" json:"instructions,omitempty"`
+	Inputs        map[string]int64       `protobuf:"bytes,2,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -217,6 +294,13 @@ func (x *CalculateRequest) GetInstructions() []*Instruction {
 	return nil
 }
 
+func (x *CalculateRequest) GetInputs() map[string]int64 {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
 type ResultItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Var           string                 `protobuf:"bytes,1,opt,name=var,proto3" json:"var,omitempty"`
@@ -270,8 +354,10 @@ func (x *ResultItem) GetValue() int64 {
 }
 
 type CalculateResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Items         []*ResultItem          `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Items []*ResultItem          `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// Значения входов, с которыми выполнена программа.
+	Inputs        map[string]int64 `protobuf:"bytes,2,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -313,88 +399,3384 @@ func (x *CalculateResponse) GetItems() []*ResultItem {
 	return nil
 }
 
-var File_proto_calculator_proto protoreflect.FileDescriptor
+func (x *CalculateResponse) GetInputs() map[string]int64 {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
 
-const file_proto_calculator_proto_rawDesc = "" +
-	"\n" +
-	"\x16proto/calculator.proto\x12\n" +
-	"calculator\"\xd6\x01\n" +
-	"\vInstruction\x12\x12\n" +
-	"\x04type\x18\x01 \x01(\tR\x04type\x12\x0e\n" +
-	"\x02op\x18\x02 \x01(\tR\x02op\x12\x10\n" +
-	"\x03var\x18\x03 \x01(\tR\x03var\x12\x1b\n" +
-	"\bleft_int\x18\x04 \x01(\x03H\x00R\aleftInt\x12\x1b\n" +
-	"\bleft_var\x18\x05 \x01(\tH\x00R\aleftVar\x12\x1d\n" +
-	"\tright_int\x18\x06 \x01(\x03H\x01R\brightInt\x12\x1d\n" +
-	"\tright_var\x18\a \x01(\tH\x01R\brightVarB\v\n" +
-	"\tleft_typeB\f\n" +
-	"\n" +
-	"right_type\"O\n" +
-	"\x10CalculateRequest\x12;\n" +
-	"\finstructions\x18\x01 \x03(\v2\x17.calculator.InstructionR\finstructions\"4\n" +
-	"\n" +
-	"ResultItem\x12\x10\n" +
-	"\x03var\x18\x01 \x01(\tR\x03var\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x03R\x05value\"A\n" +
-	"\x11CalculateResponse\x12,\n" +
-	"\x05items\x18\x01 \x03(\v2\x16.calculator.ResultItemR\x05items2]\n" +
-	"\x11CalculatorService\x12H\n" +
-	"\tCalculate\x12\x1c.calculator.CalculateRequest\x1a\x1d.calculator.CalculateResponseB\x0fZ\rcalculator/pbb\x06proto3"
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	VariableCount int32                  `protobuf:"varint,4,opt,name=variable_count,json=variableCount,proto3" json:"variable_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-var (
-	file_proto_calculator_proto_rawDescOnce sync.Once
-	file_proto_calculator_proto_rawDescData []byte
-)
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_proto_calculator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-func file_proto_calculator_proto_rawDescGZIP() []byte {
-	file_proto_calculator_proto_rawDescOnce.Do(func() {
-		file_proto_calculator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_calculator_proto_rawDesc), len(file_proto_calculator_proto_rawDesc)))
-	})
-	return file_proto_calculator_proto_rawDescData
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_proto_calculator_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
-var file_proto_calculator_proto_goTypes = []any{
-	(*Instruction)(nil),       // 0: calculator.Instruction
-	(*CalculateRequest)(nil),  // 1: calculator.CalculateRequest
-	(*ResultItem)(nil),        // 2: calculator.ResultItem
-	(*CalculateResponse)(nil), // 3: calculator.CalculateResponse
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_proto_calculator_proto_depIdxs = []int32{
-	0, // 0: calculator.CalculateRequest.instructions:type_name -> calculator.Instruction
-	2, // 1: calculator.CalculateResponse.items:type_name -> calculator.ResultItem
-	1, // 2: calculator.CalculatorService.Calculate:input_type -> calculator.CalculateRequest
-	3, // 3: calculator.CalculatorService.Calculate:output_type -> calculator.CalculateResponse
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{4}
 }
 
-func init() { file_proto_calculator_proto_init() }
-func file_proto_calculator_proto_init() {
-	if File_proto_calculator_proto != nil {
-		return
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
 	}
-	file_proto_calculator_proto_msgTypes[0].OneofWrappers = []any{
-		(*Instruction_LeftInt)(nil),
-		(*Instruction_LeftVar)(nil),
-		(*Instruction_RightInt)(nil),
-		(*Instruction_RightVar)(nil),
+	return ""
+}
+
+func (x *Session) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Session) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Session) GetVariableCount() int32 {
+	if x != nil {
+		return x.VariableCount
+	}
+	return 0
+}
+
+type CreateSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSessionRequest) Reset() {
+	*x = CreateSessionRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionRequest) ProtoMessage() {}
+
+func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{5}
+}
+
+type GetSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSessionRequest) Reset() {
+	*x = GetSessionRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSessionRequest) ProtoMessage() {}
+
+func (x *GetSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type DeleteSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSessionRequest) Reset() {
+	*x = DeleteSessionRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSessionRequest) ProtoMessage() {}
+
+func (x *DeleteSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSessionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type DeleteSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSessionResponse) Reset() {
+	*x = DeleteSessionResponse{}
+	mi := &file_proto_calculator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSessionResponse) ProtoMessage() {}
+
+func (x *DeleteSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSessionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSessionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{8}
+}
+
+type ListVariablesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVariablesRequest) Reset() {
+	*x = ListVariablesRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVariablesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVariablesRequest) ProtoMessage() {}
+
+func (x *ListVariablesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVariablesRequest.ProtoReflect.Descriptor instead.
+func (*ListVariablesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListVariablesRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type ListVariablesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*ResultItem          `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVariablesResponse) Reset() {
+	*x = ListVariablesResponse{}
+	mi := &file_proto_calculator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVariablesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVariablesResponse) ProtoMessage() {}
+
+func (x *ListVariablesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVariablesResponse.ProtoReflect.Descriptor instead.
+func (*ListVariablesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListVariablesResponse) GetItems() []*ResultItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type SessionCalculateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Instructions  []*Instruction         `protobuf:"bytes,2,rep,name=instructions,proto3" json:"instructions,omitempty"`
+	Inputs        map[string]int64       `protobuf:"bytes,3,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionCalculateRequest) Reset() {
+	*x = SessionCalculateRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionCalculateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionCalculateRequest) ProtoMessage() {}
+
+func (x *SessionCalculateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionCalculateRequest.ProtoReflect.Descriptor instead.
+func (*SessionCalculateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SessionCalculateRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionCalculateRequest) GetInstructions() []*Instruction {
+	if x != nil {
+		return x.Instructions
+	}
+	return nil
+}
+
+func (x *SessionCalculateRequest) GetInputs() map[string]int64 {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+type UpdateInputsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Values        map[string]int64       `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateInputsRequest) Reset() {
+	*x = UpdateInputsRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateInputsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateInputsRequest) ProtoMessage() {}
+
+func (x *UpdateInputsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateInputsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateInputsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateInputsRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *UpdateInputsRequest) GetValues() map[string]int64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type VariableChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Var           string                 `protobuf:"bytes,1,opt,name=var,proto3" json:"var,omitempty"`
+	OldValue      int64                  `protobuf:"varint,2,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	NewValue      int64                  `protobuf:"varint,3,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VariableChange) Reset() {
+	*x = VariableChange{}
+	mi := &file_proto_calculator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VariableChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariableChange) ProtoMessage() {}
+
+func (x *VariableChange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariableChange.ProtoReflect.Descriptor instead.
+func (*VariableChange) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *VariableChange) GetVar() string {
+	if x != nil {
+		return x.Var
+	}
+	return ""
+}
+
+func (x *VariableChange) GetOldValue() int64 {
+	if x != nil {
+		return x.OldValue
+	}
+	return 0
+}
+
+func (x *VariableChange) GetNewValue() int64 {
+	if x != nil {
+		return x.NewValue
+	}
+	return 0
+}
+
+type UpdateInputsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*VariableChange      `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateInputsResponse) Reset() {
+	*x = UpdateInputsResponse{}
+	mi := &file_proto_calculator_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateInputsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateInputsResponse) ProtoMessage() {}
+
+func (x *UpdateInputsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateInputsResponse.ProtoReflect.Descriptor instead.
+func (*UpdateInputsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UpdateInputsResponse) GetChanges() []*VariableChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type ProgramVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Instructions  []*Instruction         `protobuf:"bytes,3,rep,name=instructions,proto3" json:"instructions,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProgramVersion) Reset() {
+	*x = ProgramVersion{}
+	mi := &file_proto_calculator_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProgramVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgramVersion) ProtoMessage() {}
+
+func (x *ProgramVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgramVersion.ProtoReflect.Descriptor instead.
+func (*ProgramVersion) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ProgramVersion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProgramVersion) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ProgramVersion) GetInstructions() []*Instruction {
+	if x != nil {
+		return x.Instructions
+	}
+	return nil
+}
+
+func (x *ProgramVersion) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ProgramSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	LatestVersion int32                  `protobuf:"varint,2,opt,name=latest_version,json=latestVersion,proto3" json:"latest_version,omitempty"`
+	Versions      []int32                `protobuf:"varint,3,rep,packed,name=versions,proto3" json:"versions,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProgramSummary) Reset() {
+	*x = ProgramSummary{}
+	mi := &file_proto_calculator_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProgramSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgramSummary) ProtoMessage() {}
+
+func (x *ProgramSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgramSummary.ProtoReflect.Descriptor instead.
+func (*ProgramSummary) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ProgramSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProgramSummary) GetLatestVersion() int32 {
+	if x != nil {
+		return x.LatestVersion
+	}
+	return 0
+}
+
+func (x *ProgramSummary) GetVersions() []int32 {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *ProgramSummary) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CreateProgramRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Instructions  []*Instruction         `protobuf:"bytes,2,rep,name=instructions,proto3" json:"instructions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateProgramRequest) Reset() {
+	*x = CreateProgramRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateProgramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProgramRequest) ProtoMessage() {}
+
+func (x *CreateProgramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProgramRequest.ProtoReflect.Descriptor instead.
+func (*CreateProgramRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CreateProgramRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateProgramRequest) GetInstructions() []*Instruction {
+	if x != nil {
+		return x.Instructions
+	}
+	return nil
+}
+
+type ListProgramsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProgramsRequest) Reset() {
+	*x = ListProgramsRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProgramsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProgramsRequest) ProtoMessage() {}
+
+func (x *ListProgramsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProgramsRequest.ProtoReflect.Descriptor instead.
+func (*ListProgramsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{18}
+}
+
+type ListProgramsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Programs      []*ProgramSummary      `protobuf:"bytes,1,rep,name=programs,proto3" json:"programs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProgramsResponse) Reset() {
+	*x = ListProgramsResponse{}
+	mi := &file_proto_calculator_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProgramsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProgramsResponse) ProtoMessage() {}
+
+func (x *ListProgramsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProgramsResponse.ProtoReflect.Descriptor instead.
+func (*ListProgramsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListProgramsResponse) GetPrograms() []*ProgramSummary {
+	if x != nil {
+		return x.Programs
+	}
+	return nil
+}
+
+// version = 0 означает последнюю версию.
+type GetProgramRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProgramRequest) Reset() {
+	*x = GetProgramRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProgramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProgramRequest) ProtoMessage() {}
+
+func (x *GetProgramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProgramRequest.ProtoReflect.Descriptor instead.
+func (*GetProgramRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetProgramRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetProgramRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type ExecuteProgramRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Inputs        map[string]int64       `protobuf:"bytes,3,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteProgramRequest) Reset() {
+	*x = ExecuteProgramRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteProgramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteProgramRequest) ProtoMessage() {}
+
+func (x *ExecuteProgramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteProgramRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteProgramRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ExecuteProgramRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExecuteProgramRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ExecuteProgramRequest) GetInputs() map[string]int64 {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+type ExecuteProgramResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Items         []*ResultItem          `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Inputs        map[string]int64       `protobuf:"bytes,4,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteProgramResponse) Reset() {
+	*x = ExecuteProgramResponse{}
+	mi := &file_proto_calculator_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteProgramResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteProgramResponse) ProtoMessage() {}
+
+func (x *ExecuteProgramResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteProgramResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteProgramResponse) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ExecuteProgramResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExecuteProgramResponse) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ExecuteProgramResponse) GetItems() []*ResultItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ExecuteProgramResponse) GetInputs() map[string]int64 {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+type DeleteProgramRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProgramRequest) Reset() {
+	*x = DeleteProgramRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProgramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProgramRequest) ProtoMessage() {}
+
+func (x *DeleteProgramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProgramRequest.ProtoReflect.Descriptor instead.
+func (*DeleteProgramRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeleteProgramRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteProgramResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProgramResponse) Reset() {
+	*x = DeleteProgramResponse{}
+	mi := &file_proto_calculator_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProgramResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProgramResponse) ProtoMessage() {}
+
+func (x *DeleteProgramResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProgramResponse.ProtoReflect.Descriptor instead.
+func (*DeleteProgramResponse) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{24}
+}
+
+type DiffProgramRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	FromVersion   int32                  `protobuf:"varint,2,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
+	ToVersion     int32                  `protobuf:"varint,3,opt,name=to_version,json=toVersion,proto3" json:"to_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffProgramRequest) Reset() {
+	*x = DiffProgramRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffProgramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffProgramRequest) ProtoMessage() {}
+
+func (x *DiffProgramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffProgramRequest.ProtoReflect.Descriptor instead.
+func (*DiffProgramRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DiffProgramRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DiffProgramRequest) GetFromVersion() int32 {
+	if x != nil {
+		return x.FromVersion
+	}
+	return 0
+}
+
+func (x *DiffProgramRequest) GetToVersion() int32 {
+	if x != nil {
+		return x.ToVersion
+	}
+	return 0
+}
+
+type InstructionChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Var           string                 `protobuf:"bytes,1,opt,name=var,proto3" json:"var,omitempty"`
+	From          *Instruction           `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *Instruction           `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstructionChange) Reset() {
+	*x = InstructionChange{}
+	mi := &file_proto_calculator_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstructionChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstructionChange) ProtoMessage() {}
+
+func (x *InstructionChange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstructionChange.ProtoReflect.Descriptor instead.
+func (*InstructionChange) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *InstructionChange) GetVar() string {
+	if x != nil {
+		return x.Var
+	}
+	return ""
+}
+
+func (x *InstructionChange) GetFrom() *Instruction {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *InstructionChange) GetTo() *Instruction {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type DiffProgramResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	FromVersion   int32                  `protobuf:"varint,2,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
+	ToVersion     int32                  `protobuf:"varint,3,opt,name=to_version,json=toVersion,proto3" json:"to_version,omitempty"`
+	Added         []*Instruction         `protobuf:"bytes,4,rep,name=added,proto3" json:"added,omitempty"`
+	Removed       []*Instruction         `protobuf:"bytes,5,rep,name=removed,proto3" json:"removed,omitempty"`
+	Changed       []*InstructionChange   `protobuf:"bytes,6,rep,name=changed,proto3" json:"changed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffProgramResponse) Reset() {
+	*x = DiffProgramResponse{}
+	mi := &file_proto_calculator_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffProgramResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffProgramResponse) ProtoMessage() {}
+
+func (x *DiffProgramResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffProgramResponse.ProtoReflect.Descriptor instead.
+func (*DiffProgramResponse) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DiffProgramResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DiffProgramResponse) GetFromVersion() int32 {
+	if x != nil {
+		return x.FromVersion
+	}
+	return 0
+}
+
+func (x *DiffProgramResponse) GetToVersion() int32 {
+	if x != nil {
+		return x.ToVersion
+	}
+	return 0
+}
+
+func (x *DiffProgramResponse) GetAdded() []*Instruction {
+	if x != nil {
+		return x.Added
+	}
+	return nil
+}
+
+func (x *DiffProgramResponse) GetRemoved() []*Instruction {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+func (x *DiffProgramResponse) GetChanged() []*InstructionChange {
+	if x != nil {
+		return x.Changed
+	}
+	return nil
+}
+
+// Программа пакетного вычисления: либо инструкции, либо имя и версия
+// сохранённой программы.
+type BatchProgram struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instructions  []*Instruction         `protobuf:"bytes,1,rep,name=instructions,proto3" json:"instructions,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version       int32                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchProgram) Reset() {
+	*x = BatchProgram{}
+	mi := &file_proto_calculator_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchProgram) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchProgram) ProtoMessage() {}
+
+func (x *BatchProgram) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchProgram.ProtoReflect.Descriptor instead.
+func (*BatchProgram) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *BatchProgram) GetInstructions() []*Instruction {
+	if x != nil {
+		return x.Instructions
+	}
+	return nil
+}
+
+func (x *BatchProgram) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BatchProgram) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type BatchRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Inputs        map[string]int64       `protobuf:"bytes,1,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchRow) Reset() {
+	*x = BatchRow{}
+	mi := &file_proto_calculator_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRow) ProtoMessage() {}
+
+func (x *BatchRow) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRow.ProtoReflect.Descriptor instead.
+func (*BatchRow) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *BatchRow) GetInputs() map[string]int64 {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+// Первое сообщение потока — program, остальные — row.
+type BatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Frame:
+	//
+	//	*BatchRequest_Program
+	//	*BatchRequest_Row
+	Frame         isBatchRequest_Frame `protobuf_oneof:"frame"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchRequest) Reset() {
+	*x = BatchRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRequest) ProtoMessage() {}
+
+func (x *BatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRequest.ProtoReflect.Descriptor instead.
+func (*BatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *BatchRequest) GetFrame() isBatchRequest_Frame {
+	if x != nil {
+		return x.Frame
+	}
+	return nil
+}
+
+func (x *BatchRequest) GetProgram() *BatchProgram {
+	if x != nil {
+		if x, ok := x.Frame.(*BatchRequest_Program); ok {
+			return x.Program
+		}
+	}
+	return nil
+}
+
+func (x *BatchRequest) GetRow() *BatchRow {
+	if x != nil {
+		if x, ok := x.Frame.(*BatchRequest_Row); ok {
+			return x.Row
+		}
+	}
+	return nil
+}
+
+type isBatchRequest_Frame interface {
+	isBatchRequest_Frame()
+}
+
+type BatchRequest_Program struct {
+	Program *BatchProgram `protobuf:"bytes,1,opt,name=program,proto3,oneof"`
+}
+
+type BatchRequest_Row struct {
+	Row *BatchRow `protobuf:"bytes,2,opt,name=row,proto3,oneof"`
+}
+
+func (*BatchRequest_Program) isBatchRequest_Frame() {}
+
+func (*BatchRequest_Row) isBatchRequest_Frame() {}
+
+type BatchRowResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Inputs        map[string]int64       `protobuf:"bytes,2,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Items         []*ResultItem          `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchRowResult) Reset() {
+	*x = BatchRowResult{}
+	mi := &file_proto_calculator_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRowResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRowResult) ProtoMessage() {}
+
+func (x *BatchRowResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRowResult.ProtoReflect.Descriptor instead.
+func (*BatchRowResult) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *BatchRowResult) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *BatchRowResult) GetInputs() map[string]int64 {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *BatchRowResult) GetItems() []*ResultItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BatchRowResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          int32                  `protobuf:"varint,1,opt,name=rows,proto3" json:"rows,omitempty"`
+	Succeeded     int32                  `protobuf:"varint,2,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	Failed        int32                  `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchSummary) Reset() {
+	*x = BatchSummary{}
+	mi := &file_proto_calculator_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSummary) ProtoMessage() {}
+
+func (x *BatchSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSummary.ProtoReflect.Descriptor instead.
+func (*BatchSummary) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *BatchSummary) GetRows() int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *BatchSummary) GetSucceeded() int32 {
+	if x != nil {
+		return x.Succeeded
+	}
+	return 0
+}
+
+func (x *BatchSummary) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+// Сервер отправляет result каждой строки, как только она вычислена, в
+// порядке строк и завершает поток кадром summary.
+type BatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Frame:
+	//
+	//	*BatchResponse_Result
+	//	*BatchResponse_Summary
+	Frame         isBatchResponse_Frame `protobuf_oneof:"frame"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResponse) Reset() {
+	*x = BatchResponse{}
+	mi := &file_proto_calculator_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResponse) ProtoMessage() {}
+
+func (x *BatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResponse.ProtoReflect.Descriptor instead.
+func (*BatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *BatchResponse) GetFrame() isBatchResponse_Frame {
+	if x != nil {
+		return x.Frame
+	}
+	return nil
+}
+
+func (x *BatchResponse) GetResult() *BatchRowResult {
+	if x != nil {
+		if x, ok := x.Frame.(*BatchResponse_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+func (x *BatchResponse) GetSummary() *BatchSummary {
+	if x != nil {
+		if x, ok := x.Frame.(*BatchResponse_Summary); ok {
+			return x.Summary
+		}
+	}
+	return nil
+}
+
+type isBatchResponse_Frame interface {
+	isBatchResponse_Frame()
+}
+
+type BatchResponse_Result struct {
+	Result *BatchRowResult `protobuf:"bytes,1,opt,name=result,proto3,oneof"`
+}
+
+type BatchResponse_Summary struct {
+	Summary *BatchSummary `protobuf:"bytes,2,opt,name=summary,proto3,oneof"`
+}
+
+func (*BatchResponse_Result) isBatchResponse_Frame() {}
+
+func (*BatchResponse_Summary) isBatchResponse_Frame() {}
+
+// Первым сообщением CalculateStream может быть start со значениями
+// входов. Затем клиент передаёт инструкции по одной. Конец ввода — кадр
+// end или закрытие потока клиентом; сообщения после end не читаются.
+type CalculateStreamRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Frame:
+	//
+	//	*CalculateStreamRequest_Start
+	//	*CalculateStreamRequest_Instruction
+	//	*CalculateStreamRequest_End
+	Frame         isCalculateStreamRequest_Frame `protobuf_oneof:"frame"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateStreamRequest) Reset() {
+	*x = CalculateStreamRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateStreamRequest) ProtoMessage() {}
+
+func (x *CalculateStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateStreamRequest.ProtoReflect.Descriptor instead.
+func (*CalculateStreamRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CalculateStreamRequest) GetFrame() isCalculateStreamRequest_Frame {
+	if x != nil {
+		return x.Frame
+	}
+	return nil
+}
+
+func (x *CalculateStreamRequest) GetStart() *StreamStart {
+	if x != nil {
+		if x, ok := x.Frame.(*CalculateStreamRequest_Start); ok {
+			return x.Start
+		}
+	}
+	return nil
+}
+
+func (x *CalculateStreamRequest) GetInstruction() *Instruction {
+	if x != nil {
+		if x, ok := x.Frame.(*CalculateStreamRequest_Instruction); ok {
+			return x.Instruction
+		}
+	}
+	return nil
+}
+
+func (x *CalculateStreamRequest) GetEnd() *EndOfInput {
+	if x != nil {
+		if x, ok := x.Frame.(*CalculateStreamRequest_End); ok {
+			return x.End
+		}
+	}
+	return nil
+}
+
+type isCalculateStreamRequest_Frame interface {
+	isCalculateStreamRequest_Frame()
+}
+
+type CalculateStreamRequest_Start struct {
+	Start *StreamStart `protobuf:"bytes,1,opt,name=start,proto3,oneof"`
+}
+
+type CalculateStreamRequest_Instruction struct {
+	Instruction *Instruction `protobuf:"bytes,2,opt,name=instruction,proto3,oneof"`
+}
+
+type CalculateStreamRequest_End struct {
+	End *EndOfInput `protobuf:"bytes,3,opt,name=end,proto3,oneof"`
+}
+
+func (*CalculateStreamRequest_Start) isCalculateStreamRequest_Frame() {}
+
+func (*CalculateStreamRequest_Instruction) isCalculateStreamRequest_Frame() {}
+
+func (*CalculateStreamRequest_End) isCalculateStreamRequest_Frame() {}
+
+type StreamStart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Inputs        map[string]int64       `protobuf:"bytes,1,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamStart) Reset() {
+	*x = StreamStart{}
+	mi := &file_proto_calculator_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamStart) ProtoMessage() {}
+
+func (x *StreamStart) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamStart.ProtoReflect.Descriptor instead.
+func (*StreamStart) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *StreamStart) GetInputs() map[string]int64 {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+type EndOfInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EndOfInput) Reset() {
+	*x = EndOfInput{}
+	mi := &file_proto_calculator_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndOfInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndOfInput) ProtoMessage() {}
+
+func (x *EndOfInput) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndOfInput.ProtoReflect.Descriptor instead.
+func (*EndOfInput) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{36}
+}
+
+// Ошибка выполнения. var — переменная, при вычислении которой она
+// возникла (пусто, если ошибка не относится к переменной), code —
+// код google.rpc.Code.
+type StreamError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Var           string                 `protobuf:"bytes,1,opt,name=var,proto3" json:"var,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Code          int32                  `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamError) Reset() {
+	*x = StreamError{}
+	mi := &file_proto_calculator_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamError) ProtoMessage() {}
+
+func (x *StreamError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamError.ProtoReflect.Descriptor instead.
+func (*StreamError) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *StreamError) GetVar() string {
+	if x != nil {
+		return x.Var
+	}
+	return ""
+}
+
+func (x *StreamError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *StreamError) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+// Итог успешного выполнения. unresolved — переменные из print, которые
+// так и не были объявлены.
+type StreamDone struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instructions  int32                  `protobuf:"varint,1,opt,name=instructions,proto3" json:"instructions,omitempty"`
+	Printed       int32                  `protobuf:"varint,2,opt,name=printed,proto3" json:"printed,omitempty"`
+	Unresolved    []string               `protobuf:"bytes,3,rep,name=unresolved,proto3" json:"unresolved,omitempty"`
+	Inputs        map[string]int64       `protobuf:"bytes,4,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamDone) Reset() {
+	*x = StreamDone{}
+	mi := &file_proto_calculator_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamDone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamDone) ProtoMessage() {}
+
+func (x *StreamDone) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamDone.ProtoReflect.Descriptor instead.
+func (*StreamDone) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *StreamDone) GetInstructions() int32 {
+	if x != nil {
+		return x.Instructions
+	}
+	return 0
+}
+
+func (x *StreamDone) GetPrinted() int32 {
+	if x != nil {
+		return x.Printed
+	}
+	return 0
+}
+
+func (x *StreamDone) GetUnresolved() []string {
+	if x != nil {
+		return x.Unresolved
+	}
+	return nil
+}
+
+func (x *StreamDone) GetInputs() map[string]int64 {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+// Сервер отправляет item по мере готовности каждой переменной из print.
+// Поток завершается ровно одним из кадров done или error, после чего
+// сервер закрывает его со статусом OK. Статус, отличный от OK, означает
+// нарушение протокола или отмену вызова.
+type CalculateStreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Frame:
+	//
+	//	*CalculateStreamResponse_Item
+	//	*CalculateStreamResponse_Error
+	//	*CalculateStreamResponse_Done
+	Frame         isCalculateStreamResponse_Frame `protobuf_oneof:"frame"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateStreamResponse) Reset() {
+	*x = CalculateStreamResponse{}
+	mi := &file_proto_calculator_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateStreamResponse) ProtoMessage() {}
+
+func (x *CalculateStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateStreamResponse.ProtoReflect.Descriptor instead.
+func (*CalculateStreamResponse) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *CalculateStreamResponse) GetFrame() isCalculateStreamResponse_Frame {
+	if x != nil {
+		return x.Frame
+	}
+	return nil
+}
+
+func (x *CalculateStreamResponse) GetItem() *ResultItem {
+	if x != nil {
+		if x, ok := x.Frame.(*CalculateStreamResponse_Item); ok {
+			return x.Item
+		}
+	}
+	return nil
+}
+
+func (x *CalculateStreamResponse) GetError() *StreamError {
+	if x != nil {
+		if x, ok := x.Frame.(*CalculateStreamResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *CalculateStreamResponse) GetDone() *StreamDone {
+	if x != nil {
+		if x, ok := x.Frame.(*CalculateStreamResponse_Done); ok {
+			return x.Done
+		}
+	}
+	return nil
+}
+
+type isCalculateStreamResponse_Frame interface {
+	isCalculateStreamResponse_Frame()
+}
+
+type CalculateStreamResponse_Item struct {
+	Item *ResultItem `protobuf:"bytes,1,opt,name=item,proto3,oneof"`
+}
+
+type CalculateStreamResponse_Error struct {
+	Error *StreamError `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+type CalculateStreamResponse_Done struct {
+	Done *StreamDone `protobuf:"bytes,3,opt,name=done,proto3,oneof"`
+}
+
+func (*CalculateStreamResponse_Item) isCalculateStreamResponse_Frame() {}
+
+func (*CalculateStreamResponse_Error) isCalculateStreamResponse_Frame() {}
+
+func (*CalculateStreamResponse_Done) isCalculateStreamResponse_Frame() {}
+
+// Независимая программа пакета BatchCalculate; id задаёт клиент.
+type BatchCalculateProgram struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Instructions  []*Instruction         `protobuf:"bytes,2,rep,name=instructions,proto3" json:"instructions,omitempty"`
+	Inputs        map[string]int64       `protobuf:"bytes,3,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCalculateProgram) Reset() {
+	*x = BatchCalculateProgram{}
+	mi := &file_proto_calculator_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCalculateProgram) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCalculateProgram) ProtoMessage() {}
+
+func (x *BatchCalculateProgram) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCalculateProgram.ProtoReflect.Descriptor instead.
+func (*BatchCalculateProgram) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *BatchCalculateProgram) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BatchCalculateProgram) GetInstructions() []*Instruction {
+	if x != nil {
+		return x.Instructions
+	}
+	return nil
+}
+
+func (x *BatchCalculateProgram) GetInputs() map[string]int64 {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+// Срок выполнения пакета — срок вызова, а если задан timeout_ms, то
+// меньший из них.
+type BatchCalculateRequest struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Programs      []*BatchCalculateProgram `protobuf:"bytes,1,rep,name=programs,proto3" json:"programs,omitempty"`
+	TimeoutMs     int64                    `protobuf:"varint,2,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCalculateRequest) Reset() {
+	*x = BatchCalculateRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCalculateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCalculateRequest) ProtoMessage() {}
+
+func (x *BatchCalculateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCalculateRequest.ProtoReflect.Descriptor instead.
+func (*BatchCalculateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *BatchCalculateRequest) GetPrograms() []*BatchCalculateProgram {
+	if x != nil {
+		return x.Programs
+	}
+	return nil
+}
+
+func (x *BatchCalculateRequest) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+type BatchCalculateResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*ResultItem          `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Inputs        map[string]int64       `protobuf:"bytes,2,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCalculateResult) Reset() {
+	*x = BatchCalculateResult{}
+	mi := &file_proto_calculator_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCalculateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCalculateResult) ProtoMessage() {}
+
+func (x *BatchCalculateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCalculateResult.ProtoReflect.Descriptor instead.
+func (*BatchCalculateResult) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *BatchCalculateResult) GetItems() []*ResultItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BatchCalculateResult) GetInputs() map[string]int64 {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *BatchCalculateResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// results — результаты программ по их id. Ошибка одной программы не
+// влияет на остальные.
+type BatchCalculateResponse struct {
+	state         protoimpl.MessageState           `protogen:"open.v1"`
+	Results       map[string]*BatchCalculateResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Succeeded     int32                            `protobuf:"varint,2,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	Failed        int32                            `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCalculateResponse) Reset() {
+	*x = BatchCalculateResponse{}
+	mi := &file_proto_calculator_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCalculateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCalculateResponse) ProtoMessage() {}
+
+func (x *BatchCalculateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCalculateResponse.ProtoReflect.Descriptor instead.
+func (*BatchCalculateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *BatchCalculateResponse) GetResults() map[string]*BatchCalculateResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BatchCalculateResponse) GetSucceeded() int32 {
+	if x != nil {
+		return x.Succeeded
+	}
+	return 0
+}
+
+func (x *BatchCalculateResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+// Адрес уведомления о завершении задания. Тело уведомления подписывается
+// HMAC-SHA256 с секретом secret.
+type JobCallback struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobCallback) Reset() {
+	*x = JobCallback{}
+	mi := &file_proto_calculator_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobCallback) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobCallback) ProtoMessage() {}
+
+func (x *JobCallback) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobCallback.ProtoReflect.Descriptor instead.
+func (*JobCallback) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *JobCallback) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *JobCallback) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type SubmitJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instructions  []*Instruction         `protobuf:"bytes,1,rep,name=instructions,proto3" json:"instructions,omitempty"`
+	Inputs        map[string]int64       `protobuf:"bytes,2,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Callback      *JobCallback           `protobuf:"bytes,3,opt,name=callback,proto3" json:"callback,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitJobRequest) Reset() {
+	*x = SubmitJobRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitJobRequest) ProtoMessage() {}
+
+func (x *SubmitJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitJobRequest.ProtoReflect.Descriptor instead.
+func (*SubmitJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *SubmitJobRequest) GetInstructions() []*Instruction {
+	if x != nil {
+		return x.Instructions
+	}
+	return nil
+}
+
+func (x *SubmitJobRequest) GetInputs() map[string]int64 {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *SubmitJobRequest) GetCallback() *JobCallback {
+	if x != nil {
+		return x.Callback
+	}
+	return nil
+}
+
+type GetJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobRequest) Reset() {
+	*x = GetJobRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobRequest) ProtoMessage() {}
+
+func (x *GetJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobRequest.ProtoReflect.Descriptor instead.
+func (*GetJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobRequest) Reset() {
+	*x = CancelJobRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobRequest) ProtoMessage() {}
+
+func (x *CancelJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *CancelJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RedeliverJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedeliverJobRequest) Reset() {
+	*x = RedeliverJobRequest{}
+	mi := &file_proto_calculator_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedeliverJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeliverJobRequest) ProtoMessage() {}
+
+func (x *RedeliverJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeliverJobRequest.ProtoReflect.Descriptor instead.
+func (*RedeliverJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *RedeliverJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeliveryAttempt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Delivery      int32                  `protobuf:"varint,1,opt,name=delivery,proto3" json:"delivery,omitempty"`
+	Attempt       int32                  `protobuf:"varint,2,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	StatusCode    int32                  `protobuf:"varint,4,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryAttempt) Reset() {
+	*x = DeliveryAttempt{}
+	mi := &file_proto_calculator_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryAttempt) ProtoMessage() {}
+
+func (x *DeliveryAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryAttempt.ProtoReflect.Descriptor instead.
+func (*DeliveryAttempt) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *DeliveryAttempt) GetDelivery() int32 {
+	if x != nil {
+		return x.Delivery
+	}
+	return 0
+}
+
+func (x *DeliveryAttempt) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *DeliveryAttempt) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *DeliveryAttempt) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *DeliveryAttempt) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type JobWebhook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Delivered     bool                   `protobuf:"varint,2,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	Delivering    bool                   `protobuf:"varint,3,opt,name=delivering,proto3" json:"delivering,omitempty"`
+	Attempts      []*DeliveryAttempt     `protobuf:"bytes,4,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobWebhook) Reset() {
+	*x = JobWebhook{}
+	mi := &file_proto_calculator_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobWebhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobWebhook) ProtoMessage() {}
+
+func (x *JobWebhook) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobWebhook.ProtoReflect.Descriptor instead.
+func (*JobWebhook) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *JobWebhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *JobWebhook) GetDelivered() bool {
+	if x != nil {
+		return x.Delivered
+	}
+	return false
+}
+
+func (x *JobWebhook) GetDelivering() bool {
+	if x != nil {
+		return x.Delivering
+	}
+	return false
+}
+
+func (x *JobWebhook) GetAttempts() []*DeliveryAttempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+// Задание асинхронного выполнения. items заполнено только после
+// успешного завершения, error — после ошибки или отмены.
+type Job struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State         JobState               `protobuf:"varint,2,opt,name=state,proto3,enum=calculator.JobState" json:"state,omitempty"`
+	Total         int32                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Completed     int32                  `protobuf:"varint,4,opt,name=completed,proto3" json:"completed,omitempty"`
+	Items         []*ResultItem          `protobuf:"bytes,5,rep,name=items,proto3" json:"items,omitempty"`
+	Inputs        map[string]int64       `protobuf:"bytes,6,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Webhook       *JobWebhook            `protobuf:"bytes,11,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	mi := &file_proto_calculator_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calculator_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_proto_calculator_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *Job) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Job) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *Job) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Job) GetCompleted() int32 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *Job) GetItems() []*ResultItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Job) GetInputs() map[string]int64 {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *Job) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Job) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Job) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Job) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *Job) GetWebhook() *JobWebhook {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
+var File_proto_calculator_proto protoreflect.FileDescriptor
+
+const file_proto_calculator_proto_rawDesc = "" +
+	"\n" +
+	"\x16proto/calculator.proto\x12\n" +
+	"calculator\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa0\x02\n" +
+	"\vInstruction\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02op\x18\x02 \x01(\tR\x02op\x12\x10\n" +
+	"\x03var\x18\x03 \x01(\tR\x03var\x12\x1b\n" +
+	"\bleft_int\x18\x04 \x01(\x03H\x00R\aleftInt\x12\x1b\n" +
+	"\bleft_var\x18\x05 \x01(\tH\x00R\aleftVar\x12\x1d\n" +
+	"\tright_int\x18\x06 \x01(\x03H\x01R\brightInt\x12\x1d\n" +
+	"\tright_var\x18\a \x01(\tH\x01R\brightVar\x12\x1d\n" +
+	"\adefault\x18\b \x01(\x03H\x02R\adefault\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"value_type\x18\t \x01(\tR\tvalueTypeB\v\n" +
+	"\tleft_typeB\f\n" +
+	"\n" +
+	"right_typeB\n" +
+	"\n" +
+	"\b_default\"\xcc\x01\n" +
+	"\x10CalculateRequest\x12;\n" +
+	"\finstructions\x18\x01 \x03(\v2\x17.calculator.InstructionR\finstructions\x12@\n" +
+	"\x06inputs\x18\x02 \x03(\v2(.calculator.CalculateRequest.InputsEntryR\x06inputs\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"4\n" +
+	"\n" +
+	"ResultItem\x12\x10\n" +
+	"\x03var\x18\x01 \x01(\tR\x03var\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value\"\xbf\x01\n" +
+	"\x11CalculateResponse\x12,\n" +
+	"\x05items\x18\x01 \x03(\v2\x16.calculator.ResultItemR\x05items\x12A\n" +
+	"\x06inputs\x18\x02 \x03(\v2).calculator.CalculateResponse.InputsEntryR\x06inputs\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xb6\x01\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12%\n" +
+	"\x0evariable_count\x18\x04 \x01(\x05R\rvariableCount\"\x16\n" +
+	"\x14CreateSessionRequest\"2\n" +
+	"\x11GetSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"5\n" +
+	"\x14DeleteSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"\x17\n" +
+	"\x15DeleteSessionResponse\"5\n" +
+	"\x14ListVariablesRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"E\n" +
+	"\x15ListVariablesResponse\x12,\n" +
+	"\x05items\x18\x01 \x03(\v2\x16.calculator.ResultItemR\x05items\"\xf9\x01\n" +
+	"\x17SessionCalculateRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12;\n" +
+	"\finstructions\x18\x02 \x03(\v2\x17.calculator.InstructionR\finstructions\x12G\n" +
+	"\x06inputs\x18\x03 \x03(\v2/.calculator.SessionCalculateRequest.InputsEntryR\x06inputs\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xb4\x01\n" +
+	"\x13UpdateInputsRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12C\n" +
+	"\x06values\x18\x02 \x03(\v2+.calculator.UpdateInputsRequest.ValuesEntryR\x06values\x1a9\n" +
+	"\vValuesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\\\n" +
+	"\x0eVariableChange\x12\x10\n" +
+	"\x03var\x18\x01 \x01(\tR\x03var\x12\x1b\n" +
+	"\told_value\x18\x02 \x01(\x03R\boldValue\x12\x1b\n" +
+	"\tnew_value\x18\x03 \x01(\x03R\bnewValue\"L\n" +
+	"\x14UpdateInputsResponse\x124\n" +
+	"\achanges\x18\x01 \x03(\v2\x1a.calculator.VariableChangeR\achanges\"\xb6\x01\n" +
+	"\x0eProgramVersion\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12;\n" +
+	"\finstructions\x18\x03 \x03(\v2\x17.calculator.InstructionR\finstructions\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xa2\x01\n" +
+	"\x0eProgramSummary\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12%\n" +
+	"\x0elatest_version\x18\x02 \x01(\x05R\rlatestVersion\x12\x1a\n" +
+	"\bversions\x18\x03 \x03(\x05R\bversions\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"g\n" +
+	"\x14CreateProgramRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12;\n" +
+	"\finstructions\x18\x02 \x03(\v2\x17.calculator.InstructionR\finstructions\"\x15\n" +
+	"\x13ListProgramsRequest\"N\n" +
+	"\x14ListProgramsResponse\x126\n" +
+	"\bprograms\x18\x01 \x03(\v2\x1a.calculator.ProgramSummaryR\bprograms\"A\n" +
+	"\x11GetProgramRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"\xc7\x01\n" +
+	"\x15ExecuteProgramRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12E\n" +
+	"\x06inputs\x18\x03 \x03(\v2-.calculator.ExecuteProgramRequest.InputsEntryR\x06inputs\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xf7\x01\n" +
+	"\x16ExecuteProgramResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12,\n" +
+	"\x05items\x18\x03 \x03(\v2\x16.calculator.ResultItemR\x05items\x12F\n" +
+	"\x06inputs\x18\x04 \x03(\v2..calculator.ExecuteProgramResponse.InputsEntryR\x06inputs\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"*\n" +
+	"\x14DeleteProgramRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x17\n" +
+	"\x15DeleteProgramResponse\"j\n" +
+	"\x12DiffProgramRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\ffrom_version\x18\x02 \x01(\x05R\vfromVersion\x12\x1d\n" +
+	"\n" +
+	"to_version\x18\x03 \x01(\x05R\ttoVersion\"{\n" +
+	"\x11InstructionChange\x12\x10\n" +
+	"\x03var\x18\x01 \x01(\tR\x03var\x12+\n" +
+	"\x04from\x18\x02 \x01(\v2\x17.calculator.InstructionR\x04from\x12'\n" +
+	"\x02to\x18\x03 \x01(\v2\x17.calculator.InstructionR\x02to\"\x86\x02\n" +
+	"\x13DiffProgramResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\ffrom_version\x18\x02 \x01(\x05R\vfromVersion\x12\x1d\n" +
+	"\n" +
+	"to_version\x18\x03 \x01(\x05R\ttoVersion\x12-\n" +
+	"\x05added\x18\x04 \x03(\v2\x17.calculator.InstructionR\x05added\x121\n" +
+	"\aremoved\x18\x05 \x03(\v2\x17.calculator.InstructionR\aremoved\x127\n" +
+	"\achanged\x18\x06 \x03(\v2\x1d.calculator.InstructionChangeR\achanged\"y\n" +
+	"\fBatchProgram\x12;\n" +
+	"\finstructions\x18\x01 \x03(\v2\x17.calculator.InstructionR\finstructions\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\"\x7f\n" +
+	"\bBatchRow\x128\n" +
+	"\x06inputs\x18\x01 \x03(\v2 .calculator.BatchRow.InputsEntryR\x06inputs\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"w\n" +
+	"\fBatchRequest\x124\n" +
+	"\aprogram\x18\x01 \x01(\v2\x18.calculator.BatchProgramH\x00R\aprogram\x12(\n" +
+	"\x03row\x18\x02 \x01(\v2\x14.calculator.BatchRowH\x00R\x03rowB\a\n" +
+	"\x05frame\"\xe1\x01\n" +
+	"\x0eBatchRowResult\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12>\n" +
+	"\x06inputs\x18\x02 \x03(\v2&.calculator.BatchRowResult.InputsEntryR\x06inputs\x12,\n" +
+	"\x05items\x18\x03 \x03(\v2\x16.calculator.ResultItemR\x05items\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"X\n" +
+	"\fBatchSummary\x12\x12\n" +
+	"\x04rows\x18\x01 \x01(\x05R\x04rows\x12\x1c\n" +
+	"\tsucceeded\x18\x02 \x01(\x05R\tsucceeded\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x05R\x06failed\"\x84\x01\n" +
+	"\rBatchResponse\x124\n" +
+	"\x06result\x18\x01 \x01(\v2\x1a.calculator.BatchRowResultH\x00R\x06result\x124\n" +
+	"\asummary\x18\x02 \x01(\v2\x18.calculator.BatchSummaryH\x00R\asummaryB\a\n" +
+	"\x05frame\"\xbb\x01\n" +
+	"\x16CalculateStreamRequest\x12/\n" +
+	"\x05start\x18\x01 \x01(\v2\x17.calculator.StreamStartH\x00R\x05start\x12;\n" +
+	"\vinstruction\x18\x02 \x01(\v2\x17.calculator.InstructionH\x00R\vinstruction\x12*\n" +
+	"\x03end\x18\x03 \x01(\v2\x16.calculator.EndOfInputH\x00R\x03endB\a\n" +
+	"\x05frame\"\x85\x01\n" +
+	"\vStreamStart\x12;\n" +
+	"\x06inputs\x18\x01 \x03(\v2#.calculator.StreamStart.InputsEntryR\x06inputs\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\f\n" +
+	"\n" +
+	"EndOfInput\"M\n" +
+	"\vStreamError\x12\x10\n" +
+	"\x03var\x18\x01 \x01(\tR\x03var\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\x05R\x04code\"\xe1\x01\n" +
+	"\n" +
+	"StreamDone\x12\"\n" +
+	"\finstructions\x18\x01 \x01(\x05R\finstructions\x12\x18\n" +
+	"\aprinted\x18\x02 \x01(\x05R\aprinted\x12\x1e\n" +
+	"\n" +
+	"unresolved\x18\x03 \x03(\tR\n" +
+	"unresolved\x12:\n" +
+	"\x06inputs\x18\x04 \x03(\v2\".calculator.StreamDone.InputsEntryR\x06inputs\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xaf\x01\n" +
+	"\x17CalculateStreamResponse\x12,\n" +
+	"\x04item\x18\x01 \x01(\v2\x16.calculator.ResultItemH\x00R\x04item\x12/\n" +
+	"\x05error\x18\x02 \x01(\v2\x17.calculator.StreamErrorH\x00R\x05error\x12,\n" +
+	"\x04done\x18\x03 \x01(\v2\x16.calculator.StreamDoneH\x00R\x04doneB\a\n" +
+	"\x05frame\"\xe6\x01\n" +
+	"\x15BatchCalculateProgram\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12;\n" +
+	"\finstructions\x18\x02 \x03(\v2\x17.calculator.InstructionR\finstructions\x12E\n" +
+	"\x06inputs\x18\x03 \x03(\v2-.calculator.BatchCalculateProgram.InputsEntryR\x06inputs\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"u\n" +
+	"\x15BatchCalculateRequest\x12=\n" +
+	"\bprograms\x18\x01 \x03(\v2!.calculator.BatchCalculateProgramR\bprograms\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x02 \x01(\x03R\ttimeoutMs\"\xdb\x01\n" +
+	"\x14BatchCalculateResult\x12,\n" +
+	"\x05items\x18\x01 \x03(\v2\x16.calculator.ResultItemR\x05items\x12D\n" +
+	"\x06inputs\x18\x02 \x03(\v2,.calculator.BatchCalculateResult.InputsEntryR\x06inputs\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xf7\x01\n" +
+	"\x16BatchCalculateResponse\x12I\n" +
+	"\aresults\x18\x01 \x03(\v2/.calculator.BatchCalculateResponse.ResultsEntryR\aresults\x12\x1c\n" +
+	"\tsucceeded\x18\x02 \x01(\x05R\tsucceeded\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x05R\x06failed\x1a\\\n" +
+	"\fResultsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x126\n" +
+	"\x05value\x18\x02 \x01(\v2 .calculator.BatchCalculateResultR\x05value:\x028\x01\"7\n" +
+	"\vJobCallback\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"\x81\x02\n" +
+	"\x10SubmitJobRequest\x12;\n" +
+	"\finstructions\x18\x01 \x03(\v2\x17.calculator.InstructionR\finstructions\x12@\n" +
+	"\x06inputs\x18\x02 \x03(\v2(.calculator.SubmitJobRequest.InputsEntryR\x06inputs\x123\n" +
+	"\bcallback\x18\x03 \x01(\v2\x17.calculator.JobCallbackR\bcallback\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\x1f\n" +
+	"\rGetJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\"\n" +
+	"\x10CancelJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"%\n" +
+	"\x13RedeliverJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xae\x01\n" +
+	"\x0fDeliveryAttempt\x12\x1a\n" +
+	"\bdelivery\x18\x01 \x01(\x05R\bdelivery\x12\x18\n" +
+	"\aattempt\x18\x02 \x01(\x05R\aattempt\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x1f\n" +
+	"\vstatus_code\x18\x04 \x01(\x05R\n" +
+	"statusCode\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"\x95\x01\n" +
+	"\n" +
+	"JobWebhook\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1c\n" +
+	"\tdelivered\x18\x02 \x01(\bR\tdelivered\x12\x1e\n" +
+	"\n" +
+	"delivering\x18\x03 \x01(\bR\n" +
+	"delivering\x127\n" +
+	"\battempts\x18\x04 \x03(\v2\x1b.calculator.DeliveryAttemptR\battempts\"\x8e\x04\n" +
+	"\x03Job\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12*\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x14.calculator.JobStateR\x05state\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\x12\x1c\n" +
+	"\tcompleted\x18\x04 \x01(\x05R\tcompleted\x12,\n" +
+	"\x05items\x18\x05 \x03(\v2\x16.calculator.ResultItemR\x05items\x123\n" +
+	"\x06inputs\x18\x06 \x03(\v2\x1b.calculator.Job.InputsEntryR\x06inputs\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"started_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x120\n" +
+	"\awebhook\x18\v \x01(\v2\x16.calculator.JobWebhookR\awebhook\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01*\x99\x01\n" +
+	"\bJobState\x12\x19\n" +
+	"\x15JOB_STATE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10JOB_STATE_QUEUED\x10\x01\x12\x15\n" +
+	"\x11JOB_STATE_RUNNING\x10\x02\x12\x17\n" +
+	"\x13JOB_STATE_SUCCEEDED\x10\x03\x12\x14\n" +
+	"\x10JOB_STATE_FAILED\x10\x04\x12\x16\n" +
+	"\x12JOB_STATE_CANCELED\x10\x052\x9b\f\n" +
+	"\x11CalculatorService\x12H\n" +
+	"\tCalculate\x12\x1c.calculator.CalculateRequest\x1a\x1d.calculator.CalculateResponse\x12F\n" +
+	"\rCreateSession\x12 .calculator.CreateSessionRequest\x1a\x13.calculator.Session\x12@\n" +
+	"\n" +
+	"GetSession\x12\x1d.calculator.GetSessionRequest\x1a\x13.calculator.Session\x12T\n" +
+	"\rDeleteSession\x12 .calculator.DeleteSessionRequest\x1a!.calculator.DeleteSessionResponse\x12T\n" +
+	"\rListVariables\x12 .calculator.ListVariablesRequest\x1a!.calculator.ListVariablesResponse\x12V\n" +
+	"\x10SessionCalculate\x12#.calculator.SessionCalculateRequest\x1a\x1d.calculator.CalculateResponse\x12Q\n" +
+	"\fUpdateInputs\x12\x1f.calculator.UpdateInputsRequest\x1a .calculator.UpdateInputsResponse\x12M\n" +
+	"\rCreateProgram\x12 .calculator.CreateProgramRequest\x1a\x1a.calculator.ProgramVersion\x12Q\n" +
+	"\fListPrograms\x12\x1f.calculator.ListProgramsRequest\x1a .calculator.ListProgramsResponse\x12G\n" +
+	"\n" +
+	"GetProgram\x12\x1d.calculator.GetProgramRequest\x1a\x1a.calculator.ProgramVersion\x12W\n" +
+	"\x0eExecuteProgram\x12!.calculator.ExecuteProgramRequest\x1a\".calculator.ExecuteProgramResponse\x12T\n" +
+	"\rDeleteProgram\x12 .calculator.DeleteProgramRequest\x1a!.calculator.DeleteProgramResponse\x12N\n" +
+	"\vDiffProgram\x12\x1e.calculator.DiffProgramRequest\x1a\x1f.calculator.DiffProgramResponse\x12H\n" +
+	"\rBatchEvaluate\x12\x18.calculator.BatchRequest\x1a\x19.calculator.BatchResponse(\x010\x01\x12W\n" +
+	"\x0eBatchCalculate\x12!.calculator.BatchCalculateRequest\x1a\".calculator.BatchCalculateResponse\x12^\n" +
+	"\x0fCalculateStream\x12\".calculator.CalculateStreamRequest\x1a#.calculator.CalculateStreamResponse(\x010\x01\x12:\n" +
+	"\tSubmitJob\x12\x1c.calculator.SubmitJobRequest\x1a\x0f.calculator.Job\x124\n" +
+	"\x06GetJob\x12\x19.calculator.GetJobRequest\x1a\x0f.calculator.Job\x12:\n" +
+	"\tCancelJob\x12\x1c.calculator.CancelJobRequest\x1a\x0f.calculator.Job\x12@\n" +
+	"\fRedeliverJob\x12\x1f.calculator.RedeliverJobRequest\x1a\x0f.calculator.JobB\x0fZ\rcalculator/pbb\x06proto3"
+
+var (
+	file_proto_calculator_proto_rawDescOnce sync.Once
+	file_proto_calculator_proto_rawDescData []byte
+)
+
+func file_proto_calculator_proto_rawDescGZIP() []byte {
+	file_proto_calculator_proto_rawDescOnce.Do(func() {
+		file_proto_calculator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_calculator_proto_rawDesc), len(file_proto_calculator_proto_rawDesc)))
+	})
+	return file_proto_calculator_proto_rawDescData
+}
+
+var file_proto_calculator_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_calculator_proto_msgTypes = make([]protoimpl.MessageInfo, 67)
+var file_proto_calculator_proto_goTypes = []any{
+	(JobState)(0),                   // 0: calculator.JobState
+	(*Instruction)(nil),             // 1: calculator.Instruction
+	(*CalculateRequest)(nil),        // 2: calculator.CalculateRequest
+	(*ResultItem)(nil),              // 3: calculator.ResultItem
+	(*CalculateResponse)(nil),       // 4: calculator.CalculateResponse
+	(*Session)(nil),                 // 5: calculator.Session
+	(*CreateSessionRequest)(nil),    // 6: calculator.CreateSessionRequest
+	(*GetSessionRequest)(nil),       // 7: calculator.GetSessionRequest
+	(*DeleteSessionRequest)(nil),    // 8: calculator.DeleteSessionRequest
+	(*DeleteSessionResponse)(nil),   // 9: calculator.DeleteSessionResponse
+	(*ListVariablesRequest)(nil),    // 10: calculator.ListVariablesRequest
+	(*ListVariablesResponse)(nil),   // 11: calculator.ListVariablesResponse
+	(*SessionCalculateRequest)(nil), // 12: calculator.SessionCalculateRequest
+	(*UpdateInputsRequest)(nil),     // 13: calculator.UpdateInputsRequest
+	(*VariableChange)(nil),          // 14: calculator.VariableChange
+	(*UpdateInputsResponse)(nil),    // 15: calculator.UpdateInputsResponse
+	(*ProgramVersion)(nil),          // 16: calculator.ProgramVersion
+	(*ProgramSummary)(nil),          // 17: calculator.ProgramSummary
+	(*CreateProgramRequest)(nil),    // 18: calculator.CreateProgramRequest
+	(*ListProgramsRequest)(nil),     // 19: calculator.ListProgramsRequest
+	(*ListProgramsResponse)(nil),    // 20: calculator.ListProgramsResponse
+	(*GetProgramRequest)(nil),       // 21: calculator.GetProgramRequest
+	(*ExecuteProgramRequest)(nil),   // 22: calculator.ExecuteProgramRequest
+	(*ExecuteProgramResponse)(nil),  // 23: calculator.ExecuteProgramResponse
+	(*DeleteProgramRequest)(nil),    // 24: calculator.DeleteProgramRequest
+	(*DeleteProgramResponse)(nil),   // 25: calculator.DeleteProgramResponse
+	(*DiffProgramRequest)(nil),      // 26: calculator.DiffProgramRequest
+	(*InstructionChange)(nil),       // 27: calculator.InstructionChange
+	(*DiffProgramResponse)(nil),     // 28: calculator.DiffProgramResponse
+	(*BatchProgram)(nil),            // 29: calculator.BatchProgram
+	(*BatchRow)(nil),                // 30: calculator.BatchRow
+	(*BatchRequest)(nil),            // 31: calculator.BatchRequest
+	(*BatchRowResult)(nil),          // 32: calculator.BatchRowResult
+	(*BatchSummary)(nil),            // 33: calculator.BatchSummary
+	(*BatchResponse)(nil),           // 34: calculator.BatchResponse
+	(*CalculateStreamRequest)(nil),  // 35: calculator.CalculateStreamRequest
+	(*StreamStart)(nil),             // 36: calculator.StreamStart
+	(*EndOfInput)(nil),              // 37: calculator.EndOfInput
+	(*StreamError)(nil),             // 38: calculator.StreamError
+	(*StreamDone)(nil),              // 39: calculator.StreamDone
+	(*CalculateStreamResponse)(nil), // 40: calculator.CalculateStreamResponse
+	(*BatchCalculateProgram)(nil),   // 41: calculator.BatchCalculateProgram
+	(*BatchCalculateRequest)(nil),   // 42: calculator.BatchCalculateRequest
+	(*BatchCalculateResult)(nil),    // 43: calculator.BatchCalculateResult
+	(*BatchCalculateResponse)(nil),  // 44: calculator.BatchCalculateResponse
+	(*JobCallback)(nil),             // 45: calculator.JobCallback
+	(*SubmitJobRequest)(nil),        // 46: calculator.SubmitJobRequest
+	(*GetJobRequest)(nil),           // 47: calculator.GetJobRequest
+	(*CancelJobRequest)(nil),        // 48: calculator.CancelJobRequest
+	(*RedeliverJobRequest)(nil),     // 49: calculator.RedeliverJobRequest
+	(*DeliveryAttempt)(nil),         // 50: calculator.DeliveryAttempt
+	(*JobWebhook)(nil),              // 51: calculator.JobWebhook
+	(*Job)(nil),                     // 52: calculator.Job
+	nil,                             // 53: calculator.CalculateRequest.InputsEntry
+	nil,                             // 54: calculator.CalculateResponse.InputsEntry
+	nil,                             // 55: calculator.SessionCalculateRequest.InputsEntry
+	nil,                             // 56: calculator.UpdateInputsRequest.ValuesEntry
+	nil,                             // 57: calculator.ExecuteProgramRequest.InputsEntry
+	nil,                             // 58: calculator.ExecuteProgramResponse.InputsEntry
+	nil,                             // 59: calculator.BatchRow.InputsEntry
+	nil,                             // 60: calculator.BatchRowResult.InputsEntry
+	nil,                             // 61: calculator.StreamStart.InputsEntry
+	nil,                             // 62: calculator.StreamDone.InputsEntry
+	nil,                             // 63: calculator.BatchCalculateProgram.InputsEntry
+	nil,                             // 64: calculator.BatchCalculateResult.InputsEntry
+	nil,                             // 65: calculator.BatchCalculateResponse.ResultsEntry
+	nil,                             // 66: calculator.SubmitJobRequest.InputsEntry
+	nil,                             // 67: calculator.Job.InputsEntry
+	(*timestamppb.Timestamp)(nil),   // 68: google.protobuf.Timestamp
+}
+var file_proto_calculator_proto_depIdxs = []int32{
+	1,  // 0: calculator.CalculateRequest.instructions:type_name -> calculator.Instruction
+	53, // 1: calculator.CalculateRequest.inputs:type_name -> calculator.CalculateRequest.InputsEntry
+	3,  // 2: calculator.CalculateResponse.items:type_name -> calculator.ResultItem
+	54, // 3: calculator.CalculateResponse.inputs:type_name -> calculator.CalculateResponse.InputsEntry
+	68, // 4: calculator.Session.created_at:type_name -> google.protobuf.Timestamp
+	68, // 5: calculator.Session.expires_at:type_name -> google.protobuf.Timestamp
+	3,  // 6: calculator.ListVariablesResponse.items:type_name -> calculator.ResultItem
+	1,  // 7: calculator.SessionCalculateRequest.instructions:type_name -> calculator.Instruction
+	55, // 8: calculator.SessionCalculateRequest.inputs:type_name -> calculator.SessionCalculateRequest.InputsEntry
+	56, // 9: calculator.UpdateInputsRequest.values:type_name -> calculator.UpdateInputsRequest.ValuesEntry
+	14, // 10: calculator.UpdateInputsResponse.changes:type_name -> calculator.VariableChange
+	1,  // 11: calculator.ProgramVersion.instructions:type_name -> calculator.Instruction
+	68, // 12: calculator.ProgramVersion.created_at:type_name -> google.protobuf.Timestamp
+	68, // 13: calculator.ProgramSummary.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 14: calculator.CreateProgramRequest.instructions:type_name -> calculator.Instruction
+	17, // 15: calculator.ListProgramsResponse.programs:type_name -> calculator.ProgramSummary
+	57, // 16: calculator.ExecuteProgramRequest.inputs:type_name -> calculator.ExecuteProgramRequest.InputsEntry
+	3,  // 17: calculator.ExecuteProgramResponse.items:type_name -> calculator.ResultItem
+	58, // 18: calculator.ExecuteProgramResponse.inputs:type_name -> calculator.ExecuteProgramResponse.InputsEntry
+	1,  // 19: calculator.InstructionChange.from:type_name -> calculator.Instruction
+	1,  // 20: calculator.InstructionChange.to:type_name -> calculator.Instruction
+	1,  // 21: calculator.DiffProgramResponse.added:type_name -> calculator.Instruction
+	1,  // 22: calculator.DiffProgramResponse.removed:type_name -> calculator.Instruction
+	27, // 23: calculator.DiffProgramResponse.changed:type_name -> calculator.InstructionChange
+	1,  // 24: calculator.BatchProgram.instructions:type_name -> calculator.Instruction
+	59, // 25: calculator.BatchRow.inputs:type_name -> calculator.BatchRow.InputsEntry
+	29, // 26: calculator.BatchRequest.program:type_name -> calculator.BatchProgram
+	30, // 27: calculator.BatchRequest.row:type_name -> calculator.BatchRow
+	60, // 28: calculator.BatchRowResult.inputs:type_name -> calculator.BatchRowResult.InputsEntry
+	3,  // 29: calculator.BatchRowResult.items:type_name -> calculator.ResultItem
+	32, // 30: calculator.BatchResponse.result:type_name -> calculator.BatchRowResult
+	33, // 31: calculator.BatchResponse.summary:type_name -> calculator.BatchSummary
+	36, // 32: calculator.CalculateStreamRequest.start:type_name -> calculator.StreamStart
+	1,  // 33: calculator.CalculateStreamRequest.instruction:type_name -> calculator.Instruction
+	37, // 34: calculator.CalculateStreamRequest.end:type_name -> calculator.EndOfInput
+	61, // 35: calculator.StreamStart.inputs:type_name -> calculator.StreamStart.InputsEntry
+	62, // 36: calculator.StreamDone.inputs:type_name -> calculator.StreamDone.InputsEntry
+	3,  // 37: calculator.CalculateStreamResponse.item:type_name -> calculator.ResultItem
+	38, // 38: calculator.CalculateStreamResponse.error:type_name -> calculator.StreamError
+	39, // 39: calculator.CalculateStreamResponse.done:type_name -> calculator.StreamDone
+	1,  // 40: calculator.BatchCalculateProgram.instructions:type_name -> calculator.Instruction
+	63, // 41: calculator.BatchCalculateProgram.inputs:type_name -> calculator.BatchCalculateProgram.InputsEntry
+	41, // 42: calculator.BatchCalculateRequest.programs:type_name -> calculator.BatchCalculateProgram
+	3,  // 43: calculator.BatchCalculateResult.items:type_name -> calculator.ResultItem
+	64, // 44: calculator.BatchCalculateResult.inputs:type_name -> calculator.BatchCalculateResult.InputsEntry
+	65, // 45: calculator.BatchCalculateResponse.results:type_name -> calculator.BatchCalculateResponse.ResultsEntry
+	1,  // 46: calculator.SubmitJobRequest.instructions:type_name -> calculator.Instruction
+	66, // 47: calculator.SubmitJobRequest.inputs:type_name -> calculator.SubmitJobRequest.InputsEntry
+	45, // 48: calculator.SubmitJobRequest.callback:type_name -> calculator.JobCallback
+	68, // 49: calculator.DeliveryAttempt.time:type_name -> google.protobuf.Timestamp
+	50, // 50: calculator.JobWebhook.attempts:type_name -> calculator.DeliveryAttempt
+	0,  // 51: calculator.Job.state:type_name -> calculator.JobState
+	3,  // 52: calculator.Job.items:type_name -> calculator.ResultItem
+	67, // 53: calculator.Job.inputs:type_name -> calculator.Job.InputsEntry
+	68, // 54: calculator.Job.created_at:type_name -> google.protobuf.Timestamp
+	68, // 55: calculator.Job.started_at:type_name -> google.protobuf.Timestamp
+	68, // 56: calculator.Job.finished_at:type_name -> google.protobuf.Timestamp
+	51, // 57: calculator.Job.webhook:type_name -> calculator.JobWebhook
+	43, // 58: calculator.BatchCalculateResponse.ResultsEntry.value:type_name -> calculator.BatchCalculateResult
+	2,  // 59: calculator.CalculatorService.Calculate:input_type -> calculator.CalculateRequest
+	6,  // 60: calculator.CalculatorService.CreateSession:input_type -> calculator.CreateSessionRequest
+	7,  // 61: calculator.CalculatorService.GetSession:input_type -> calculator.GetSessionRequest
+	8,  // 62: calculator.CalculatorService.DeleteSession:input_type -> calculator.DeleteSessionRequest
+	10, // 63: calculator.CalculatorService.ListVariables:input_type -> calculator.ListVariablesRequest
+	12, // 64: calculator.CalculatorService.SessionCalculate:input_type -> calculator.SessionCalculateRequest
+	13, // 65: calculator.CalculatorService.UpdateInputs:input_type -> calculator.UpdateInputsRequest
+	18, // 66: calculator.CalculatorService.CreateProgram:input_type -> calculator.CreateProgramRequest
+	19, // 67: calculator.CalculatorService.ListPrograms:input_type -> calculator.ListProgramsRequest
+	21, // 68: calculator.CalculatorService.GetProgram:input_type -> calculator.GetProgramRequest
+	22, // 69: calculator.CalculatorService.ExecuteProgram:input_type -> calculator.ExecuteProgramRequest
+	24, // 70: calculator.CalculatorService.DeleteProgram:input_type -> calculator.DeleteProgramRequest
+	26, // 71: calculator.CalculatorService.DiffProgram:input_type -> calculator.DiffProgramRequest
+	31, // 72: calculator.CalculatorService.BatchEvaluate:input_type -> calculator.BatchRequest
+	42, // 73: calculator.CalculatorService.BatchCalculate:input_type -> calculator.BatchCalculateRequest
+	35, // 74: calculator.CalculatorService.CalculateStream:input_type -> calculator.CalculateStreamRequest
+	46, // 75: calculator.CalculatorService.SubmitJob:input_type -> calculator.SubmitJobRequest
+	47, // 76: calculator.CalculatorService.GetJob:input_type -> calculator.GetJobRequest
+	48, // 77: calculator.CalculatorService.CancelJob:input_type -> calculator.CancelJobRequest
+	49, // 78: calculator.CalculatorService.RedeliverJob:input_type -> calculator.RedeliverJobRequest
+	4,  // 79: calculator.CalculatorService.Calculate:output_type -> calculator.CalculateResponse
+	5,  // 80: calculator.CalculatorService.CreateSession:output_type -> calculator.Session
+	5,  // 81: calculator.CalculatorService.GetSession:output_type -> calculator.Session
+	9,  // 82: calculator.CalculatorService.DeleteSession:output_type -> calculator.DeleteSessionResponse
+	11, // 83: calculator.CalculatorService.ListVariables:output_type -> calculator.ListVariablesResponse
+	4,  // 84: calculator.CalculatorService.SessionCalculate:output_type -> calculator.CalculateResponse
+	15, // 85: calculator.CalculatorService.UpdateInputs:output_type -> calculator.UpdateInputsResponse
+	16, // 86: calculator.CalculatorService.CreateProgram:output_type -> calculator.ProgramVersion
+	20, // 87: calculator.CalculatorService.ListPrograms:output_type -> calculator.ListProgramsResponse
+	16, // 88: calculator.CalculatorService.GetProgram:output_type -> calculator.ProgramVersion
+	23, // 89: calculator.CalculatorService.ExecuteProgram:output_type -> calculator.ExecuteProgramResponse
+	25, // 90: calculator.CalculatorService.DeleteProgram:output_type -> calculator.DeleteProgramResponse
+	28, // 91: calculator.CalculatorService.DiffProgram:output_type -> calculator.DiffProgramResponse
+	34, // 92: calculator.CalculatorService.BatchEvaluate:output_type -> calculator.BatchResponse
+	44, // 93: calculator.CalculatorService.BatchCalculate:output_type -> calculator.BatchCalculateResponse
+	40, // 94: calculator.CalculatorService.CalculateStream:output_type -> calculator.CalculateStreamResponse
+	52, // 95: calculator.CalculatorService.SubmitJob:output_type -> calculator.Job
+	52, // 96: calculator.CalculatorService.GetJob:output_type -> calculator.Job
+	52, // 97: calculator.CalculatorService.CancelJob:output_type -> calculator.Job
+	52, // 98: calculator.CalculatorService.RedeliverJob:output_type -> calculator.Job
+	79, // [79:99] is the sub-list for method output_type
+	59, // [59:79] is the sub-list for method input_type
+	59, // [59:59] is the sub-list for extension type_name
+	59, // [59:59] is the sub-list for extension extendee
+	0,  // [0:59] is the sub-list for field type_name
+}
+
+func init() { file_proto_calculator_proto_init() }
+func file_proto_calculator_proto_init() {
+	if File_proto_calculator_proto != nil {
+		return
+	}
+	file_proto_calculator_proto_msgTypes[0].OneofWrappers = []any{
+		(*Instruction_LeftInt)(nil),
+		(*Instruction_LeftVar)(nil),
+		(*Instruction_RightInt)(nil),
+		(*Instruction_RightVar)(nil),
+	}
+	file_proto_calculator_proto_msgTypes[30].OneofWrappers = []any{
+		(*BatchRequest_Program)(nil),
+		(*BatchRequest_Row)(nil),
+	}
+	file_proto_calculator_proto_msgTypes[33].OneofWrappers = []any{
+		(*BatchResponse_Result)(nil),
+		(*BatchResponse_Summary)(nil),
+	}
+	file_proto_calculator_proto_msgTypes[34].OneofWrappers = []any{
+		(*CalculateStreamRequest_Start)(nil),
+		(*CalculateStreamRequest_Instruction)(nil),
+		(*CalculateStreamRequest_End)(nil),
+	}
+	file_proto_calculator_proto_msgTypes[39].OneofWrappers = []any{
+		(*CalculateStreamResponse_Item)(nil),
+		(*CalculateStreamResponse_Error)(nil),
+		(*CalculateStreamResponse_Done)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_calculator_proto_rawDesc), len(file_proto_calculator_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      1,
+			NumMessages:   67,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_calculator_proto_goTypes,
 		DependencyIndexes: file_proto_calculator_proto_depIdxs,
+		EnumInfos:         file_proto_calculator_proto_enumTypes,
 		MessageInfos:      file_proto_calculator_proto_msgTypes,
 	}.Build()
 	File_proto_calculator_proto = out.File
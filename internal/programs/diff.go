@@ -0,0 +1,86 @@
+package programs
+
+import (
+	"encoding/json"
+	"sort"
+
+	"calculator/internal/service"
+)
+
+// Diff — различия между двумя версиями программы. Инструкции
+// сопоставляются по типу и имени переменной.
+type Diff struct {
+	Name    string                `json:"name"`
+	From    int                   `json:"from"`
+	To      int                   `json:"to"`
+	Added   []service.Instruction `json:"added"`
+	Removed []service.Instruction `json:"removed"`
+	Changed []InstructionChange   `json:"changed"`
+}
+
+type InstructionChange struct {
+	Var  string              `json:"var"`
+	From service.Instruction `json:"from"`
+	To   service.Instruction `json:"to"`
+}
+
+func (r *Registry) Diff(name string, from, to int) (Diff, error) {
+	a, err := r.Get(name, from)
+	if err != nil {
+		return Diff{}, err
+	}
+	b, err := r.Get(name, to)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	diff := Diff{
+		Name:    name,
+		From:    a.Version,
+		To:      b.Version,
+		Added:   make([]service.Instruction, 0),
+		Removed: make([]service.Instruction, 0),
+		Changed: make([]InstructionChange, 0),
+	}
+	before := indexInstructions(a.Instructions)
+	after := indexInstructions(b.Instructions)
+
+	for _, instr := range b.Instructions {
+		old, ok := before[instructionKey(instr)]
+		if !ok {
+			diff.Added = append(diff.Added, instr)
+			continue
+		}
+		if !sameInstruction(old, instr) {
+			diff.Changed = append(diff.Changed, InstructionChange{Var: instr.Var, From: old, To: instr})
+		}
+	}
+	for _, instr := range a.Instructions {
+		if _, ok := after[instructionKey(instr)]; !ok {
+			diff.Removed = append(diff.Removed, instr)
+		}
+	}
+	sort.SliceStable(diff.Changed, func(i, j int) bool { return diff.Changed[i].Var < diff.Changed[j].Var })
+	return diff, nil
+}
+
+func instructionKey(instr service.Instruction) string {
+	if instr.Type == "print" {
+		return "print:" + instr.Var
+	}
+	return "var:" + instr.Var
+}
+
+func indexInstructions(instructions []service.Instruction) map[string]service.Instruction {
+	index := make(map[string]service.Instruction, len(instructions))
+	for _, instr := range instructions {
+		index[instructionKey(instr)] = instr
+	}
+	return index
+}
+
+func sameInstruction(a, b service.Instruction) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
@@ -0,0 +1,248 @@
+package programs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"calculator/internal/service"
+	"calculator/internal/storage"
+)
+
+var (
+	ErrNotFound       = errors.New("program not found")
+	ErrInvalidName    = errors.New("invalid program name")
+	ErrInvalidVersion = errors.New("invalid program version")
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// Ключи хранилища:
+//
+//	program/<name>/<version>  — неизменяемая версия программы
+//	program-head/<name>       — последний выданный номер версии
+const (
+	versionPrefix = "program/"
+	headPrefix    = "program-head/"
+)
+
+// Version — одна неизменяемая версия сохранённой программы.
+type Version struct {
+	Name         string                `json:"name"`
+	Version      int                   `json:"version"`
+	Instructions []service.Instruction `json:"instructions"`
+	CreatedAt    time.Time             `json:"created_at"`
+}
+
+// Summary — описание программы со списком её версий.
+type Summary struct {
+	Name          string    `json:"name"`
+	LatestVersion int       `json:"latest_version"`
+	Versions      []int     `json:"versions"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type head struct {
+	Latest  int  `json:"latest"`
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// Registry хранит именованные программы с историей версий. Номера версий
+// не переиспользуются даже после удаления программы.
+type Registry struct {
+	calc  *service.CalculatorService
+	store storage.Store
+
+	mu sync.Mutex
+}
+
+func NewRegistry(calc *service.CalculatorService, store storage.Store) *Registry {
+	return &Registry{calc: calc, store: store}
+}
+
+// Put проверяет программу и сохраняет её как новую версию.
+func (r *Registry) Put(name string, instructions []service.Instruction) (Version, error) {
+	if !namePattern.MatchString(name) {
+		return Version{}, fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	if err := r.calc.Validate(instructions); err != nil {
+		return Version{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, err := r.head(name)
+	if err != nil {
+		return Version{}, err
+	}
+	h.Latest++
+	h.Deleted = false
+
+	v := Version{
+		Name:         name,
+		Version:      h.Latest,
+		Instructions: instructions,
+		CreatedAt:    time.Now().UTC(),
+	}
+	versionPayload, err := json.Marshal(v)
+	if err != nil {
+		return Version{}, err
+	}
+	headPayload, err := json.Marshal(h)
+	if err != nil {
+		return Version{}, err
+	}
+	if err := r.store.Apply(
+		storage.Put(versionKey(name, v.Version), versionPayload),
+		storage.Put(headPrefix+name, headPayload),
+	); err != nil {
+		return Version{}, err
+	}
+	return r.decode(versionPayload)
+}
+
+// Get возвращает версию программы; version <= 0 означает последнюю.
+func (r *Registry) Get(name string, version int) (Version, error) {
+	if version <= 0 {
+		h, err := r.head(name)
+		if err != nil {
+			return Version{}, err
+		}
+		if h.Latest == 0 || h.Deleted {
+			return Version{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		version = h.Latest
+	}
+	payload, ok, err := r.store.Get(versionKey(name, version))
+	if err != nil {
+		return Version{}, err
+	}
+	if !ok {
+		return Version{}, fmt.Errorf("%w: %s version %d", ErrNotFound, name, version)
+	}
+	return r.decode(payload)
+}
+
+func (r *Registry) List() ([]Summary, error) {
+	stored, err := r.store.List(versionPrefix)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*Summary)
+	for key, payload := range stored {
+		v, err := r.decode(payload)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: %w", key, err)
+		}
+		sum, ok := byName[v.Name]
+		if !ok {
+			sum = &Summary{Name: v.Name}
+			byName[v.Name] = sum
+		}
+		sum.Versions = append(sum.Versions, v.Version)
+		if v.Version > sum.LatestVersion {
+			sum.LatestVersion = v.Version
+			sum.UpdatedAt = v.CreatedAt
+		}
+	}
+
+	summaries := make([]Summary, 0, len(byName))
+	for _, sum := range byName {
+		sort.Ints(sum.Versions)
+		summaries = append(summaries, *sum)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries, nil
+}
+
+// Delete удаляет все версии программы.
+func (r *Registry) Delete(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions, err := r.store.List(versionPrefix + name + "/")
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	h, err := r.head(name)
+	if err != nil {
+		return err
+	}
+	h.Deleted = true
+	headPayload, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+
+	ops := []storage.Op{storage.Put(headPrefix+name, headPayload)}
+	for key := range versions {
+		ops = append(ops, storage.Delete(key))
+	}
+	return r.store.Apply(ops...)
+}
+
+// Execute выполняет версию программы с переданными входами.
+func (r *Registry) Execute(ctx context.Context, name string, version int, inputs map[string]int64) (Version, []service.ResultItem, map[string]int64, error) {
+	v, err := r.Get(name, version)
+	if err != nil {
+		return Version{}, nil, nil, err
+	}
+	bound, err := service.Bind(v.Instructions, inputs)
+	if err != nil {
+		return Version{}, nil, nil, err
+	}
+	results, err := r.calc.Run(ctx, v.Instructions, bound)
+	if err != nil {
+		return Version{}, nil, nil, err
+	}
+	return v, results, bound, nil
+}
+
+func (r *Registry) head(name string) (head, error) {
+	var h head
+	payload, ok, err := r.store.Get(headPrefix + name)
+	if err != nil || !ok {
+		return h, err
+	}
+	if err := json.Unmarshal(payload, &h); err != nil {
+		return h, fmt.Errorf("decode program head %s: %w", name, err)
+	}
+	return h, nil
+}
+
+// decode восстанавливает версию так же, как она будет прочитана после
+// перезапуска; целые литералы операндов читаются как int64 без потери
+// точности.
+func (r *Registry) decode(payload []byte) (Version, error) {
+	var v Version
+	err := json.Unmarshal(payload, &v)
+	return v, err
+}
+
+func versionKey(name string, version int) string {
+	// Номер дополняется нулями, чтобы ключи сортировались по версиям.
+	return versionPrefix + name + "/" + fmt.Sprintf("%010d", version)
+}
+
+// ParseVersion разбирает номер версии из строки; пустая строка — последняя.
+func ParseVersion(s string) (int, error) {
+	if strings.TrimSpace(s) == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+	}
+	return v, nil
+}
@@ -0,0 +1,166 @@
+package programs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"calculator/internal/service"
+	"calculator/internal/storage"
+)
+
+func newTestRegistry(t *testing.T, store storage.Store) *Registry {
+	t.Helper()
+	return NewRegistry(service.NewCalculatorService(service.WithLatency(0)), store)
+}
+
+func calc(name string, left, right interface{}) service.Instruction {
+	return service.Instruction{Type: "calc", Op: "+", Var: name, Left: left, Right: right}
+}
+
+func mustPut(t *testing.T, r *Registry, name string, instructions ...service.Instruction) Version {
+	t.Helper()
+	v, err := r.Put(name, instructions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestPutGet(t *testing.T) {
+	r := newTestRegistry(t, storage.NewMemoryStore())
+	put := mustPut(t, r, "sum", calc("x", int64(1), int64(2)), service.Instruction{Type: "print", Var: "x"})
+	if put.Name != "sum" || put.Version != 1 {
+		t.Fatalf("Put = %+v, want sum version 1", put)
+	}
+
+	got, err := r.Get("sum", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(put) {
+		t.Fatalf("Get = %+v, want %+v", got, put)
+	}
+
+	if _, err := r.Get("missing", 0); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get(missing) = %v, want ErrNotFound", err)
+	}
+	if _, err := r.Put("bad name", []service.Instruction{calc("x", int64(1), int64(2))}); !errors.Is(err, ErrInvalidName) {
+		t.Fatalf("Put(bad name) = %v, want ErrInvalidName", err)
+	}
+	if _, err := r.Put("invalid", []service.Instruction{calc("x", "y", int64(2))}); !errors.Is(err, service.ErrInvalidProgram) {
+		t.Fatalf("Put(invalid program) = %v, want ErrInvalidProgram", err)
+	}
+}
+
+func TestVersionHistory(t *testing.T) {
+	r := newTestRegistry(t, storage.NewMemoryStore())
+	for i := int64(1); i <= 3; i++ {
+		if v := mustPut(t, r, "p", calc("x", i, int64(0))); v.Version != int(i) {
+			t.Fatalf("version = %d, want %d", v.Version, i)
+		}
+	}
+	for version := 1; version <= 3; version++ {
+		v, err := r.Get("p", version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if left := v.Instructions[0].Left; left != int64(version) {
+			t.Fatalf("version %d: left = %#v", version, left)
+		}
+	}
+	if _, err := r.Get("p", 4); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get(p, 4) = %v, want ErrNotFound", err)
+	}
+
+	summaries, err := r.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 1 || summaries[0].LatestVersion != 3 || fmt.Sprint(summaries[0].Versions) != "[1 2 3]" {
+		t.Fatalf("List = %+v", summaries)
+	}
+}
+
+// После удаления программа не находится, а номера версий продолжаются.
+func TestDelete(t *testing.T) {
+	r := newTestRegistry(t, storage.NewMemoryStore())
+	mustPut(t, r, "p", calc("x", int64(1), int64(0)))
+	mustPut(t, r, "p", calc("x", int64(2), int64(0)))
+	if err := r.Delete("p"); err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range []int{0, 1, 2} {
+		if _, err := r.Get("p", version); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Get(p, %d) after delete = %v, want ErrNotFound", version, err)
+		}
+	}
+	if err := r.Delete("p"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("second Delete = %v, want ErrNotFound", err)
+	}
+	if v := mustPut(t, r, "p", calc("x", int64(3), int64(0))); v.Version != 3 {
+		t.Fatalf("version after delete = %d, want 3", v.Version)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	r := newTestRegistry(t, storage.NewMemoryStore())
+	mustPut(t, r, "p",
+		calc("a", int64(1), int64(0)),
+		calc("b", "a", int64(1)),
+		service.Instruction{Type: "print", Var: "b"},
+	)
+	mustPut(t, r, "p",
+		calc("a", int64(1), int64(0)),
+		calc("b", "a", int64(2)),
+		calc("c", "b", int64(1)),
+		service.Instruction{Type: "print", Var: "c"},
+	)
+
+	diff, err := r.Diff("p", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.From != 1 || diff.To != 2 {
+		t.Fatalf("diff versions = %d..%d", diff.From, diff.To)
+	}
+	if len(diff.Added) != 2 || diff.Added[0].Var != "c" || diff.Added[1].Type != "print" {
+		t.Fatalf("added = %+v, want c and print c", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Type != "print" || diff.Removed[0].Var != "b" {
+		t.Fatalf("removed = %+v, want print b", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Var != "b" || diff.Changed[0].To.Right != int64(2) {
+		t.Fatalf("changed = %+v, want b", diff.Changed)
+	}
+
+	if _, err := r.Diff("p", 1, 3); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Diff(p, 1, 3) = %v, want ErrNotFound", err)
+	}
+}
+
+// Литералы за пределами точности float64 сохраняются как есть и после
+// перезапуска.
+func TestLargeLiteralRoundTrip(t *testing.T) {
+	store := storage.NewMemoryStore()
+	r := newTestRegistry(t, store)
+	put := mustPut(t, r, "big", calc("x", int64(1<<53+1), int64(math.MinInt64)), calc("y", int64(math.MaxInt64), int64(0)))
+
+	restarted := newTestRegistry(t, store)
+	for _, v := range []Version{put, mustGet(t, restarted, "big")} {
+		instrs := v.Instructions
+		if instrs[0].Left != int64(1<<53+1) || instrs[0].Right != int64(math.MinInt64) || instrs[1].Left != int64(math.MaxInt64) {
+			t.Fatalf("operands = %#v %#v %#v", instrs[0].Left, instrs[0].Right, instrs[1].Left)
+		}
+	}
+}
+
+func mustGet(t *testing.T, r *Registry, name string) Version {
+	t.Helper()
+	v, err := r.Get(name, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
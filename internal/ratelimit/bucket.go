@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// bucket — корзина жетонов: пополняется со скоростью rate в секунду и
+// вмещает не больше burst. Нулевая скорость означает отсутствие
+// ограничения.
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rate float64, burst int, now time.Time) bucket {
+	b := bucket{rate: rate, burst: float64(burst), last: now}
+	if b.burst <= 0 {
+		b.burst = math.Max(rate, 1)
+	}
+	b.tokens = b.burst
+	return b
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+	}
+	b.last = now
+}
+
+// take снимает n жетонов или возвращает время, через которое их будет
+// достаточно. Запрос больше burst пропускается при полной корзине и
+// уводит её в минус, так что следующие запросы подождут дольше.
+func (b *bucket) take(now time.Time, n float64) time.Duration {
+	if b.rate <= 0 {
+		return 0
+	}
+	b.refill(now)
+	need := math.Min(n, b.burst)
+	if b.tokens < need {
+		return time.Duration(math.Ceil((need - b.tokens) / b.rate * float64(time.Second)))
+	}
+	b.tokens -= n
+	return 0
+}
+
+// remaining возвращает число целых жетонов в корзине.
+func (b *bucket) remaining(now time.Time) int64 {
+	b.refill(now)
+	return int64(math.Max(0, math.Floor(b.tokens)))
+}
+
+// full сообщает, что за время простоя корзина успела бы наполниться.
+func (b *bucket) full(now time.Time) bool {
+	if b.rate <= 0 {
+		return true
+	}
+	return b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.burst
+}
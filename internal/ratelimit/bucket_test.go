@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketTake(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	type step struct {
+		after    time.Duration // от начала
+		n        float64
+		wantWait time.Duration
+	}
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		steps []step
+	}{
+		{
+			name: "burst then refill", rate: 2, burst: 3,
+			steps: []step{
+				{0, 1, 0}, {0, 1, 0}, {0, 1, 0},
+				{0, 1, 500 * time.Millisecond},
+				{500 * time.Millisecond, 1, 0},
+				{500 * time.Millisecond, 1, 500 * time.Millisecond},
+			},
+		},
+		{
+			name: "default burst equals rate", rate: 2,
+			steps: []step{{0, 1, 0}, {0, 1, 0}, {0, 1, 500 * time.Millisecond}},
+		},
+		{
+			name: "refill is capped by burst", rate: 1, burst: 2,
+			steps: []step{{0, 2, 0}, {time.Hour, 2, 0}, {time.Hour, 1, time.Second}},
+		},
+		{
+			name: "oversized request goes into debt", rate: 10, burst: 5,
+			steps: []step{
+				{0, 20, 0},
+				// После 20 жетонов при 5 в корзине долг 15, и одному
+				// жетону нужно 1.6 с.
+				{0, 1, 1600 * time.Millisecond},
+				{1600 * time.Millisecond, 1, 0},
+			},
+		},
+		{
+			name: "zero rate is unlimited", rate: 0,
+			steps: []step{{0, 1e9, 0}, {0, 1e9, 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBucket(tt.rate, tt.burst, start)
+			for i, s := range tt.steps {
+				if got := b.take(start.Add(s.after), s.n); got != s.wantWait {
+					t.Fatalf("step %d: take(%v) wait = %v, want %v", i, s.n, got, s.wantWait)
+				}
+			}
+		})
+	}
+}
+
+func TestBucketFull(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	b := newBucket(1, 10, start)
+	b.take(start, 10)
+	if b.full(start.Add(5 * time.Second)) {
+		t.Fatal("bucket is full after 5s")
+	}
+	if !b.full(start.Add(10 * time.Second)) {
+		t.Fatal("bucket is not full after 10s")
+	}
+}
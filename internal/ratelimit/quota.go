@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"strings"
+
+	"calculator/internal/storage"
+)
+
+// Ключи хранилища:
+//
+//	quota/<client>  — расход суточной квоты клиента
+const quotaPrefix = "quota/"
+
+type quotaRecord struct {
+	// Day — сутки по UTC в виде 2006-01-02.
+	Day  string `json:"day"`
+	Used int64  `json:"used"`
+}
+
+func quotaKey(client string) string {
+	return quotaPrefix + client
+}
+
+// loadQuotas читает расход за сутки day. Записи других суток и
+// нечитаемые записи возвращаются в stale для удаления.
+func loadQuotas(store storage.Store, day string) (used map[string]int64, stale map[string]bool, err error) {
+	records, err := store.List(quotaPrefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	used = make(map[string]int64)
+	stale = make(map[string]bool)
+	for key, payload := range records {
+		client := strings.TrimPrefix(key, quotaPrefix)
+		var rec quotaRecord
+		if json.Unmarshal(payload, &rec) != nil || rec.Day != day {
+			stale[client] = true
+			continue
+		}
+		used[client] = rec.Used
+	}
+	return used, stale, nil
+}
+
+// flush записывает изменившийся расход одним пакетом. При ошибке
+// изменения остаются отмеченными и записываются в следующий раз.
+func (l *Limiter) flush() error {
+	l.mu.Lock()
+	ops := make([]storage.Op, 0, len(l.dirty)+len(l.stale))
+	for client := range l.stale {
+		if _, ok := l.used[client]; !ok {
+			ops = append(ops, storage.Delete(quotaKey(client)))
+		}
+	}
+	for client := range l.dirty {
+		payload, err := json.Marshal(quotaRecord{Day: l.day, Used: l.used[client]})
+		if err != nil {
+			l.mu.Unlock()
+			return err
+		}
+		ops = append(ops, storage.Put(quotaKey(client), payload))
+	}
+	dirty, stale := l.dirty, l.stale
+	l.dirty, l.stale = make(map[string]bool), make(map[string]bool)
+	l.mu.Unlock()
+
+	if len(ops) == 0 {
+		return nil
+	}
+	if err := l.store.Apply(ops...); err != nil {
+		l.mu.Lock()
+		for client := range dirty {
+			l.dirty[client] = true
+		}
+		for client := range stale {
+			l.stale[client] = true
+		}
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+// Package ratelimit ограничивает частоту запросов и объём вычислений
+// каждого клиента: корзинами жетонов на запросы и инструкции в секунду
+// и суточной квотой инструкций, которая переживает перезапуск.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"calculator/internal/storage"
+)
+
+// ErrLimited — клиент превысил одно из ограничений.
+var ErrLimited = errors.New("rate limit exceeded")
+
+// Превышенные ограничения в LimitError.Limit.
+const (
+	LimitRequests          = "requests"
+	LimitInstructions      = "instructions"
+	LimitDailyInstructions = "daily_instructions"
+)
+
+// LimitError — отказ из-за ограничения. errors.Is(err, ErrLimited)
+// истинно для любого LimitError.
+type LimitError struct {
+	Limit string
+	// RetryAfter — через сколько повторный запрос может пройти.
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%v: %s, retry after %ds", ErrLimited, e.Limit, e.RetryAfterSeconds())
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimited
+}
+
+// RetryAfterSeconds возвращает RetryAfter в целых секундах с
+// округлением вверх, как его ждёт заголовок Retry-After.
+func (e *LimitError) RetryAfterSeconds() int {
+	return max(1, int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+// Config — ограничения одного клиента. Нулевое значение отключает
+// соответствующее ограничение.
+type Config struct {
+	RequestsPerSecond float64
+	// RequestBurst — запросы, которые можно сделать подряд; ноль —
+	// столько же, сколько в секунду.
+	RequestBurst          int
+	InstructionsPerSecond float64
+	InstructionBurst      int
+	// DailyInstructions — инструкций за сутки по UTC.
+	DailyInstructions int64
+}
+
+func (c Config) Enabled() bool {
+	return c.RequestsPerSecond > 0 || c.InstructionsPerSecond > 0 || c.DailyInstructions > 0
+}
+
+const (
+	// flushInterval — период записи израсходованных квот в хранилище.
+	flushInterval = 5 * time.Second
+	// idleTimeout — через сколько простоя забываются корзины клиента.
+	idleTimeout = 10 * time.Minute
+)
+
+type client struct {
+	requests     bucket
+	instructions bucket
+	lastSeen     time.Time
+}
+
+// Status — остаток ограничений клиента для заголовков ответа. Нулевой
+// предел означает, что ограничение выключено.
+type Status struct {
+	RequestLimit      int64
+	RequestsRemaining int64
+	QuotaLimit        int64
+	QuotaRemaining    int64
+	QuotaReset        time.Time
+}
+
+// Limiter хранит состояние ограничений всех клиентов. Клиент
+// определяется строковым ключом, см. WithClient.
+type Limiter struct {
+	cfg   Config
+	store storage.Store
+	now   func() time.Time
+
+	mu      sync.Mutex
+	clients map[string]*client
+	day     string
+	used    map[string]int64
+	// dirty — клиенты, чей расход ещё не записан; stale — записи
+	// прошлых суток, которые нужно удалить.
+	dirty map[string]bool
+	stale map[string]bool
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// New восстанавливает расход квот за текущие сутки из store и запускает
+// их периодическую запись.
+func New(cfg Config, store storage.Store) (*Limiter, error) {
+	l := &Limiter{
+		cfg:     cfg,
+		store:   store,
+		now:     time.Now,
+		clients: make(map[string]*client),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	l.day = dayOf(l.now())
+	used, stale, err := loadQuotas(store, l.day)
+	if err != nil {
+		return nil, fmt.Errorf("restore quotas: %w", err)
+	}
+	l.used, l.stale = used, stale
+	l.dirty = make(map[string]bool)
+	go l.loop()
+	return l, nil
+}
+
+// Close останавливает фоновую работу и записывает несохранённый расход.
+func (l *Limiter) Close() error {
+	l.once.Do(func() { close(l.stop) })
+	<-l.done
+	return l.flush()
+}
+
+// Allow учитывает запрос клиента key.
+func (l *Limiter) Allow(key string) error {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c := l.client(key, now)
+	if wait := c.requests.take(now, 1); wait > 0 {
+		return &LimitError{Limit: LimitRequests, RetryAfter: wait}
+	}
+	return nil
+}
+
+// Charge учитывает инструкции программы перед её выполнением. Клиент
+// берётся из контекста; без него вычисления не ограничиваются.
+func (l *Limiter) Charge(ctx context.Context, instructions int) error {
+	key, ok := ClientFromContext(ctx)
+	if !ok || instructions <= 0 {
+		return nil
+	}
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rollover(now)
+	n := int64(instructions)
+	if limit := l.cfg.DailyInstructions; limit > 0 && l.used[key]+n > limit {
+		return &LimitError{Limit: LimitDailyInstructions, RetryAfter: nextDay(now).Sub(now)}
+	}
+	c := l.client(key, now)
+	if wait := c.instructions.take(now, float64(n)); wait > 0 {
+		return &LimitError{Limit: LimitInstructions, RetryAfter: wait}
+	}
+	if l.cfg.DailyInstructions > 0 {
+		l.used[key] += n
+		l.dirty[key] = true
+	}
+	return nil
+}
+
+// Status возвращает остаток запросов и суточной квоты клиента.
+func (l *Limiter) Status(key string) Status {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var st Status
+	if l.cfg.RequestsPerSecond > 0 {
+		c := l.client(key, now)
+		st.RequestLimit = int64(c.requests.burst)
+		st.RequestsRemaining = c.requests.remaining(now)
+	}
+	if limit := l.cfg.DailyInstructions; limit > 0 {
+		l.rollover(now)
+		st.QuotaLimit = limit
+		st.QuotaRemaining = max(0, limit-l.used[key])
+		st.QuotaReset = nextDay(now)
+	}
+	return st
+}
+
+func (l *Limiter) client(key string, now time.Time) *client {
+	c, ok := l.clients[key]
+	if !ok {
+		c = &client{
+			requests:     newBucket(l.cfg.RequestsPerSecond, l.cfg.RequestBurst, now),
+			instructions: newBucket(l.cfg.InstructionsPerSecond, l.cfg.InstructionBurst, now),
+		}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	return c
+}
+
+// rollover начинает новые сутки: расход обнуляется, а записи прошлых
+// суток удаляются из хранилища при следующей записи.
+func (l *Limiter) rollover(now time.Time) {
+	day := dayOf(now)
+	if day == l.day {
+		return
+	}
+	for key := range l.used {
+		l.stale[key] = true
+	}
+	l.day = day
+	l.used = make(map[string]int64)
+	l.dirty = make(map[string]bool)
+}
+
+func (l *Limiter) loop() {
+	defer close(l.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if err := l.flush(); err != nil {
+				slog.Warn("failed to save quotas", "error", err)
+			}
+			l.evict()
+		}
+	}
+}
+
+// evict забывает корзины клиентов, которые долго не обращались и чьи
+// корзины за это время наполнились бы заново.
+func (l *Limiter) evict() {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, c := range l.clients {
+		if now.Sub(c.lastSeen) > idleTimeout && c.requests.full(now) && c.instructions.full(now) {
+			delete(l.clients, key)
+		}
+	}
+}
+
+func dayOf(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+// nextDay возвращает начало следующих суток по UTC.
+func nextDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+}
+
+type clientKey struct{}
+
+// WithClient добавляет в контекст ключ клиента, по которому Charge
+// учитывает инструкции.
+func WithClient(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, clientKey{}, key)
+}
+
+// ClientFromContext возвращает ключ клиента из контекста.
+func ClientFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(clientKey{}).(string)
+	return key, ok
+}
@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"calculator/internal/storage"
+)
+
+// clock — управляемое время для Limiter.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLimiter(t *testing.T, cfg Config, store storage.Store) (*Limiter, *clock) {
+	t.Helper()
+	l, err := New(cfg, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	c := &clock{t: time.Now()}
+	l.mu.Lock()
+	l.now = c.now
+	l.mu.Unlock()
+	return l, c
+}
+
+func TestAllow(t *testing.T) {
+	l, clk := newTestLimiter(t, Config{RequestsPerSecond: 1, RequestBurst: 2}, storage.NewMemoryStore())
+	for i := 0; i < 2; i++ {
+		if err := l.Allow("a"); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+	err := l.Allow("a")
+	var limited *LimitError
+	if !errors.As(err, &limited) || !errors.Is(err, ErrLimited) || limited.Limit != LimitRequests {
+		t.Fatalf("third request: got %v, want requests LimitError", err)
+	}
+	if limited.RetryAfterSeconds() != 1 {
+		t.Fatalf("RetryAfterSeconds = %d, want 1", limited.RetryAfterSeconds())
+	}
+	// Клиенты ограничиваются независимо.
+	if err := l.Allow("b"); err != nil {
+		t.Fatalf("other client: %v", err)
+	}
+	clk.advance(time.Second)
+	if err := l.Allow("a"); err != nil {
+		t.Fatalf("after refill: %v", err)
+	}
+	if st := l.Status("a"); st.RequestLimit != 2 || st.RequestsRemaining != 0 {
+		t.Fatalf("Status = %+v", st)
+	}
+}
+
+func TestChargeInstructions(t *testing.T) {
+	l, clk := newTestLimiter(t, Config{InstructionsPerSecond: 10, InstructionBurst: 10}, storage.NewMemoryStore())
+	ctx := WithClient(context.Background(), "a")
+	if err := l.Charge(ctx, 10); err != nil {
+		t.Fatal(err)
+	}
+	err := l.Charge(ctx, 5)
+	var limited *LimitError
+	if !errors.As(err, &limited) || limited.Limit != LimitInstructions || limited.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("got %v, want instructions LimitError with 500ms", err)
+	}
+	clk.advance(500 * time.Millisecond)
+	if err := l.Charge(ctx, 5); err != nil {
+		t.Fatal(err)
+	}
+	// Без клиента в контексте вычисления не ограничиваются.
+	if err := l.Charge(context.Background(), 1000); err != nil {
+		t.Fatalf("no client: %v", err)
+	}
+}
+
+func TestDailyQuota(t *testing.T) {
+	l, clk := newTestLimiter(t, Config{DailyInstructions: 100}, storage.NewMemoryStore())
+	clk.t = time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	ctx := WithClient(context.Background(), "a")
+
+	tests := []struct {
+		name      string
+		advance   time.Duration
+		n         int
+		wantErr   bool
+		remaining int64
+	}{
+		{name: "within quota", n: 60, remaining: 40},
+		{name: "exact remainder", n: 40, remaining: 0},
+		{name: "over quota", n: 1, wantErr: true, remaining: 0},
+		{name: "next day", advance: time.Minute, n: 30, remaining: 70},
+	}
+	for _, tt := range tests {
+		clk.advance(tt.advance)
+		err := l.Charge(ctx, tt.n)
+		var limited *LimitError
+		if tt.wantErr {
+			if !errors.As(err, &limited) || limited.Limit != LimitDailyInstructions || limited.RetryAfter != time.Minute {
+				t.Fatalf("%s: got %v, want daily LimitError retrying in 1m", tt.name, err)
+			}
+		} else if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		st := l.Status("a")
+		if st.QuotaLimit != 100 || st.QuotaRemaining != tt.remaining || !st.QuotaReset.Equal(nextDay(clk.t)) {
+			t.Fatalf("%s: Status = %+v, want %d remaining", tt.name, st, tt.remaining)
+		}
+	}
+}
+
+// Расход квоты переживает перезапуск, а записи прошлых суток удаляются.
+func TestQuotaPersistence(t *testing.T) {
+	store := storage.NewMemoryStore()
+	cfg := Config{DailyInstructions: 100}
+	l, err := New(cfg, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Charge(WithClient(context.Background(), "a"), 30); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	l, clk := newTestLimiter(t, cfg, store)
+	if st := l.Status("a"); st.QuotaRemaining != 70 {
+		t.Fatalf("after restart: remaining = %d, want 70", st.QuotaRemaining)
+	}
+
+	clk.advance(24 * time.Hour)
+	if st := l.Status("a"); st.QuotaRemaining != 100 {
+		t.Fatalf("next day: remaining = %d, want 100", st.QuotaRemaining)
+	}
+	if err := l.flush(); err != nil {
+		t.Fatal(err)
+	}
+	records, err := store.List(quotaPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("stale records were not removed: %v", records)
+	}
+}
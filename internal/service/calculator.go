@@ -1,19 +1,72 @@
 package service
 
 import (
+	"bytes"
 	"context"
-	"errors"
-	"fmt"
-	"sync"
+	"encoding/json"
+	"sync/atomic"
 	"time"
+
+	"calculator/internal/tracing"
 )
 
 type Instruction struct {
 	Type  string      `json:"type"`
 	Op    string      `json:"op,omitempty"`
 	Var   string      `json:"var"`
-	Left  interface{} `json:"left"`
+	Left  interface{} `json:"left,omitempty"`
 	Right interface{} `json:"right,omitempty"`
+
+	// Для инструкций input: значение по умолчанию и тип параметра.
+	Default   *int64 `json:"default,omitempty"`
+	ValueType string `json:"value_type,omitempty"`
+}
+
+// UnmarshalJSON разбирает инструкцию, сохраняя целые литералы операндов
+// как int64: при разборе в float64 числа больше 2^53 теряют точность.
+func (i *Instruction) UnmarshalJSON(data []byte) error {
+	type plain Instruction
+	var raw struct {
+		plain
+		Left  json.RawMessage `json:"left,omitempty"`
+		Right json.RawMessage `json:"right,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	left, err := decodeOperand(raw.Left)
+	if err != nil {
+		return err
+	}
+	right, err := decodeOperand(raw.Right)
+	if err != nil {
+		return err
+	}
+	*i = Instruction(raw.plain)
+	i.Left, i.Right = left, right
+	return nil
+}
+
+// decodeOperand возвращает целый литерал как int64. Нецелые числа
+// остаются float64 и отклоняются при проверке программы.
+func decodeOperand(data json.RawMessage) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var val interface{}
+	if err := dec.Decode(&val); err != nil {
+		return nil, err
+	}
+	num, ok := val.(json.Number)
+	if !ok {
+		return val, nil
+	}
+	if n, err := num.Int64(); err == nil {
+		return n, nil
+	}
+	return num.Float64()
 }
 
 type ResultItem struct {
@@ -21,121 +74,130 @@ type ResultItem struct {
 	Value int64  `json:"value"`
 }
 
+const (
+	defaultWorkers = 64
+	defaultLatency = 50 * time.Millisecond
+)
+
+// CalculatorService — долгоживущая часть калькулятора: настройки, реестр
+// операций и пул исполнителей. Состояние конкретного выполнения хранится
+// в Environment и между вызовами Run не разделяется.
 type CalculatorService struct {
-	results map[string]int64
-	mu      sync.Mutex
-	once    map[string]bool
+	operators map[string]Operator
+	pool      *workerPool
+	latency   time.Duration
+	metrics   Metrics
+	quota     Quota
+	limits    Limits
+	admission *admission
+	inFlight  atomic.Int64
+}
+
+type Option func(*CalculatorService)
+
+// WithWorkers ограничивает число одновременно вычисляемых переменных
+// во всех выполнениях сервиса.
+func WithWorkers(n int) Option {
+	return func(s *CalculatorService) {
+		if n > 0 {
+			s.pool = newWorkerPool(n)
+		}
+	}
 }
 
-func NewCalculatorService() *CalculatorService {
-	return &CalculatorService{
-		results: make(map[string]int64),
-		once:    make(map[string]bool),
+// WithLatency задаёт искусственную задержку вычисления одной переменной.
+func WithLatency(d time.Duration) Option {
+	return func(s *CalculatorService) {
+		if d >= 0 {
+			s.latency = d
+		}
 	}
 }
 
-func (s *CalculatorService) Run(ctx context.Context, instructions []Instruction) ([]ResultItem, error) {
-	var wg sync.WaitGroup
-	errChan := make(chan error, 1)
-	resultChan := make(chan ResultItem, len(instructions))
+// PoolUsage возвращает число переменных, вычисляемых сейчас, и размер
+// общего пула.
+func (s *CalculatorService) PoolUsage() (busy, size int) {
+	return s.pool.usage()
+}
+
+func NewCalculatorService(opts ...Option) *CalculatorService {
+	s := &CalculatorService{
+		operators: defaultOperators(),
+		pool:      newWorkerPool(defaultWorkers),
+		latency:   defaultLatency,
+		metrics:   noMetrics{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Run выполняет программу в новом окружении, поэтому параллельные и
+// последовательные вызовы не видят переменных друг друга.
+func (s *CalculatorService) Run(ctx context.Context, instructions []Instruction, inputs map[string]int64, opts ...RunOption) ([]ResultItem, error) {
+	return s.Execute(ctx, NewEnvironment(), instructions, inputs, opts...)
+}
+
+// Execute выполняет программу в переданном окружении. Переменные, уже
+// присвоенные в env, доступны программе, но не могут быть переприсвоены.
+// inputs связываются с инструкциями input программы, см. Bind.
+func (s *CalculatorService) Execute(ctx context.Context, env *Environment, instructions []Instruction, inputs map[string]int64, opts ...RunOption) ([]ResultItem, error) {
+	_, span := tracing.Start(ctx, "calculator.validate")
+	span.SetAttribute("instructions", len(instructions))
+	err := s.checkLimits(instructions)
+	var bound map[string]int64
+	if err == nil {
+		bound, err = Bind(instructions, inputs)
+	}
+	span.RecordError(err)
+	span.End()
+	if err == nil {
+		err = s.admission.admit(len(instructions))
+	}
+	if err == nil {
+		if err = s.charge(ctx, len(instructions)); err != nil {
+			s.admission.release(len(instructions))
+		}
+	}
+	if err != nil {
+		// Отклонённое выполнение учитывается в метриках так же, как
+		// прерванный поток.
+		s.metrics.ExecutionFinished(len(instructions), 0, err)
+		return nil, err
+	}
 
-	tasks := make(map[string]func() (int64, error))
+	exec := newExecution(ctx, s, env, opts)
+	exec.admitted, exec.reserved = true, len(instructions)
+	exec.inputs = bound
 	printVars := make([]string, 0)
 
-	// Разделяем calc и print
+	// Разделяем вычисляемые переменные и print
+	_, span = tracing.Start(ctx, "calculator.schedule")
 	for _, instr := range instructions {
-		if instr.Type == "calc" {
-			name := instr.Var
-			left := instr.Left
-			right := instr.Right
-			op := instr.Op
-
-			leftCopy := left
-			rightCopy := right
-
-			tasks[name] = func() (int64, error) {
-				time.Sleep(50 * time.Millisecond)
-
-				lVal, err := s.resolveValue(tasks, leftCopy)
-				if err != nil {
-					return 0, err
-				}
-
-				var rVal int64
-				if rightCopy != nil {
-					rVal, err = s.resolveValue(tasks, rightCopy)
-					if err != nil {
-						return 0, err
-					}
-				}
-
-				var res int64
-				switch op {
-				case "+":
-					res = lVal + rVal
-				case "-":
-					res = lVal - rVal
-				case "*":
-					res = lVal * rVal
-				default:
-					return 0, fmt.Errorf("unsupported operation: %s", op)
-				}
-
-				s.mu.Lock()
-				if s.once[name] {
-					s.mu.Unlock()
-					return 0, fmt.Errorf("variable %s already assigned", name)
-				}
-				s.results[name] = res
-				s.once[name] = true
-				s.mu.Unlock()
-
-				return res, nil
+		if instr.Type == "calc" || instr.Type == "input" {
+			if err := exec.schedule(instr); err != nil {
+				span.RecordError(err)
+				exec.fail(err)
+				break
 			}
 		} else if instr.Type == "print" {
 			printVars = append(printVars, instr.Var)
 		}
 	}
+	exec.seal()
+	span.End()
 
-	// Запуск задач параллельно
-	for name, task := range tasks {
-		wg.Add(1)
-		go func(n string, t func() (int64, error)) {
-			val, err := t()
-			if err != nil {
-				select {
-				case errChan <- err:
-				default:
-				}
-			} else {
-				resultChan <- ResultItem{Var: n, Value: val}
-			}
-			wg.Done()
-		}(name, task)
-	}
-
-	// Ждём завершения
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
-
-	// Собираем результаты
-	output := make([]ResultItem, 0)
-	for item := range resultChan {
-		output = append(output, item)
-	}
-
-	select {
-	case err := <-errChan:
+	err = exec.wait()
+	exec.finish(len(instructions), err)
+	if err != nil {
 		return nil, err
-	default:
 	}
 
 	// Фильтруем по printVars
 	finalOutput := make([]ResultItem, 0)
 	for _, varName := range printVars {
-		val, ok := s.results[varName]
+		val, ok := env.Get(varName)
 		if !ok {
 			continue
 		}
@@ -145,22 +207,13 @@ func (s *CalculatorService) Run(ctx context.Context, instructions []Instruction)
 	return finalOutput, nil
 }
 
-func (s *CalculatorService) resolveValue(tasks map[string]func() (int64, error), val interface{}) (int64, error) {
-	switch v := val.(type) {
-	case float64:
-		if v != float64(int64(v)) {
-			return 0, fmt.Errorf("expected integer value, got %v", v)
-		}
-		return int64(v), nil
-	case int64:
-		return v, nil
-	case string:
-		task, exists := tasks[v]
-		if !exists {
-			return 0, errors.New("undefined variable: " + v)
+// Dependencies возвращает имена переменных, на которые ссылается инструкция.
+func (instr Instruction) Dependencies() []string {
+	deps := make([]string, 0, 2)
+	for _, operand := range []interface{}{instr.Left, instr.Right} {
+		if name, ok := operand.(string); ok {
+			deps = append(deps, name)
 		}
-		return task()
-	default:
-		return 0, errors.New("invalid value type 8")
 	}
+	return deps
 }
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+)
+
+func calc(name, op string, left, right interface{}) Instruction {
+	return Instruction{Type: "calc", Op: op, Var: name, Left: left, Right: right}
+}
+
+func printVar(name string) Instruction {
+	return Instruction{Type: "print", Var: name}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		program []Instruction
+		want    []ResultItem
+		wantErr error
+	}{
+		{
+			name: "arithmetic",
+			program: []Instruction{
+				calc("x", "+", int64(2), int64(3)),
+				calc("y", "*", "x", int64(4)),
+				calc("z", "-", "y", "x"),
+				printVar("z"),
+				printVar("x"),
+			},
+			want: []ResultItem{{Var: "z", Value: 15}, {Var: "x", Value: 5}},
+		},
+		{
+			name: "dependency declared later",
+			program: []Instruction{
+				calc("y", "+", "x", int64(1)),
+				calc("x", "+", int64(1), int64(1)),
+				printVar("y"),
+			},
+			want: []ResultItem{{Var: "y", Value: 3}},
+		},
+		{
+			name:    "undefined variable",
+			program: []Instruction{calc("y", "+", "x", int64(1)), printVar("y")},
+			wantErr: ErrUndefinedVariable,
+		},
+		{
+			name:    "reassignment",
+			program: []Instruction{calc("x", "+", int64(1), int64(1)), calc("x", "+", int64(2), int64(2))},
+			wantErr: ErrAlreadyAssigned,
+		},
+	}
+	s := NewCalculatorService(WithLatency(0))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Run(context.Background(), tt.program, nil)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Повторные и параллельные вызовы одного сервиса не видят переменных
+// друг друга.
+func TestRunIsolation(t *testing.T) {
+	s := NewCalculatorService(WithLatency(0))
+	var wg sync.WaitGroup
+	for i := int64(0); i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := s.Run(context.Background(), []Instruction{
+				calc("x", "+", i, int64(0)),
+				calc("y", "*", "x", int64(10)),
+				printVar("y"),
+			}, nil)
+			if err != nil {
+				t.Errorf("run %d: %v", i, err)
+				return
+			}
+			if len(got) != 1 || got[0].Value != 10*i {
+				t.Errorf("run %d: got %v", i, got)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestExecuteSharesEnvironment(t *testing.T) {
+	s := NewCalculatorService(WithLatency(0))
+	env := NewEnvironment()
+	if _, err := s.Execute(context.Background(), env, []Instruction{calc("x", "+", int64(1), int64(2))}, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Execute(context.Background(), env, []Instruction{calc("y", "*", "x", int64(2)), printVar("y")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Value != 6 {
+		t.Fatalf("got %v, want y=6", got)
+	}
+	if _, err := s.Execute(context.Background(), env, []Instruction{calc("x", "+", int64(0), int64(0))}, nil); !errors.Is(err, ErrAlreadyAssigned) {
+		t.Fatalf("reassigning x: got %v, want ErrAlreadyAssigned", err)
+	}
+}
+
+func TestInstructionUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     string
+		left, right interface{}
+	}{
+		{name: "small integers", payload: `{"type":"calc","op":"+","var":"x","left":1,"right":-2}`, left: int64(1), right: int64(-2)},
+		{name: "above 2^53", payload: `{"type":"calc","op":"+","var":"x","left":9007199254740993,"right":0}`, left: int64(9007199254740993), right: int64(0)},
+		{name: "int64 bounds", payload: `{"type":"calc","op":"+","var":"x","left":9223372036854775807,"right":-9223372036854775808}`, left: int64(math.MaxInt64), right: int64(math.MinInt64)},
+		{name: "variables", payload: `{"type":"calc","op":"*","var":"y","left":"x","right":"x"}`, left: "x", right: "x"},
+		{name: "fraction", payload: `{"type":"calc","op":"+","var":"x","left":1.5,"right":1}`, left: 1.5, right: int64(1)},
+		{name: "print", payload: `{"type":"print","var":"x"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var instr Instruction
+			if err := json.Unmarshal([]byte(tt.payload), &instr); err != nil {
+				t.Fatal(err)
+			}
+			if instr.Left != tt.left || instr.Right != tt.right {
+				t.Fatalf("operands = %#v, %#v, want %#v, %#v", instr.Left, instr.Right, tt.left, tt.right)
+			}
+		})
+	}
+
+	// Разбор и обратная сериализация не меняют инструкцию.
+	var instr Instruction
+	payload := `{"type":"input","var":"n","default":5,"value_type":"int"}`
+	if err := json.Unmarshal([]byte(payload), &instr); err != nil {
+		t.Fatal(err)
+	}
+	if out, _ := json.Marshal(instr); string(out) != payload {
+		t.Fatalf("round trip = %s, want %s", out, payload)
+	}
+}
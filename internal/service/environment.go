@@ -0,0 +1,79 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Environment хранит значения переменных одного выполнения программы
+// (или одной сессии, если окружение переиспользуется между вызовами).
+type Environment struct {
+	mu      sync.Mutex
+	results map[string]int64
+	once    map[string]bool
+}
+
+func NewEnvironment() *Environment {
+	return &Environment{
+		results: make(map[string]int64),
+		once:    make(map[string]bool),
+	}
+}
+
+// NewEnvironmentFrom создаёт окружение с уже присвоенными переменными,
+// например восстановленными из хранилища.
+func NewEnvironmentFrom(values map[string]int64) *Environment {
+	env := NewEnvironment()
+	for name, val := range values {
+		env.results[name] = val
+		env.once[name] = true
+	}
+	return env
+}
+
+func (e *Environment) Get(name string) (int64, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	val, ok := e.results[name]
+	return val, ok
+}
+
+// Values возвращает копию всех присвоенных переменных.
+func (e *Environment) Values() map[string]int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	values := make(map[string]int64, len(e.results))
+	for name, val := range e.results {
+		values[name] = val
+	}
+	return values
+}
+
+func (e *Environment) assign(name string, val int64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.once[name] {
+		return fmt.Errorf("variable %s %w", name, ErrAlreadyAssigned)
+	}
+	e.results[name] = val
+	e.once[name] = true
+	return nil
+}
+
+// Clone возвращает независимую копию окружения.
+func (e *Environment) Clone() *Environment {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	clone := NewEnvironment()
+	for name, val := range e.results {
+		clone.results[name] = val
+		clone.once[name] = e.once[name]
+	}
+	return clone
+}
+
+func (e *Environment) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.results)
+}
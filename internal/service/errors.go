@@ -0,0 +1,31 @@
+package service
+
+import "errors"
+
+var (
+	ErrUndefinedVariable = errors.New("undefined variable")
+	ErrAlreadyAssigned   = errors.New("already assigned")
+)
+
+// EvalError — ошибка вычисления конкретной переменной. Текст ошибки не
+// меняется, имя переменной доступно через errors.As.
+type EvalError struct {
+	Var string
+	Err error
+}
+
+func (e *EvalError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *EvalError) Unwrap() error {
+	return e.Err
+}
+
+func wrapEval(name string, err error) error {
+	var evalErr *EvalError
+	if err == nil || errors.As(err, &evalErr) {
+		return err
+	}
+	return &EvalError{Var: name, Err: err}
+}
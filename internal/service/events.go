@@ -0,0 +1,42 @@
+package service
+
+import "time"
+
+type EventType string
+
+const (
+	EventScheduled EventType = "scheduled"
+	EventStarted   EventType = "started"
+	EventCompleted EventType = "completed"
+	EventFailed    EventType = "failed"
+)
+
+// Event — изменение состояния переменной во время выполнения.
+type Event struct {
+	Type  EventType `json:"type"`
+	Var   string    `json:"var"`
+	Value int64     `json:"value,omitempty"`
+	Error string    `json:"error,omitempty"`
+	Time  time.Time `json:"time"`
+}
+
+type RunOption func(*execution)
+
+// WithEvents передаёт события выполнения в fn. fn вызывается из разных
+// горутин и должен быть безопасен для конкурентного использования.
+func WithEvents(fn func(Event)) RunOption {
+	return func(e *execution) {
+		e.observe = fn
+	}
+}
+
+func (e *execution) notify(typ EventType, name string, val int64, err error) {
+	if e.observe == nil {
+		return
+	}
+	ev := Event{Type: typ, Var: name, Value: val, Time: time.Now()}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	e.observe(ev)
+}
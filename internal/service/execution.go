@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"calculator/internal/tracing"
+)
+
+// execution — состояние одного вызова Execute: узлы программы и их
+// результаты. Каждая переменная вычисляется ровно один раз, зависимые
+// переменные ждут её результата, а не пересчитывают его.
+type execution struct {
+	svc     *CalculatorService
+	env     *Environment
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	observe func(Event)
+	started time.Time
+	// stopTimer освобождает таймер ограничения времени выполнения.
+	stopTimer context.CancelFunc
+	// admitted — выполнение принято контролем допуска и занимает в нём
+	// reserved инструкций.
+	admitted bool
+	reserved int
+
+	mu     sync.Mutex
+	nodes  map[string]*node
+	inputs map[string]int64
+	sealed bool
+
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+// node — переменная программы. Узел без instr создаётся, когда на
+// переменную ссылаются раньше, чем она объявлена.
+type node struct {
+	name  string
+	instr *Instruction
+	done  chan struct{}
+	value int64
+	err   error
+}
+
+func newExecution(ctx context.Context, svc *CalculatorService, env *Environment, opts []RunOption) *execution {
+	stopTimer := context.CancelFunc(func() {})
+	if d := svc.limits.MaxEvaluationTime; d > 0 {
+		ctx, stopTimer = context.WithTimeoutCause(ctx, d, &LimitExceededError{Limit: LimitEvaluationTime, Max: d.Milliseconds()})
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	e := &execution{
+		svc:       svc,
+		env:       env,
+		ctx:       ctx,
+		cancel:    cancel,
+		stopTimer: stopTimer,
+		nodes:     make(map[string]*node),
+		started:   time.Now(),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	svc.inFlight.Add(1)
+	return e
+}
+
+// schedule объявляет переменную и запускает её вычисление.
+func (e *execution) schedule(instr Instruction) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	name := instr.Var
+	if _, ok := e.env.Get(name); ok {
+		return fmt.Errorf("variable %s %w", name, ErrAlreadyAssigned)
+	}
+	n, ok := e.nodes[name]
+	if ok && n.instr != nil {
+		return fmt.Errorf("variable %s %w", name, ErrAlreadyAssigned)
+	}
+	if !ok {
+		n = &node{name: name, done: make(chan struct{})}
+		e.nodes[name] = n
+	}
+	n.instr = &instr
+
+	if path := e.findCycle(name, name, nil); path != nil {
+		n.instr = nil
+		return fmt.Errorf("cyclic dependency: %s", strings.Join(path, " -> "))
+	}
+
+	e.notify(EventScheduled, name, 0, nil)
+	e.wg.Add(1)
+	go e.run(n)
+	return nil
+}
+
+// seal сообщает, что новых объявлений не будет: ссылки на так и не
+// объявленные переменные завершаются ошибкой.
+func (e *execution) seal() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sealed = true
+	for name, n := range e.nodes {
+		if n.instr == nil {
+			n.err = fmt.Errorf("%w: %s", ErrUndefinedVariable, name)
+			close(n.done)
+		}
+	}
+}
+
+func (e *execution) wait() error {
+	e.wg.Wait()
+	e.cancel(nil)
+	return e.err
+}
+
+// finish вызывается один раз после wait и передаёт итог в метрики и
+// журнал.
+func (e *execution) finish(instructions int, err error) {
+	elapsed := time.Since(e.started)
+	e.stopTimer()
+	if e.admitted {
+		e.svc.admission.release(e.reserved)
+	}
+	e.svc.inFlight.Add(-1)
+	e.svc.metrics.ExecutionFinished(instructions, elapsed, err)
+	if err != nil {
+		slog.DebugContext(e.ctx, "execution failed", "instructions", instructions, "duration", elapsed, "error", err)
+	} else {
+		slog.DebugContext(e.ctx, "execution finished", "instructions", instructions, "duration", elapsed)
+	}
+}
+
+func (e *execution) fail(err error) {
+	e.errOnce.Do(func() {
+		e.err = err
+		e.cancel(err)
+	})
+}
+
+func (e *execution) run(n *node) {
+	defer e.wg.Done()
+	_, span := tracing.Start(e.ctx, "calculator.evaluate")
+	span.SetAttribute("var", n.name)
+	if n.instr.Type == "input" {
+		span.SetAttribute("op", "input")
+	} else {
+		span.SetAttribute("op", n.instr.Op)
+	}
+	span.SetAttribute("dependencies", len(n.instr.Dependencies()))
+	n.value, n.err = e.evaluate(n)
+	n.err = wrapEval(n.name, n.err)
+	span.RecordError(n.err)
+	span.End()
+	if n.err != nil {
+		e.fail(n.err)
+		e.notify(EventFailed, n.name, 0, n.err)
+	} else {
+		e.notify(EventCompleted, n.name, n.value, nil)
+	}
+	close(n.done)
+}
+
+func (e *execution) evaluate(n *node) (int64, error) {
+	instr := n.instr
+	if instr.Type == "input" {
+		e.notify(EventStarted, n.name, 0, nil)
+		e.mu.Lock()
+		val := e.inputs[n.name]
+		e.mu.Unlock()
+		if err := e.svc.limits.checkValue(n.name, val); err != nil {
+			return 0, err
+		}
+		if err := e.env.assign(n.name, val); err != nil {
+			return 0, err
+		}
+		return val, nil
+	}
+
+	lVal, err := e.resolve(instr.Left)
+	if err != nil {
+		return 0, err
+	}
+
+	var rVal int64
+	if instr.Right != nil {
+		rVal, err = e.resolve(instr.Right)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	op, ok := e.svc.operators[instr.Op]
+	if !ok {
+		return 0, fmt.Errorf("unsupported operation: %s", instr.Op)
+	}
+
+	if err := e.svc.pool.acquire(e.ctx); err != nil {
+		return 0, err
+	}
+	defer e.svc.pool.release()
+	e.notify(EventStarted, n.name, 0, nil)
+
+	if err := sleepContext(e.ctx, e.svc.latency); err != nil {
+		return 0, err
+	}
+
+	res, err := op(lVal, rVal)
+	e.svc.metrics.OperatorEvaluated(instr.Op, err)
+	if err != nil {
+		return 0, err
+	}
+	if err := e.svc.limits.checkValue(n.name, res); err != nil {
+		return 0, err
+	}
+	if err := e.env.assign(n.name, res); err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+// resolve возвращает значение операнда: литерала, переменной окружения
+// или переменной, вычисляемой в этом же выполнении.
+func (e *execution) resolve(val interface{}) (int64, error) {
+	switch v := val.(type) {
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, fmt.Errorf("expected integer value, got %v", v)
+		}
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case string:
+		start := time.Now()
+		val, err := e.await(v)
+		e.svc.metrics.DependencyWaited(time.Since(start))
+		return val, err
+	default:
+		return 0, fmt.Errorf("invalid value type %T", val)
+	}
+}
+
+// await ждёт значения переменной: из окружения или из этого выполнения.
+func (e *execution) await(name string) (int64, error) {
+	n, err := e.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	if n == nil {
+		val, _ := e.env.Get(name)
+		return val, nil
+	}
+	select {
+	case <-n.done:
+		return n.value, n.err
+	case <-e.ctx.Done():
+		return 0, context.Cause(e.ctx)
+	}
+}
+
+// lookup находит узел переменной. nil без ошибки означает, что значение
+// уже есть в окружении.
+func (e *execution) lookup(name string) (*node, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if n, ok := e.nodes[name]; ok {
+		return n, nil
+	}
+	if _, ok := e.env.Get(name); ok {
+		return nil, nil
+	}
+	if e.sealed {
+		return nil, fmt.Errorf("%w: %s", ErrUndefinedVariable, name)
+	}
+	n := &node{name: name, done: make(chan struct{})}
+	e.nodes[name] = n
+	return n, nil
+}
+
+// findCycle ищет путь по объявленным зависимостям от from обратно к target.
+func (e *execution) findCycle(target, from string, visited map[string]bool) []string {
+	if visited == nil {
+		visited = make(map[string]bool)
+	}
+	visited[from] = true
+	n, ok := e.nodes[from]
+	if !ok || n.instr == nil {
+		return nil
+	}
+	for _, dep := range n.instr.Dependencies() {
+		if dep == target {
+			return []string{from, dep}
+		}
+		if visited[dep] {
+			continue
+		}
+		if path := e.findCycle(target, dep, visited); path != nil {
+			return append([]string{from}, path...)
+		}
+	}
+	return nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
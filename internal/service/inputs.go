@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidInputs = errors.New("invalid inputs")
+
+// InputError перечисляет все проблемы с входными параметрами вызова.
+type InputError struct {
+	Missing []string
+	Extra   []string
+	Invalid []string
+}
+
+func (e *InputError) Error() string {
+	parts := make([]string, 0, 3)
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Extra) > 0 {
+		parts = append(parts, "unexpected: "+strings.Join(e.Extra, ", "))
+	}
+	if len(e.Invalid) > 0 {
+		parts = append(parts, "invalid: "+strings.Join(e.Invalid, ", "))
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidInputs, strings.Join(parts, "; "))
+}
+
+func (e *InputError) Is(target error) bool {
+	return target == ErrInvalidInputs
+}
+
+// Bind сопоставляет объявленные в программе входы (инструкции input) с
+// переданными значениями. Для непереданных входов берётся значение по
+// умолчанию. Возвращает значения всех объявленных входов.
+func Bind(instructions []Instruction, inputs map[string]int64) (map[string]int64, error) {
+	bound := make(map[string]int64)
+	declared := make(map[string]bool)
+	inputErr := &InputError{}
+
+	for _, instr := range instructions {
+		if instr.Type != "input" {
+			continue
+		}
+		declared[instr.Var] = true
+		if instr.ValueType != "" && instr.ValueType != "int" {
+			inputErr.Invalid = append(inputErr.Invalid, fmt.Sprintf("%s (unsupported type %q)", instr.Var, instr.ValueType))
+			continue
+		}
+		if val, ok := inputs[instr.Var]; ok {
+			bound[instr.Var] = val
+		} else if instr.Default != nil {
+			bound[instr.Var] = *instr.Default
+		} else {
+			inputErr.Missing = append(inputErr.Missing, instr.Var)
+		}
+	}
+	for name := range inputs {
+		if !declared[name] {
+			inputErr.Extra = append(inputErr.Extra, name)
+		}
+	}
+
+	if len(inputErr.Missing) > 0 || len(inputErr.Extra) > 0 || len(inputErr.Invalid) > 0 {
+		sort.Strings(inputErr.Missing)
+		sort.Strings(inputErr.Extra)
+		return nil, inputErr
+	}
+	return bound, nil
+}
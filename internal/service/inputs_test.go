@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func input(name string, def *int64) Instruction {
+	return Instruction{Type: "input", Var: name, Default: def}
+}
+
+func TestBind(t *testing.T) {
+	five := int64(5)
+	tests := []struct {
+		name    string
+		program []Instruction
+		inputs  map[string]int64
+		want    map[string]int64
+		wantErr *InputError
+	}{
+		{
+			name:    "supplied and default",
+			program: []Instruction{input("a", nil), input("b", &five)},
+			inputs:  map[string]int64{"a": 1},
+			want:    map[string]int64{"a": 1, "b": 5},
+		},
+		{
+			name:    "default overridden",
+			program: []Instruction{input("b", &five)},
+			inputs:  map[string]int64{"b": 7},
+			want:    map[string]int64{"b": 7},
+		},
+		{
+			name:    "missing and extra",
+			program: []Instruction{input("b", nil), input("a", nil)},
+			inputs:  map[string]int64{"z": 1, "y": 2},
+			wantErr: &InputError{Missing: []string{"a", "b"}, Extra: []string{"y", "z"}},
+		},
+		{
+			name:    "unsupported type",
+			program: []Instruction{{Type: "input", Var: "a", ValueType: "string"}},
+			inputs:  map[string]int64{"a": 1},
+			wantErr: &InputError{Invalid: []string{`a (unsupported type "string")`}},
+		},
+		{
+			name:    "no inputs declared",
+			program: []Instruction{calc("x", "+", int64(1), int64(2))},
+			want:    map[string]int64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Bind(tt.program, tt.inputs)
+			if tt.wantErr != nil {
+				var inputErr *InputError
+				if !errors.As(err, &inputErr) || !errors.Is(err, ErrInvalidInputs) {
+					t.Fatalf("got error %v, want InputError", err)
+				}
+				if fmt.Sprint(inputErr) != fmt.Sprint(tt.wantErr) {
+					t.Fatalf("got %v, want %v", inputErr, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunWithInputs(t *testing.T) {
+	s := NewCalculatorService(WithLatency(0))
+	program := []Instruction{
+		input("price", nil),
+		calc("total", "*", "price", int64(3)),
+		printVar("total"),
+	}
+	for _, price := range []int64{1, 10, -4} {
+		got, err := s.Run(context.Background(), program, map[string]int64{"price": price})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].Value != 3*price {
+			t.Fatalf("price %d: got %v", price, got)
+		}
+	}
+	if _, err := s.Run(context.Background(), program, nil); !errors.Is(err, ErrInvalidInputs) {
+		t.Fatalf("missing input: got %v, want ErrInvalidInputs", err)
+	}
+}
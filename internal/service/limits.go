@@ -0,0 +1,263 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrLimitExceeded — программа превышает ограничение размера или
+	// сложности.
+	ErrLimitExceeded = errors.New("limit exceeded")
+	// ErrOverloaded — сервер уже выполняет столько работы, сколько
+	// допускает контроль допуска.
+	ErrOverloaded = errors.New("server overloaded")
+)
+
+// Имена ограничений в LimitExceededError.Limit; совпадают с ключами
+// конфигурации limits.
+const (
+	LimitInstructions   = "max_instructions"
+	LimitNameLength     = "max_name_length"
+	LimitDepth          = "max_depth"
+	LimitFanIn          = "max_fan_in"
+	LimitValueBits      = "max_value_bits"
+	LimitEvaluationTime = "max_evaluation_time"
+)
+
+// LimitExceededError — программа превысила ограничение Limit. Для
+// max_evaluation_time Max задан в миллисекундах, а Value не заполняется.
+type LimitExceededError struct {
+	Limit string
+	Max   int64
+	Value int64
+	// Var — переменная, на которой обнаружено превышение, если есть.
+	Var string
+}
+
+func (e *LimitExceededError) Error() string {
+	if e.Limit == LimitEvaluationTime {
+		return fmt.Sprintf("%v: evaluation took longer than %s", ErrLimitExceeded, time.Duration(e.Max)*time.Millisecond)
+	}
+	msg := fmt.Sprintf("%v: %s is %d, got %d", ErrLimitExceeded, e.Limit, e.Max, e.Value)
+	if e.Var != "" {
+		msg += " (variable " + e.Var + ")"
+	}
+	return msg
+}
+
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// Limits — ограничения одной программы и всей работы сервиса. Ноль
+// отключает ограничение.
+type Limits struct {
+	// MaxInstructions — инструкций в одной программе.
+	MaxInstructions int
+	// MaxNameLength — длина имени переменной в байтах.
+	MaxNameLength int
+	// MaxDepth — длина самой длинной цепочки зависимостей.
+	MaxDepth int
+	// MaxFanIn — сколько инструкций может ссылаться на одну переменную.
+	MaxFanIn int
+	// MaxValueBits — разрядность модуля литералов, входов и результатов;
+	// не больше 64.
+	MaxValueBits int
+	// MaxEvaluationTime — время выполнения одной программы.
+	MaxEvaluationTime time.Duration
+
+	// MaxInFlightExecutions и MaxInFlightInstructions — сколько программ
+	// и инструкций сервис выполняет одновременно; сверх этого новые
+	// выполнения отклоняются с ErrOverloaded.
+	MaxInFlightExecutions   int
+	MaxInFlightInstructions int
+}
+
+// WithLimits задаёт ограничения программ и контроль допуска.
+func WithLimits(l Limits) Option {
+	return func(s *CalculatorService) {
+		s.limits = l
+		s.admission = &admission{maxExecutions: l.MaxInFlightExecutions, maxInstructions: l.MaxInFlightInstructions}
+	}
+}
+
+// checkLimits проверяет размер и форму программы до выполнения.
+func (s *CalculatorService) checkLimits(instructions []Instruction) error {
+	l := s.limits
+	if l.MaxInstructions > 0 && len(instructions) > l.MaxInstructions {
+		return &LimitExceededError{Limit: LimitInstructions, Max: int64(l.MaxInstructions), Value: int64(len(instructions))}
+	}
+	refs := make(map[string]int)
+	defs := make(map[string]Instruction, len(instructions))
+	for _, instr := range instructions {
+		if err := l.checkName(instr.Var); err != nil {
+			return err
+		}
+		if err := l.checkLiterals(instr); err != nil {
+			return err
+		}
+		for _, dep := range instr.Dependencies() {
+			if err := l.checkName(dep); err != nil {
+				return err
+			}
+			refs[dep]++
+			if l.MaxFanIn > 0 && refs[dep] > l.MaxFanIn {
+				return &LimitExceededError{Limit: LimitFanIn, Max: int64(l.MaxFanIn), Value: int64(refs[dep]), Var: dep}
+			}
+		}
+		if instr.Type == "calc" || instr.Type == "input" {
+			defs[instr.Var] = instr
+		}
+	}
+	if l.MaxDepth > 0 {
+		depths := make(map[string]int, len(defs))
+		for name := range defs {
+			if d := depthOf(name, defs, depths); d > l.MaxDepth {
+				return &LimitExceededError{Limit: LimitDepth, Max: int64(l.MaxDepth), Value: int64(d), Var: name}
+			}
+		}
+	}
+	return nil
+}
+
+func (l Limits) checkName(name string) error {
+	if l.MaxNameLength > 0 && len(name) > l.MaxNameLength {
+		return &LimitExceededError{Limit: LimitNameLength, Max: int64(l.MaxNameLength), Value: int64(len(name)), Var: name[:l.MaxNameLength] + "..."}
+	}
+	return nil
+}
+
+// checkLiterals проверяет разрядность литералов инструкции и значения
+// входа по умолчанию.
+func (l Limits) checkLiterals(instr Instruction) error {
+	if instr.Default != nil {
+		if err := l.checkValue(instr.Var, *instr.Default); err != nil {
+			return err
+		}
+	}
+	for _, operand := range []interface{}{instr.Left, instr.Right} {
+		var v int64
+		switch o := operand.(type) {
+		case int64:
+			v = o
+		case int:
+			v = int64(o)
+		case float64:
+			v = int64(o)
+		default:
+			continue
+		}
+		if err := l.checkValue(instr.Var, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkValue проверяет разрядность значения переменной name.
+func (l Limits) checkValue(name string, v int64) error {
+	if l.MaxValueBits <= 0 {
+		return nil
+	}
+	if n := valueBits(v); n > l.MaxValueBits {
+		return &LimitExceededError{Limit: LimitValueBits, Max: int64(l.MaxValueBits), Value: int64(n), Var: name}
+	}
+	return nil
+}
+
+// valueBits возвращает число значащих битов модуля v.
+func valueBits(v int64) int {
+	u := uint64(v)
+	if v < 0 {
+		u = -u
+	}
+	return bits.Len64(u)
+}
+
+// depthOf возвращает длину самой длинной цепочки зависимостей,
+// заканчивающейся переменной name. Переменные вне defs имеют глубину
+// ноль; циклы не учитываются, их отклоняет проверка программы.
+func depthOf(name string, defs map[string]Instruction, depths map[string]int) int {
+	if d, ok := depths[name]; ok {
+		return d
+	}
+	instr, ok := defs[name]
+	if !ok {
+		return 0
+	}
+	// Отметка на время обхода обрывает циклы.
+	depths[name] = 0
+	d := 0
+	for _, dep := range instr.Dependencies() {
+		d = max(d, depthOf(dep, defs, depths))
+	}
+	depths[name] = d + 1
+	return d + 1
+}
+
+// admission — контроль допуска: общий для сервиса предел одновременно
+// выполняемых программ и инструкций в них.
+type admission struct {
+	maxExecutions   int
+	maxInstructions int
+
+	mu           sync.Mutex
+	executions   int
+	instructions int
+}
+
+// admit принимает новое выполнение с instructions инструкциями.
+func (a *admission) admit(instructions int) error {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.maxExecutions > 0 && a.executions >= a.maxExecutions {
+		return fmt.Errorf("%w: %d executions in flight", ErrOverloaded, a.executions)
+	}
+	if err := a.checkInstructions(instructions); err != nil {
+		return err
+	}
+	a.executions++
+	a.instructions += instructions
+	return nil
+}
+
+// grow добавляет инструкции уже принятому потоковому выполнению.
+func (a *admission) grow(instructions int) error {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.checkInstructions(instructions); err != nil {
+		return err
+	}
+	a.instructions += instructions
+	return nil
+}
+
+func (a *admission) checkInstructions(n int) error {
+	// Программа больше всего предела принимается, когда сервис свободен,
+	// иначе она не выполнилась бы никогда.
+	if a.maxInstructions > 0 && a.instructions > 0 && a.instructions+n > a.maxInstructions {
+		return fmt.Errorf("%w: %d instructions in flight", ErrOverloaded, a.instructions)
+	}
+	return nil
+}
+
+// release освобождает место завершившегося выполнения.
+func (a *admission) release(instructions int) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.executions--
+	a.instructions -= instructions
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckLimits(t *testing.T) {
+	chain := func(n int) []Instruction {
+		program := []Instruction{calc("v0", "+", int64(1), int64(0))}
+		for i := 1; i < n; i++ {
+			program = append(program, calc(fmt.Sprintf("v%d", i), "+", program[i-1].Var, int64(1)))
+		}
+		return program
+	}
+	tests := []struct {
+		name      string
+		limits    Limits
+		program   []Instruction
+		wantLimit string
+		wantVar   string
+	}{
+		{
+			name:      "instructions",
+			limits:    Limits{MaxInstructions: 2},
+			program:   []Instruction{calc("a", "+", int64(1), int64(1)), calc("b", "+", int64(1), int64(1)), printVar("a")},
+			wantLimit: LimitInstructions,
+		},
+		{
+			name:      "name length",
+			limits:    Limits{MaxNameLength: 4},
+			program:   []Instruction{calc("toolong", "+", int64(1), int64(1))},
+			wantLimit: LimitNameLength,
+			wantVar:   "tool...",
+		},
+		{
+			name:      "depth",
+			limits:    Limits{MaxDepth: 3},
+			program:   chain(4),
+			wantLimit: LimitDepth,
+		},
+		{
+			name:    "depth at limit",
+			limits:  Limits{MaxDepth: 4},
+			program: chain(4),
+		},
+		{
+			name:   "fan-in",
+			limits: Limits{MaxFanIn: 2},
+			program: []Instruction{
+				calc("x", "+", int64(1), int64(1)),
+				calc("a", "+", "x", int64(1)),
+				calc("b", "+", "x", "x"),
+			},
+			wantLimit: LimitFanIn,
+			wantVar:   "x",
+		},
+		{
+			name:      "literal bits",
+			limits:    Limits{MaxValueBits: 8},
+			program:   []Instruction{calc("x", "+", int64(255), int64(256))},
+			wantLimit: LimitValueBits,
+			wantVar:   "x",
+		},
+		{
+			name:      "json literal bits",
+			limits:    Limits{MaxValueBits: 8},
+			program:   []Instruction{calc("x", "+", float64(-300), int64(1))},
+			wantLimit: LimitValueBits,
+		},
+		{
+			name:      "default input bits",
+			limits:    Limits{MaxValueBits: 8},
+			program:   []Instruction{{Type: "input", Var: "n", Default: ptr(int64(1000))}},
+			wantLimit: LimitValueBits,
+			wantVar:   "n",
+		},
+		{
+			name:    "literal bits at limit",
+			limits:  Limits{MaxValueBits: 8},
+			program: []Instruction{calc("x", "+", int64(255), int64(-255))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewCalculatorService(WithLatency(0), WithLimits(tt.limits))
+			err := s.Validate(tt.program)
+			if tt.wantLimit == "" {
+				if err != nil {
+					t.Fatalf("Validate = %v", err)
+				}
+				return
+			}
+			var limitErr *LimitExceededError
+			if !errors.As(err, &limitErr) || !errors.Is(err, ErrLimitExceeded) {
+				t.Fatalf("Validate = %v, want LimitExceededError", err)
+			}
+			if limitErr.Limit != tt.wantLimit || (tt.wantVar != "" && limitErr.Var != tt.wantVar) {
+				t.Fatalf("got %+v, want limit %s on %q", limitErr, tt.wantLimit, tt.wantVar)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestValueBits(t *testing.T) {
+	tests := []struct {
+		v    int64
+		want int
+	}{
+		{0, 0},
+		{1, 1},
+		{-1, 1},
+		{255, 8},
+		{256, 9},
+		{-256, 9},
+		{math.MaxInt64, 63},
+		{math.MinInt64, 64},
+	}
+	for _, tt := range tests {
+		if got := valueBits(tt.v); got != tt.want {
+			t.Errorf("valueBits(%d) = %d, want %d", tt.v, got, tt.want)
+		}
+	}
+}
+
+// Разрядность проверяется и у значений, известных только при
+// выполнении: входов и результатов операций.
+func TestValueBitsAtRuntime(t *testing.T) {
+	s := NewCalculatorService(WithLatency(0), WithLimits(Limits{MaxValueBits: 16}))
+	program := []Instruction{
+		{Type: "input", Var: "n"},
+		calc("sq", "*", "n", "n"),
+		printVar("sq"),
+	}
+	if _, err := s.Run(context.Background(), program, map[string]int64{"n": 255}); err != nil {
+		t.Fatalf("n = 255: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		n       int64
+		wantVar string
+	}{
+		{name: "result", n: 256, wantVar: "sq"},
+		{name: "input", n: 1 << 20, wantVar: "n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Run(context.Background(), program, map[string]int64{"n": tt.n})
+			var limitErr *LimitExceededError
+			if !errors.As(err, &limitErr) || limitErr.Limit != LimitValueBits || limitErr.Var != tt.wantVar {
+				t.Fatalf("got %v, want %s limit on %s", err, LimitValueBits, tt.wantVar)
+			}
+		})
+	}
+}
+
+func TestEvaluationTimeLimit(t *testing.T) {
+	s := NewCalculatorService(WithLatency(time.Second), WithLimits(Limits{MaxEvaluationTime: 10 * time.Millisecond}))
+	_, err := s.Run(context.Background(), []Instruction{calc("x", "+", int64(1), int64(1)), printVar("x")}, nil)
+	var limitErr *LimitExceededError
+	if !errors.As(err, &limitErr) || limitErr.Limit != LimitEvaluationTime {
+		t.Fatalf("got %v, want %s limit", err, LimitEvaluationTime)
+	}
+	if !strings.Contains(err.Error(), "10ms") {
+		t.Fatalf("error %q does not mention the limit", err)
+	}
+}
+
+func TestAdmission(t *testing.T) {
+	s := NewCalculatorService(WithLatency(200*time.Millisecond), WithLimits(Limits{MaxInFlightExecutions: 1}))
+	program := []Instruction{calc("x", "+", int64(1), int64(1)), printVar("x")}
+
+	done := make(chan error)
+	go func() {
+		_, err := s.Run(context.Background(), program, nil)
+		done <- err
+	}()
+	// Ждём, пока первое выполнение займёт место.
+	deadline := time.Now().Add(time.Second)
+	for s.inFlight.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := s.Run(context.Background(), program, nil); !errors.Is(err, ErrOverloaded) {
+		t.Fatalf("second execution: got %v, want ErrOverloaded", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	// Место освобождается по завершении.
+	if _, err := s.Run(context.Background(), program, nil); err != nil {
+		t.Fatalf("after release: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package service
+
+import "time"
+
+// Metrics получает сведения о выполнении программ. Методы вызываются
+// из разных горутин одновременно.
+type Metrics interface {
+	// ExecutionFinished вызывается по завершении Execute или Stream.
+	ExecutionFinished(instructions int, elapsed time.Duration, err error)
+	// OperatorEvaluated вызывается после применения операции.
+	OperatorEvaluated(op string, err error)
+	// DependencyWaited сообщает, сколько вычисление переменной ждало
+	// значения операнда-переменной.
+	DependencyWaited(d time.Duration)
+}
+
+type noMetrics struct{}
+
+func (noMetrics) ExecutionFinished(int, time.Duration, error) {}
+func (noMetrics) OperatorEvaluated(string, error)             {}
+func (noMetrics) DependencyWaited(time.Duration)              {}
+
+// WithMetrics подключает сбор метрик выполнения.
+func WithMetrics(m Metrics) Option {
+	return func(s *CalculatorService) {
+		if m != nil {
+			s.metrics = m
+		}
+	}
+}
+
+// InFlight возвращает число выполняющихся сейчас программ.
+func (s *CalculatorService) InFlight() int {
+	return int(s.inFlight.Load())
+}
+
+// PoolQueue возвращает число переменных, ожидающих свободного места в
+// общем пуле.
+func (s *CalculatorService) PoolQueue() int {
+	return s.pool.queued()
+}
@@ -0,0 +1,12 @@
+package service
+
+// Operator вычисляет значение бинарной операции.
+type Operator func(left, right int64) (int64, error)
+
+func defaultOperators() map[string]Operator {
+	return map[string]Operator{
+		"+": func(l, r int64) (int64, error) { return l + r, nil },
+		"-": func(l, r int64) (int64, error) { return l - r, nil },
+		"*": func(l, r int64) (int64, error) { return l * r, nil },
+	}
+}
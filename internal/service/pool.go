@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// workerPool ограничивает число переменных, вычисляемых одновременно
+// во всех выполнениях сервиса.
+type workerPool struct {
+	slots   chan struct{}
+	waiting atomic.Int64
+}
+
+func newWorkerPool(size int) *workerPool {
+	return &workerPool{slots: make(chan struct{}, size)}
+}
+
+func (p *workerPool) acquire(ctx context.Context) error {
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	p.waiting.Add(1)
+	defer p.waiting.Add(-1)
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+func (p *workerPool) release() {
+	<-p.slots
+}
+
+// usage возвращает число занятых слотов и размер пула.
+func (p *workerPool) usage() (int, int) {
+	return len(p.slots), cap(p.slots)
+}
+
+// queued возвращает число ожидающих свободного слота.
+func (p *workerPool) queued() int {
+	return int(p.waiting.Load())
+}
@@ -0,0 +1,26 @@
+package service
+
+import "context"
+
+// Quota учитывает инструкции, которые клиент отправляет на выполнение.
+// Charge вызывается до вычисления; ошибка отклоняет выполнение.
+type Quota interface {
+	Charge(ctx context.Context, instructions int) error
+}
+
+// WithQuota подключает учёт инструкций по клиентам.
+func WithQuota(q Quota) Option {
+	return func(s *CalculatorService) {
+		if q != nil {
+			s.quota = q
+		}
+	}
+}
+
+// charge списывает инструкции с квоты клиента, если учёт подключён.
+func (s *CalculatorService) charge(ctx context.Context, instructions int) error {
+	if s.quota == nil {
+		return nil
+	}
+	return s.quota.Charge(ctx, instructions)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Stream — выполнение программы, инструкции которой поступают по одной.
+// Переменная начинает вычисляться сразу после объявления, как только
+// готовы её зависимости; значения print передаются в emit по мере
+// готовности, а не в порядке инструкций.
+type Stream struct {
+	exec   *execution
+	emit   func(ResultItem)
+	emitMu sync.Mutex
+
+	mu           sync.Mutex
+	inputs       map[string]int64
+	declared     map[string]bool
+	instructions int
+	printed      int
+	unresolved   []string
+	closed       bool
+	// rejected — отказ контроля допуска; выполнение не начинается.
+	rejected error
+	// refs и depths — число ссылок на переменные и глубина объявленных
+	// переменных для проверки ограничений.
+	refs   map[string]int
+	depths map[string]int
+
+	prints sync.WaitGroup
+}
+
+// StreamSummary — итог потокового выполнения.
+type StreamSummary struct {
+	Instructions int
+	Printed      int
+	// Unresolved — переменные из print, которые так и не были объявлены.
+	Unresolved []string
+	// Inputs — значения входов, связанных с инструкциями input.
+	Inputs map[string]int64
+}
+
+// NewStream начинает потоковое выполнение в окружении env. inputs —
+// значения входов, которые будут объявлены инструкциями input. Вызовы
+// emit не пересекаются между собой.
+func (s *CalculatorService) NewStream(ctx context.Context, env *Environment, inputs map[string]int64, emit func(ResultItem), opts ...RunOption) *Stream {
+	exec := newExecution(ctx, s, env, opts)
+	exec.inputs = make(map[string]int64)
+	st := &Stream{
+		exec:     exec,
+		emit:     emit,
+		inputs:   inputs,
+		declared: make(map[string]bool),
+		refs:     make(map[string]int),
+		depths:   make(map[string]int),
+	}
+	if err := s.admission.admit(0); err != nil {
+		st.rejected = err
+		exec.fail(err)
+	} else {
+		exec.admitted = true
+	}
+	return st
+}
+
+// Add объявляет очередную инструкцию. Ошибка означает, что выполнение
+// прервано; её же вернёт Close.
+func (st *Stream) Add(instr Instruction) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.closed {
+		return errors.New("stream already closed")
+	}
+	if st.rejected != nil {
+		return st.rejected
+	}
+	st.instructions++
+
+	// Инструкции потока проверяются и списываются с квоты по одной по
+	// мере поступления.
+	err := st.checkLimits(instr)
+	if err == nil {
+		err = st.exec.svc.admission.grow(1)
+	}
+	if err == nil {
+		st.exec.reserved++
+		err = st.exec.svc.charge(st.exec.ctx, 1)
+	}
+	if err != nil {
+		st.exec.fail(err)
+		return err
+	}
+	switch instr.Type {
+	case "calc":
+		err = st.exec.schedule(instr)
+	case "input":
+		err = st.declareInput(instr)
+	case "print":
+		st.prints.Add(1)
+		go st.print(instr.Var)
+	default:
+		err = fmt.Errorf("%w: unsupported instruction type: %s", ErrInvalidProgram, instr.Type)
+	}
+	if err != nil {
+		st.exec.fail(wrapEval(instr.Var, err))
+		return err
+	}
+	return nil
+}
+
+// checkLimits проверяет ограничения программы для очередной инструкции.
+// Глубина считается по зависимостям, объявленным раньше инструкции.
+func (st *Stream) checkLimits(instr Instruction) error {
+	l := st.exec.svc.limits
+	if l.MaxInstructions > 0 && st.instructions > l.MaxInstructions {
+		return &LimitExceededError{Limit: LimitInstructions, Max: int64(l.MaxInstructions), Value: int64(st.instructions)}
+	}
+	if err := l.checkName(instr.Var); err != nil {
+		return err
+	}
+	if err := l.checkLiterals(instr); err != nil {
+		return err
+	}
+	depth := 0
+	for _, dep := range instr.Dependencies() {
+		if err := l.checkName(dep); err != nil {
+			return err
+		}
+		st.refs[dep]++
+		if l.MaxFanIn > 0 && st.refs[dep] > l.MaxFanIn {
+			return &LimitExceededError{Limit: LimitFanIn, Max: int64(l.MaxFanIn), Value: int64(st.refs[dep]), Var: dep}
+		}
+		depth = max(depth, st.depths[dep])
+	}
+	if instr.Type == "calc" || instr.Type == "input" {
+		depth++
+		if l.MaxDepth > 0 && depth > l.MaxDepth {
+			return &LimitExceededError{Limit: LimitDepth, Max: int64(l.MaxDepth), Value: int64(depth), Var: instr.Var}
+		}
+		st.depths[instr.Var] = depth
+	}
+	return nil
+}
+
+// Failed закрывается, когда выполнение прервано ошибкой или отменой.
+func (st *Stream) Failed() <-chan struct{} {
+	return st.exec.ctx.Done()
+}
+
+// Close сообщает об окончании ввода и ждёт завершения всех вычислений.
+func (st *Stream) Close() (StreamSummary, error) {
+	st.mu.Lock()
+	st.closed = true
+	st.mu.Unlock()
+
+	st.exec.seal()
+	err := st.exec.wait()
+	st.prints.Wait()
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	defer func() { st.exec.finish(st.instructions, err) }()
+	if err == nil {
+		extra := make([]string, 0)
+		for name := range st.inputs {
+			if !st.declared[name] {
+				extra = append(extra, name)
+			}
+		}
+		if len(extra) > 0 {
+			sort.Strings(extra)
+			err = &InputError{Extra: extra}
+		}
+	}
+
+	sort.Strings(st.unresolved)
+	summary := StreamSummary{
+		Instructions: st.instructions,
+		Printed:      st.printed,
+		Unresolved:   st.unresolved,
+		Inputs:       st.exec.inputs,
+	}
+	return summary, err
+}
+
+func (st *Stream) declareInput(instr Instruction) error {
+	if instr.ValueType != "" && instr.ValueType != "int" {
+		return &InputError{Invalid: []string{fmt.Sprintf("%s (unsupported type %q)", instr.Var, instr.ValueType)}}
+	}
+	if st.declared[instr.Var] {
+		return fmt.Errorf("variable %s %w", instr.Var, ErrAlreadyAssigned)
+	}
+	val, ok := st.inputs[instr.Var]
+	if !ok {
+		if instr.Default == nil {
+			return &InputError{Missing: []string{instr.Var}}
+		}
+		val = *instr.Default
+	}
+	st.declared[instr.Var] = true
+
+	st.exec.mu.Lock()
+	st.exec.inputs[instr.Var] = val
+	st.exec.mu.Unlock()
+	return st.exec.schedule(instr)
+}
+
+func (st *Stream) print(name string) {
+	defer st.prints.Done()
+	val, err := st.exec.await(name)
+	if err != nil {
+		// Необъявленная переменная не прерывает выполнение, как и в Run.
+		var evalErr *EvalError
+		if errors.Is(err, ErrUndefinedVariable) && !errors.As(err, &evalErr) {
+			st.mu.Lock()
+			st.unresolved = append(st.unresolved, name)
+			st.mu.Unlock()
+		}
+		return
+	}
+
+	st.mu.Lock()
+	st.printed++
+	st.mu.Unlock()
+
+	st.emitMu.Lock()
+	defer st.emitMu.Unlock()
+	st.emit(ResultItem{Var: name, Value: val})
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidProgram = errors.New("invalid program")
+
+// Validate проверяет программу без её выполнения: ограничения размера,
+// типы инструкций, операции, операнды, повторные присвоения, ссылки на
+// необъявленные переменные и циклы.
+func (s *CalculatorService) Validate(instructions []Instruction) error {
+	if err := s.checkLimits(instructions); err != nil {
+		return err
+	}
+	defs := make(map[string]Instruction)
+	for i, instr := range instructions {
+		if instr.Var == "" {
+			return fmt.Errorf("%w: instruction %d: empty variable name", ErrInvalidProgram, i)
+		}
+		switch instr.Type {
+		case "print":
+			continue
+		case "input":
+		case "calc":
+			if _, ok := s.operators[instr.Op]; !ok {
+				return fmt.Errorf("%w: instruction %d: unsupported operation: %s", ErrInvalidProgram, i, instr.Op)
+			}
+			if instr.Left == nil {
+				return fmt.Errorf("%w: instruction %d: missing left operand", ErrInvalidProgram, i)
+			}
+			for _, operand := range []interface{}{instr.Left, instr.Right} {
+				if err := validateOperand(operand); err != nil {
+					return fmt.Errorf("%w: instruction %d: %v", ErrInvalidProgram, i, err)
+				}
+			}
+		default:
+			return fmt.Errorf("%w: instruction %d: unsupported instruction type: %s", ErrInvalidProgram, i, instr.Type)
+		}
+		if _, ok := defs[instr.Var]; ok {
+			return fmt.Errorf("%w: variable %s already assigned", ErrInvalidProgram, instr.Var)
+		}
+		defs[instr.Var] = instr
+	}
+
+	for _, instr := range defs {
+		for _, dep := range instr.Dependencies() {
+			if _, ok := defs[dep]; !ok {
+				return fmt.Errorf("%w: undefined variable: %s", ErrInvalidProgram, dep)
+			}
+		}
+	}
+	if cycle := findCycle(defs); cycle != nil {
+		return fmt.Errorf("%w: cyclic dependency: %s", ErrInvalidProgram, strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+func validateOperand(val interface{}) error {
+	switch v := val.(type) {
+	case nil, int64, int, string:
+		return nil
+	case float64:
+		if v != float64(int64(v)) {
+			return fmt.Errorf("expected integer value, got %v", v)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid value type %T", val)
+	}
+}
+
+// findCycle возвращает путь первого найденного цикла зависимостей.
+func findCycle(defs map[string]Instruction) []string {
+	const (
+		unvisited = iota
+		inProgress
+		finished
+	)
+	state := make(map[string]int, len(defs))
+	var path []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		state[name] = inProgress
+		path = append(path, name)
+		for _, dep := range defs[name].Dependencies() {
+			switch state[dep] {
+			case inProgress:
+				for i, p := range path {
+					if p == dep {
+						return append(append([]string{}, path[i:]...), dep)
+					}
+				}
+			case unvisited:
+				if _, ok := defs[dep]; !ok {
+					continue
+				}
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = finished
+		return nil
+	}
+	for name := range defs {
+		if state[name] == unvisited {
+			if cycle := visit(name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
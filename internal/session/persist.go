@@ -0,0 +1,152 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"calculator/internal/service"
+	"calculator/internal/storage"
+)
+
+// Ключи хранилища:
+//
+//	session/<id>             — метаданные сессии
+//	session-var/<id>/<name>  — значение переменной сессии
+//	session-def/<id>/<name>  — инструкция, которой переменная вычислена
+const (
+	sessionPrefix    = "session/"
+	variablePrefix   = "session-var/"
+	definitionPrefix = "session-def/"
+)
+
+type sessionRecord struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func sessionKey(id string) string {
+	return sessionPrefix + id
+}
+
+func variableKey(id, name string) string {
+	return variablePrefix + id + "/" + name
+}
+
+func putSession(id string, createdAt time.Time) (storage.Op, error) {
+	payload, err := json.Marshal(sessionRecord{CreatedAt: createdAt})
+	if err != nil {
+		return storage.Op{}, err
+	}
+	return storage.Put(sessionKey(id), payload), nil
+}
+
+func putVariable(id, name string, val int64) (storage.Op, error) {
+	payload, err := json.Marshal(val)
+	if err != nil {
+		return storage.Op{}, err
+	}
+	return storage.Put(variableKey(id, name), payload), nil
+}
+
+func definitionKey(id, name string) string {
+	return definitionPrefix + id + "/" + name
+}
+
+func putDefinition(id string, instr service.Instruction) (storage.Op, error) {
+	payload, err := json.Marshal(instr)
+	if err != nil {
+		return storage.Op{}, err
+	}
+	return storage.Put(definitionKey(id, instr.Var), payload), nil
+}
+
+// deleteOps возвращает изменения, удаляющие сессию со всеми переменными.
+func deleteOps(store storage.Store, id string) ([]storage.Op, error) {
+	ops := []storage.Op{storage.Delete(sessionKey(id))}
+	for _, prefix := range []string{variablePrefix, definitionPrefix} {
+		keys, err := store.List(prefix + id + "/")
+		if err != nil {
+			return nil, err
+		}
+		for key := range keys {
+			ops = append(ops, storage.Delete(key))
+		}
+	}
+	return ops, nil
+}
+
+type restoredSession struct {
+	record sessionRecord
+	values map[string]int64
+	defs   map[string]service.Instruction
+}
+
+// loadSessions восстанавливает сессии, их переменные и определения
+// переменных из хранилища.
+func loadSessions(store storage.Store) (map[string]*restoredSession, error) {
+	metas, err := store.List(sessionPrefix)
+	if err != nil {
+		return nil, err
+	}
+	restored := make(map[string]*restoredSession, len(metas))
+	for key, payload := range metas {
+		rs := &restoredSession{
+			values: make(map[string]int64),
+			defs:   make(map[string]service.Instruction),
+		}
+		if err := json.Unmarshal(payload, &rs.record); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", key, err)
+		}
+		restored[strings.TrimPrefix(key, sessionPrefix)] = rs
+	}
+
+	stored, err := store.List(variablePrefix)
+	if err != nil {
+		return nil, err
+	}
+	for key, payload := range stored {
+		rs, name, err := lookupRestored(restored, key, variablePrefix)
+		if err != nil {
+			return nil, err
+		}
+		if rs == nil {
+			continue
+		}
+		var val int64
+		if err := json.Unmarshal(payload, &val); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", key, err)
+		}
+		rs.values[name] = val
+	}
+
+	stored, err = store.List(definitionPrefix)
+	if err != nil {
+		return nil, err
+	}
+	for key, payload := range stored {
+		rs, name, err := lookupRestored(restored, key, definitionPrefix)
+		if err != nil {
+			return nil, err
+		}
+		if rs == nil {
+			continue
+		}
+		var instr service.Instruction
+		if err := json.Unmarshal(payload, &instr); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", key, err)
+		}
+		rs.defs[name] = instr
+	}
+	return restored, nil
+}
+
+// lookupRestored разбирает ключ вида <prefix><id>/<name>. Записи сессий
+// без метаданных игнорируются.
+func lookupRestored(restored map[string]*restoredSession, key, prefix string) (*restoredSession, string, error) {
+	id, name, ok := strings.Cut(strings.TrimPrefix(key, prefix), "/")
+	if !ok {
+		return nil, "", fmt.Errorf("malformed key %s", key)
+	}
+	return restored[id], name, nil
+}
@@ -0,0 +1,344 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"calculator/internal/service"
+	"calculator/internal/storage"
+)
+
+var (
+	ErrNotFound         = errors.New("session not found")
+	ErrTooManyVariables = errors.New("too many variables in session")
+)
+
+type Config struct {
+	// TTL — время жизни сессии с момента последнего обращения.
+	TTL time.Duration
+	// MaxVariables — максимальное число переменных в окружении сессии.
+	MaxVariables int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		TTL:          30 * time.Minute,
+		MaxVariables: 1000,
+	}
+}
+
+// Info — описание сессии для клиентов.
+type Info struct {
+	ID            string    `json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	VariableCount int       `json:"variable_count"`
+}
+
+// Session хранит окружение переменных между запросами. Вызовы в рамках
+// одной сессии выполняются строго по очереди.
+type Session struct {
+	id        string
+	createdAt time.Time
+
+	mu  sync.Mutex
+	env *service.Environment
+	// defs — инструкции, которыми вычислены переменные сессии; по ним
+	// строится граф зависимостей для пересчёта.
+	defs map[string]service.Instruction
+	// deleted — сессия удалена или истекла. Вызов, дождавшийся mu после
+	// удаления, ничего не записывает в хранилище.
+	deleted bool
+
+	// lastUsed защищён мьютексом менеджера.
+	lastUsed time.Time
+}
+
+type Manager struct {
+	calc  *service.CalculatorService
+	store storage.Store
+	cfg   Config
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewManager создаёт менеджер и восстанавливает сессии, сохранённые в
+// store. Срок жизни восстановленных сессий отсчитывается заново.
+func NewManager(calc *service.CalculatorService, store storage.Store, cfg Config) (*Manager, error) {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultConfig().TTL
+	}
+	m := &Manager{
+		calc:     calc,
+		store:    store,
+		cfg:      cfg,
+		sessions: make(map[string]*Session),
+		stop:     make(chan struct{}),
+	}
+
+	restored, err := loadSessions(store)
+	if err != nil {
+		return nil, fmt.Errorf("restore sessions: %w", err)
+	}
+	now := time.Now()
+	for id, rs := range restored {
+		m.sessions[id] = &Session{
+			id:        id,
+			createdAt: rs.record.CreatedAt,
+			env:       service.NewEnvironmentFrom(rs.values),
+			defs:      rs.defs,
+			lastUsed:  now,
+		}
+	}
+
+	go m.sweep()
+	return m, nil
+}
+
+// Close останавливает фоновую очистку просроченных сессий.
+func (m *Manager) Close() {
+	m.once.Do(func() { close(m.stop) })
+}
+
+func (m *Manager) Create() (Info, error) {
+	id, err := newID()
+	if err != nil {
+		return Info{}, err
+	}
+	now := time.Now()
+	s := &Session{
+		id:        id,
+		createdAt: now,
+		env:       service.NewEnvironment(),
+		defs:      make(map[string]service.Instruction),
+		lastUsed:  now,
+	}
+
+	op, err := putSession(id, now)
+	if err != nil {
+		return Info{}, err
+	}
+	if err := m.store.Apply(op); err != nil {
+		return Info{}, err
+	}
+
+	m.mu.Lock()
+	m.sessions[id] = s
+	m.mu.Unlock()
+
+	return m.info(s, 0), nil
+}
+
+func (m *Manager) Get(id string) (Info, error) {
+	s, err := m.acquire(id)
+	if err != nil {
+		return Info{}, err
+	}
+	s.mu.Lock()
+	count := s.env.Len()
+	s.mu.Unlock()
+	return m.info(s, count), nil
+}
+
+func (m *Manager) Delete(id string) error {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	delete(m.sessions, id)
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return m.forget(s)
+}
+
+// Variables возвращает переменные сессии, отсортированные по имени.
+func (m *Manager) Variables(id string) ([]service.ResultItem, error) {
+	s, err := m.acquire(id)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	values := s.env.Values()
+	s.mu.Unlock()
+
+	items := make([]service.ResultItem, 0, len(values))
+	for name, val := range values {
+		items = append(items, service.ResultItem{Var: name, Value: val})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Var < items[j].Var })
+	return items, nil
+}
+
+// Calculate выполняет программу в окружении сессии. Присвоения
+// применяются, только если вся программа выполнилась без ошибок.
+func (m *Manager) Calculate(ctx context.Context, id string, instructions []service.Instruction, inputs map[string]int64) ([]service.ResultItem, error) {
+	s, err := m.acquire(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	env := s.env.Clone()
+	results, err := m.calc.Execute(ctx, env, instructions, inputs)
+	if err != nil {
+		return nil, err
+	}
+	if m.cfg.MaxVariables > 0 && env.Len() > m.cfg.MaxVariables {
+		return nil, fmt.Errorf("%w: %d > %d", ErrTooManyVariables, env.Len(), m.cfg.MaxVariables)
+	}
+
+	// Новые присвоения сначала записываются в хранилище и только потом
+	// становятся видимы в сессии.
+	ops := make([]storage.Op, 0)
+	defs := make([]service.Instruction, 0)
+	for _, instr := range instructions {
+		switch instr.Type {
+		case "calc":
+			defs = append(defs, instr)
+		case "input":
+			val, _ := env.Get(instr.Var)
+			defs = append(defs, inputDefinition(instr.Var, val))
+		}
+	}
+	for name, val := range env.Values() {
+		if _, ok := s.env.Get(name); ok {
+			continue
+		}
+		op, err := putVariable(s.id, name, val)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+	for _, instr := range defs {
+		op, err := putDefinition(s.id, instr)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+	if err := m.store.Apply(ops...); err != nil {
+		return nil, fmt.Errorf("persist session %s: %w", s.id, err)
+	}
+	s.env = env
+	for _, instr := range defs {
+		s.defs[instr.Var] = instr
+	}
+	return results, nil
+}
+
+// acquire находит живую сессию и продлевает её срок жизни.
+func (m *Manager) acquire(id string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	now := time.Now()
+	if now.Sub(s.lastUsed) > m.cfg.TTL {
+		delete(m.sessions, id)
+		go m.expire(s)
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	s.lastUsed = now
+	return s, nil
+}
+
+func (m *Manager) info(s *Session, count int) Info {
+	m.mu.Lock()
+	lastUsed := s.lastUsed
+	m.mu.Unlock()
+	return Info{
+		ID:            s.id,
+		CreatedAt:     s.createdAt,
+		ExpiresAt:     lastUsed.Add(m.cfg.TTL),
+		VariableCount: count,
+	}
+}
+
+// Период проверки истёкших сессий — половина TTL в этих пределах.
+const (
+	minSweepInterval = 10 * time.Millisecond
+	maxSweepInterval = time.Minute
+)
+
+func (m *Manager) sweep() {
+	interval := min(max(m.cfg.TTL/2, minSweepInterval), maxSweepInterval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case now := <-ticker.C:
+			expired := make([]*Session, 0)
+			m.mu.Lock()
+			for id, s := range m.sessions {
+				if now.Sub(s.lastUsed) > m.cfg.TTL {
+					delete(m.sessions, id)
+					expired = append(expired, s)
+				}
+			}
+			m.mu.Unlock()
+			for _, s := range expired {
+				m.expire(s)
+			}
+		}
+	}
+}
+
+// lock захватывает сессию для изменения. Если сессию удалили, пока вызов
+// ждал своей очереди, возвращает ErrNotFound.
+func (s *Session) lock() error {
+	s.mu.Lock()
+	if s.deleted {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrNotFound, s.id)
+	}
+	return nil
+}
+
+// forget удаляет сессию, уже убранную из менеджера, из хранилища.
+// Выполняющийся в ней вызов дорабатывает до конца, а следующие получают
+// ErrNotFound, поэтому записанные им переменные тоже удаляются.
+func (m *Manager) forget(s *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted = true
+	ops, err := deleteOps(m.store, s.id)
+	if err != nil {
+		return err
+	}
+	return m.store.Apply(ops...)
+}
+
+// expire удаляет истекшую сессию; ошибку некому вернуть, она пишется в
+// журнал.
+func (m *Manager) expire(s *Session) {
+	if err := m.forget(s); err != nil {
+		slog.Error("failed to delete expired session", "session_id", s.id, "error", err)
+	}
+}
+
+func newID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
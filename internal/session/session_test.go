@@ -0,0 +1,150 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"calculator/internal/service"
+	"calculator/internal/storage"
+)
+
+func newTestManager(t *testing.T, store storage.Store) *Manager {
+	t.Helper()
+	m, err := NewManager(service.NewCalculatorService(service.WithLatency(0)), store, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(m.Close)
+	return m
+}
+
+func calc(name string, left, right interface{}) service.Instruction {
+	return service.Instruction{Type: "calc", Op: "+", Var: name, Left: left, Right: right}
+}
+
+func storedKeys(t *testing.T, store storage.Store, id string) []string {
+	t.Helper()
+	keys := make([]string, 0)
+	for _, prefix := range []string{sessionPrefix, variablePrefix, definitionPrefix} {
+		found, err := store.List(prefix + id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for key := range found {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func TestDeleteRemovesStoredSession(t *testing.T) {
+	store := storage.NewMemoryStore()
+	m := newTestManager(t, store)
+	info, err := m.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Calculate(context.Background(), info.ID, []service.Instruction{calc("x", 1, 2)}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Delete(info.ID); err != nil {
+		t.Fatal(err)
+	}
+	if keys := storedKeys(t, store, info.ID); len(keys) != 0 {
+		t.Fatalf("keys left after delete: %v", keys)
+	}
+	if _, err := m.Calculate(context.Background(), info.ID, []service.Instruction{calc("y", 1, 2)}, nil); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("calculate after delete: got %v, want ErrNotFound", err)
+	}
+	if err := m.Delete(info.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("second delete: got %v, want ErrNotFound", err)
+	}
+}
+
+// Вызов, ожидающий очереди сессии во время удаления, не должен оставить
+// её переменные в хранилище при любом порядке захвата блокировки.
+func TestDeleteDuringCalculate(t *testing.T) {
+	store := storage.NewMemoryStore()
+	m := newTestManager(t, store)
+	info, err := m.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.mu.Lock()
+	s := m.sessions[info.ID]
+	m.mu.Unlock()
+
+	s.mu.Lock()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, err := m.Calculate(context.Background(), info.ID, []service.Instruction{calc("x", 1, 2)}, nil)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			t.Errorf("calculate: %v", err)
+		}
+	}()
+	// Calculate успевает найти сессию и ждёт её блокировку.
+	time.Sleep(20 * time.Millisecond)
+	go func() {
+		defer wg.Done()
+		if err := m.Delete(info.ID); err != nil {
+			t.Errorf("delete: %v", err)
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+	s.mu.Unlock()
+	wg.Wait()
+
+	if keys := storedKeys(t, store, info.ID); len(keys) != 0 {
+		t.Fatalf("keys left after delete: %v", keys)
+	}
+}
+
+func TestExpiredSessionIsForgotten(t *testing.T) {
+	store := storage.NewMemoryStore()
+	m := newTestManager(t, store)
+	info, err := m.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.mu.Lock()
+	m.sessions[info.ID].lastUsed = time.Now().Add(-2 * m.cfg.TTL)
+	m.mu.Unlock()
+
+	if _, err := m.Get(info.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get expired session: got %v, want ErrNotFound", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(storedKeys(t, store, info.ID)) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expired session still stored: %v", storedKeys(t, store, info.ID))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TTL короче минимального периода проверки не роняет менеджер, а
+// сессии всё равно истекают.
+func TestTinyTTL(t *testing.T) {
+	store := storage.NewMemoryStore()
+	m, err := NewManager(service.NewCalculatorService(service.WithLatency(0)), store, Config{TTL: time.Nanosecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	info, err := m.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(storedKeys(t, store, info.ID)) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("session did not expire")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,141 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"calculator/internal/service"
+	"calculator/internal/storage"
+)
+
+var (
+	ErrVariableNotFound = errors.New("variable not found in session")
+	ErrNotInput         = errors.New("variable is not an input")
+)
+
+// Change — изменение значения переменной после обновления входов.
+type Change struct {
+	Var string `json:"var"`
+	Old int64  `json:"old"`
+	New int64  `json:"new"`
+}
+
+// UpdateInputs присваивает входным переменным сессии новые значения и
+// пересчитывает только переменные, транзитивно от них зависящие.
+// Входной считается переменная, не ссылающаяся на другие переменные.
+// Возвращает переменные, значения которых действительно изменились.
+func (m *Manager) UpdateInputs(ctx context.Context, id string, inputs map[string]int64) ([]Change, error) {
+	s, err := m.acquire(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	before := s.env.Values()
+	for name := range inputs {
+		if _, ok := before[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrVariableNotFound, name)
+		}
+		if def, ok := s.defs[name]; ok && len(def.Dependencies()) > 0 {
+			return nil, fmt.Errorf("%w: %s depends on %v", ErrNotInput, name, def.Dependencies())
+		}
+	}
+
+	dependents := s.dependents(inputs)
+
+	// Окружение пересчёта: всё, кроме зависимых переменных, с новыми
+	// значениями входов.
+	values := make(map[string]int64, len(before))
+	for name, val := range before {
+		if dependents[name] {
+			continue
+		}
+		values[name] = val
+	}
+	for name, val := range inputs {
+		values[name] = val
+	}
+
+	program := make([]service.Instruction, 0, len(dependents))
+	for name := range dependents {
+		program = append(program, s.defs[name])
+	}
+
+	env := service.NewEnvironmentFrom(values)
+	if _, err := m.calc.Execute(ctx, env, program, nil); err != nil {
+		return nil, err
+	}
+	after := env.Values()
+
+	changes := make([]Change, 0)
+	ops := make([]storage.Op, 0)
+	for name, val := range after {
+		if old := before[name]; old != val {
+			changes = append(changes, Change{Var: name, Old: old, New: val})
+			op, err := putVariable(s.id, name, val)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, op)
+		}
+	}
+	defs := make([]service.Instruction, 0, len(inputs))
+	for name, val := range inputs {
+		def := inputDefinition(name, val)
+		op, err := putDefinition(s.id, def)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+		defs = append(defs, def)
+	}
+	if err := m.store.Apply(ops...); err != nil {
+		return nil, fmt.Errorf("persist session %s: %w", s.id, err)
+	}
+
+	s.env = env
+	for _, def := range defs {
+		s.defs[def.Var] = def
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Var < changes[j].Var })
+	return changes, nil
+}
+
+// dependents возвращает переменные, транзитивно зависящие от inputs.
+func (s *Session) dependents(inputs map[string]int64) map[string]bool {
+	reverse := make(map[string][]string)
+	for name, def := range s.defs {
+		for _, dep := range def.Dependencies() {
+			reverse[dep] = append(reverse[dep], name)
+		}
+	}
+
+	result := make(map[string]bool)
+	queue := make([]string, 0, len(inputs))
+	for name := range inputs {
+		queue = append(queue, name)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, dependent := range reverse[name] {
+			if result[dependent] {
+				continue
+			}
+			result[dependent] = true
+			queue = append(queue, dependent)
+		}
+	}
+	return result
+}
+
+// inputDefinition — определение входной переменной с заданным значением.
+func inputDefinition(name string, val int64) service.Instruction {
+	return service.Instruction{Type: "calc", Op: "+", Var: name, Left: val}
+}
@@ -0,0 +1,167 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"calculator/internal/service"
+	"calculator/internal/storage"
+)
+
+func TestUpdateInputs(t *testing.T) {
+	// price и qty — входы; total = price * qty, tax = total * 2,
+	// label = qty + 100 зависит только от qty.
+	program := []service.Instruction{
+		calc("price", int64(10), int64(0)),
+		calc("qty", int64(3), int64(0)),
+		{Type: "calc", Op: "*", Var: "total", Left: "price", Right: "qty"},
+		{Type: "calc", Op: "*", Var: "tax", Left: "total", Right: int64(2)},
+		calc("label", "qty", int64(100)),
+	}
+	tests := []struct {
+		name    string
+		inputs  map[string]int64
+		want    []Change
+		wantErr error
+		values  map[string]int64
+	}{
+		{
+			name:   "transitive dependents",
+			inputs: map[string]int64{"price": 20},
+			want: []Change{
+				{Var: "price", Old: 10, New: 20},
+				{Var: "tax", Old: 60, New: 120},
+				{Var: "total", Old: 30, New: 60},
+			},
+			values: map[string]int64{"price": 20, "qty": 3, "total": 60, "tax": 120, "label": 103},
+		},
+		{
+			name:   "independent branches",
+			inputs: map[string]int64{"qty": 1},
+			want: []Change{
+				{Var: "label", Old: 103, New: 101},
+				{Var: "qty", Old: 3, New: 1},
+				{Var: "tax", Old: 60, New: 20},
+				{Var: "total", Old: 30, New: 10},
+			},
+			values: map[string]int64{"price": 10, "qty": 1, "total": 10, "tax": 20, "label": 101},
+		},
+		{
+			name:   "unchanged value",
+			inputs: map[string]int64{"price": 10},
+			want:   []Change{},
+		},
+		{
+			name:    "unknown variable",
+			inputs:  map[string]int64{"discount": 1},
+			wantErr: ErrVariableNotFound,
+		},
+		{
+			name:    "computed variable",
+			inputs:  map[string]int64{"total": 1},
+			wantErr: ErrNotInput,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := storage.NewMemoryStore()
+			m := newTestManager(t, store)
+			info, err := m.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := m.Calculate(context.Background(), info.ID, program, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			changes, err := m.UpdateInputs(context.Background(), info.ID, tt.inputs)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(changes) != fmt.Sprint(tt.want) {
+				t.Fatalf("changes = %v, want %v", changes, tt.want)
+			}
+			if tt.values == nil {
+				return
+			}
+			checkValues(t, m, info.ID, tt.values)
+
+			// Пересчитанные значения переживают перезапуск.
+			restored := newTestManager(t, store)
+			checkValues(t, restored, info.ID, tt.values)
+		})
+	}
+}
+
+// Повторное обновление пересчитывает от новых значений, а не от исходной
+// программы.
+func TestUpdateInputsRepeated(t *testing.T) {
+	m := newTestManager(t, storage.NewMemoryStore())
+	info, err := m.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	program := []service.Instruction{
+		calc("a", int64(1), int64(0)),
+		calc("b", "a", int64(1)),
+		calc("c", "b", "a"),
+	}
+	if _, err := m.Calculate(context.Background(), info.ID, program, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []int64{5, 7, 7} {
+		if _, err := m.UpdateInputs(context.Background(), info.ID, map[string]int64{"a": a}); err != nil {
+			t.Fatal(err)
+		}
+		checkValues(t, m, info.ID, map[string]int64{"a": a, "b": a + 1, "c": 2*a + 1})
+	}
+}
+
+func checkValues(t *testing.T, m *Manager, id string, want map[string]int64) {
+	t.Helper()
+	items, err := m.Variables(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]int64, len(items))
+	for _, item := range items {
+		got[item.Var] = item.Value
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("variables = %v, want %v", got, want)
+	}
+}
+
+// Литералы больше 2^53 восстанавливаются после перезапуска без потери
+// точности и участвуют в пересчёте.
+func TestUpdateInputsAfterRestartKeepsLargeLiterals(t *testing.T) {
+	const big = int64(1<<53 + 1)
+	store := storage.NewMemoryStore()
+	m := newTestManager(t, store)
+	info, err := m.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	program := []service.Instruction{
+		calc("a", int64(1), int64(0)),
+		calc("b", "a", big),
+	}
+	if _, err := m.Calculate(context.Background(), info.ID, program, nil); err != nil {
+		t.Fatal(err)
+	}
+	m.Close()
+
+	restored := newTestManager(t, store)
+	if _, err := restored.UpdateInputs(context.Background(), info.ID, map[string]int64{"a": 2}); err != nil {
+		t.Fatal(err)
+	}
+	checkValues(t, restored, info.ID, map[string]int64{"a": 2, "b": big + 2})
+}
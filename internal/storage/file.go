@@ -0,0 +1,406 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	snapshotFile = "snapshot.json"
+	walFile      = "wal.log"
+
+	// Заголовок записи журнала: длина данных и их CRC32.
+	recordHeaderSize = 8
+	maxRecordSize    = 64 << 20
+)
+
+// ErrRecordTooLarge — пакет изменений не помещается в одну запись
+// журнала.
+var ErrRecordTooLarge = errors.New("write-ahead log record too large")
+
+type FileOptions struct {
+	// CompactEvery — число записей журнала, после которого он сжимается
+	// в снимок. Ноль отключает сжатие по размеру.
+	CompactEvery int
+	// CompactInterval — период фонового сжатия. Ноль отключает его.
+	CompactInterval time.Duration
+	// NoSync отключает fsync после каждой записи.
+	NoSync bool
+}
+
+func DefaultFileOptions() FileOptions {
+	return FileOptions{
+		CompactEvery:    1000,
+		CompactInterval: 5 * time.Minute,
+	}
+}
+
+// RecoveryReport описывает результат восстановления при открытии.
+type RecoveryReport struct {
+	SnapshotKeys int
+	Records      int
+	// Corruption не nil, если хвост журнала был повреждён или обрезан и
+	// отброшен.
+	Corruption *CorruptLogError
+}
+
+// FileStore хранит данные в каталоге: снимок состояния и журнал
+// предзаписи (WAL) с изменениями после снимка. Каждый вызов Apply
+// дописывает в журнал одну запись; периодически журнал сжимается в
+// новый снимок.
+type FileStore struct {
+	dir  string
+	opts FileOptions
+
+	mu   sync.RWMutex
+	data map[string][]byte
+	wal  *os.File
+	// walSize — длина журнала после последней целиком записанной записи.
+	walSize int64
+	// broken — журнал не удалось вернуть к walSize после неудачной
+	// записи; дальнейшие записи отклоняются.
+	broken   error
+	records  int
+	closed   bool
+	recovery RecoveryReport
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func OpenFileStore(dir string, opts FileOptions) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &FileStore{
+		dir:  dir,
+		opts: opts,
+		data: make(map[string][]byte),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if err := s.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+
+	wal, err := os.OpenFile(s.walPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s.wal = wal
+	fi, err := wal.Stat()
+	if err != nil {
+		wal.Close()
+		return nil, err
+	}
+	s.walSize = fi.Size()
+
+	if opts.CompactInterval > 0 {
+		go s.compactLoop()
+	} else {
+		close(s.done)
+	}
+	return s, nil
+}
+
+// Recovery возвращает отчёт о восстановлении состояния при открытии.
+func (s *FileStore) Recovery() RecoveryReport {
+	return s.recovery
+}
+
+func (s *FileStore) Get(key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, false, ErrClosed
+	}
+	val, ok := s.data[key]
+	return val, ok, nil
+}
+
+func (s *FileStore) List(prefix string) (map[string][]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+	out := make(map[string][]byte)
+	for key, val := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			out[key] = val
+		}
+	}
+	return out, nil
+}
+
+// Err сообщает, что хранилище закрыто или журнал стал непригоден для
+// записи.
+func (s *FileStore) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+	return s.broken
+}
+
+func (s *FileStore) Apply(ops ...Op) error {
+	if len(ops) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	if err := s.appendRecord(payload); err != nil {
+		return err
+	}
+	apply(s.data, ops)
+	s.records++
+
+	// Запись уже в журнале, поэтому ошибка сжатия не отменяет её: журнал
+	// сожмётся при следующей попытке.
+	if s.opts.CompactEvery > 0 && s.records >= s.opts.CompactEvery {
+		if err := s.compactLocked(); err != nil {
+			slog.Error("failed to compact storage", "data_dir", s.dir, "error", err)
+		}
+	}
+	return nil
+}
+
+// Compact записывает текущее состояние в снимок и очищает журнал.
+func (s *FileStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	return s.compactLocked()
+}
+
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.stop)
+	<-s.done
+	return s.wal.Close()
+}
+
+// appendRecord дописывает запись в журнал. Если запись не удалась
+// целиком, журнал обрезается до её начала, чтобы следующая запись не
+// оказалась после мусора, который восстановление отбросит вместе с ней.
+func (s *FileStore) appendRecord(payload []byte) error {
+	if s.broken != nil {
+		return s.broken
+	}
+	if len(payload) > maxRecordSize {
+		return fmt.Errorf("%w: %d bytes, limit %d", ErrRecordTooLarge, len(payload), maxRecordSize)
+	}
+	buf := make([]byte, recordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(payload))
+	copy(buf[recordHeaderSize:], payload)
+
+	if _, err := s.wal.Write(buf); err != nil {
+		return s.rollback(fmt.Errorf("write-ahead log append: %w", err))
+	}
+	if !s.opts.NoSync {
+		if err := s.wal.Sync(); err != nil {
+			return s.rollback(fmt.Errorf("write-ahead log sync: %w", err))
+		}
+	}
+	s.walSize += int64(len(buf))
+	return nil
+}
+
+// rollback отбрасывает недописанную запись. Если журнал не удаётся
+// обрезать, хранилище перестаёт принимать записи.
+func (s *FileStore) rollback(cause error) error {
+	if err := s.wal.Truncate(s.walSize); err != nil {
+		s.broken = fmt.Errorf("write-ahead log is unusable after failed append: %w", err)
+		slog.Error("failed to roll back write-ahead log", "data_dir", s.dir, "error", err)
+	}
+	return cause
+}
+
+func (s *FileStore) compactLocked() error {
+	payload, err := json.Marshal(s.data)
+	if err != nil {
+		return err
+	}
+	tmp := s.snapshotPath() + ".tmp"
+	if err := writeFileSync(tmp, payload); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.snapshotPath()); err != nil {
+		return err
+	}
+	// Без синхронизации каталога после сбоя может остаться старый снимок
+	// при уже очищенном журнале.
+	if err := syncDir(s.dir); err != nil {
+		return err
+	}
+	// Если процесс упадёт между переименованием и очисткой журнала, при
+	// восстановлении журнал применится к снимку повторно. Это безопасно:
+	// записи журнала идемпотентны.
+	if err := s.wal.Truncate(0); err != nil {
+		return err
+	}
+	s.walSize = 0
+	s.records = 0
+	return nil
+}
+
+func (s *FileStore) compactLoop() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.opts.CompactInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			if !s.closed && s.records > 0 {
+				if err := s.compactLocked(); err != nil {
+					slog.Error("failed to compact storage", "data_dir", s.dir, "error", err)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *FileStore) loadSnapshot() error {
+	payload, err := os.ReadFile(s.snapshotPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(payload, &s.data); err != nil {
+		return fmt.Errorf("read snapshot %s: %w", s.snapshotPath(), err)
+	}
+	// Снимок из литерала null оставил бы карту nil.
+	if s.data == nil {
+		return fmt.Errorf("read snapshot %s: not a JSON object", s.snapshotPath())
+	}
+	s.recovery.SnapshotKeys = len(s.data)
+	return nil
+}
+
+// replay применяет записи журнала к снимку. Повреждённый или обрезанный
+// хвост отбрасывается и попадает в отчёт о восстановлении.
+func (s *FileStore) replay() error {
+	f, err := os.OpenFile(s.walPath(), os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var offset int64
+	header := make([]byte, recordHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return s.truncateTail(f, offset, "truncated record header")
+		}
+		size := binary.BigEndian.Uint32(header[0:4])
+		sum := binary.BigEndian.Uint32(header[4:8])
+		if size > maxRecordSize {
+			return s.truncateTail(f, offset, fmt.Sprintf("record size %d exceeds limit", size))
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return s.truncateTail(f, offset, "truncated record payload")
+		}
+		if crc32.ChecksumIEEE(payload) != sum {
+			return s.truncateTail(f, offset, "checksum mismatch")
+		}
+		var ops []Op
+		if err := json.Unmarshal(payload, &ops); err != nil {
+			return s.truncateTail(f, offset, "malformed record: "+err.Error())
+		}
+		apply(s.data, ops)
+		s.records++
+		s.recovery.Records++
+		offset += int64(recordHeaderSize) + int64(size)
+	}
+}
+
+func (s *FileStore) truncateTail(f *os.File, offset int64, reason string) error {
+	s.recovery.Corruption = &CorruptLogError{Path: f.Name(), Offset: offset, Reason: reason}
+	if err := f.Truncate(offset); err != nil {
+		return fmt.Errorf("%v: truncate: %w", s.recovery.Corruption, err)
+	}
+	return nil
+}
+
+func (s *FileStore) snapshotPath() string {
+	return filepath.Join(s.dir, snapshotFile)
+}
+
+func (s *FileStore) walPath() string {
+	return filepath.Join(s.dir, walFile)
+}
+
+func writeFileSync(path string, payload []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(payload); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return err
+	}
+	return d.Close()
+}
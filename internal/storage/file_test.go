@@ -0,0 +1,248 @@
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func openTestStore(t *testing.T, dir string, opts FileOptions) *FileStore {
+	t.Helper()
+	s, err := OpenFileStore(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func mustApply(t *testing.T, s Store, ops ...Op) {
+	t.Helper()
+	if err := s.Apply(ops...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func wantValue(t *testing.T, s Store, key, want string) {
+	t.Helper()
+	got, ok, err := s.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want == "" {
+		if ok {
+			t.Fatalf("%s = %q, want missing", key, got)
+		}
+		return
+	}
+	if !ok || string(got) != want {
+		t.Fatalf("%s = %q (present %v), want %q", key, got, ok, want)
+	}
+}
+
+func TestFileStoreReplay(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestStore(t, dir, FileOptions{})
+	mustApply(t, s, Put("a", []byte("1")), Put("b", []byte("2")))
+	mustApply(t, s, Delete("a"), Put("c", []byte("3")))
+	s.Close()
+
+	s = openTestStore(t, dir, FileOptions{})
+	if r := s.Recovery(); r.Records != 2 || r.Corruption != nil {
+		t.Fatalf("recovery = %+v, want 2 records without corruption", r)
+	}
+	wantValue(t, s, "a", "")
+	wantValue(t, s, "b", "2")
+	wantValue(t, s, "c", "3")
+}
+
+// Повреждённый хвост журнала отбрасывается, записи до него сохраняются, а
+// новые записи дописываются после последней целой.
+func TestFileStoreTornTail(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(t *testing.T, path string, size int64)
+		reason string
+		// lost — повреждена вторая запись, а не добавлен мусор после неё.
+		lost bool
+	}{
+		{
+			name: "truncated header",
+			damage: func(t *testing.T, path string, size int64) {
+				appendBytes(t, path, []byte{0, 0, 0})
+			},
+			reason: "truncated record header",
+		},
+		{
+			name: "truncated payload",
+			damage: func(t *testing.T, path string, size int64) {
+				if err := os.Truncate(path, size-2); err != nil {
+					t.Fatal(err)
+				}
+			},
+			reason: "truncated record payload",
+			lost:   true,
+		},
+		{
+			name: "checksum mismatch",
+			damage: func(t *testing.T, path string, size int64) {
+				f, err := os.OpenFile(path, os.O_RDWR, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer f.Close()
+				if _, err := f.WriteAt([]byte("X"), size-2); err != nil {
+					t.Fatal(err)
+				}
+			},
+			reason: "checksum mismatch",
+			lost:   true,
+		},
+		{
+			name: "oversized record",
+			damage: func(t *testing.T, path string, size int64) {
+				header := make([]byte, recordHeaderSize)
+				binary.BigEndian.PutUint32(header, maxRecordSize+1)
+				appendBytes(t, path, header)
+			},
+			reason: "exceeds limit",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := openTestStore(t, dir, FileOptions{})
+			mustApply(t, s, Put("a", []byte("1")))
+			intact := fileSize(t, filepath.Join(dir, walFile))
+			mustApply(t, s, Put("b", []byte("2")))
+			s.Close()
+
+			path := filepath.Join(dir, walFile)
+			full := fileSize(t, path)
+			tt.damage(t, path, full)
+
+			s = openTestStore(t, dir, FileOptions{})
+			r := s.Recovery()
+			if r.Corruption == nil || !strings.Contains(r.Corruption.Reason, tt.reason) {
+				t.Fatalf("corruption = %v, want reason %q", r.Corruption, tt.reason)
+			}
+			wantOffset, wantB := full, "2"
+			if tt.lost {
+				wantOffset, wantB = intact, ""
+			}
+			if r.Corruption.Offset != wantOffset {
+				t.Fatalf("corruption offset = %d, want %d", r.Corruption.Offset, wantOffset)
+			}
+			wantValue(t, s, "a", "1")
+			wantValue(t, s, "b", wantB)
+
+			mustApply(t, s, Put("c", []byte("3")))
+			s.Close()
+			s = openTestStore(t, dir, FileOptions{})
+			if r := s.Recovery(); r.Corruption != nil {
+				t.Fatalf("corruption after rewrite: %v", r.Corruption)
+			}
+			wantValue(t, s, "c", "3")
+		})
+	}
+}
+
+func TestFileStoreRejectsOversizedRecord(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestStore(t, dir, FileOptions{NoSync: true})
+	err := s.Apply(Put("big", make([]byte, maxRecordSize)))
+	if !errors.Is(err, ErrRecordTooLarge) {
+		t.Fatalf("got %v, want ErrRecordTooLarge", err)
+	}
+	wantValue(t, s, "big", "")
+	mustApply(t, s, Put("a", []byte("1")))
+	s.Close()
+
+	s = openTestStore(t, dir, FileOptions{})
+	if r := s.Recovery(); r.Corruption != nil || r.Records != 1 {
+		t.Fatalf("recovery = %+v, want 1 record without corruption", r)
+	}
+	wantValue(t, s, "a", "1")
+}
+
+func TestFileStoreCompaction(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestStore(t, dir, FileOptions{CompactEvery: 3})
+	for _, key := range []string{"a", "b", "c", "d"} {
+		mustApply(t, s, Put(key, []byte(key)))
+	}
+	mustApply(t, s, Delete("a"))
+	s.Close()
+
+	s = openTestStore(t, dir, FileOptions{})
+	if r := s.Recovery(); r.SnapshotKeys != 3 || r.Records != 2 {
+		t.Fatalf("recovery = %+v, want 3 snapshot keys and 2 records", r)
+	}
+	wantValue(t, s, "a", "")
+	wantValue(t, s, "d", "d")
+
+	if err := s.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if size := fileSize(t, filepath.Join(dir, walFile)); size != 0 {
+		t.Fatalf("log size after compaction = %d", size)
+	}
+}
+
+func TestFileStoreRejectsNullSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, snapshotFile), []byte("null"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := OpenFileStore(dir, FileOptions{})
+	if err == nil {
+		s.Close()
+		t.Fatal("OpenFileStore with a null snapshot succeeded")
+	}
+}
+
+// Непригодный журнал и закрытие хранилища видны через Err.
+func TestFileStoreErr(t *testing.T) {
+	s := openTestStore(t, t.TempDir(), FileOptions{NoSync: true})
+	if err := s.Err(); err != nil {
+		t.Fatalf("Err = %v, want nil", err)
+	}
+	broken := errors.New("write-ahead log is unusable")
+	s.mu.Lock()
+	s.broken = broken
+	s.mu.Unlock()
+	if err := s.Err(); !errors.Is(err, broken) {
+		t.Fatalf("Err = %v, want %v", err, broken)
+	}
+	if err := s.Apply(Put("a", []byte("1"))); !errors.Is(err, broken) {
+		t.Fatalf("Apply = %v, want %v", err, broken)
+	}
+	s.Close()
+	if err := s.Err(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Err after Close = %v, want ErrClosed", err)
+	}
+}
+
+func appendBytes(t *testing.T, path string, data []byte) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fi.Size()
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"strings"
+	"sync"
+)
+
+// MemoryStore хранит данные только в памяти процесса.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	data   map[string][]byte
+	closed bool
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: make(map[string][]byte)}
+}
+
+func (s *MemoryStore) Get(key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, false, ErrClosed
+	}
+	val, ok := s.data[key]
+	return val, ok, nil
+}
+
+func (s *MemoryStore) List(prefix string) (map[string][]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+	out := make(map[string][]byte)
+	for key, val := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			out[key] = val
+		}
+	}
+	return out, nil
+}
+
+func (s *MemoryStore) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+func (s *MemoryStore) Apply(ops ...Op) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	apply(s.data, ops)
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrClosed = errors.New("storage closed")
+
+// Store — хранилище ключ-значение. Изменения применяются пакетами через
+// Apply: пакет либо записывается целиком, либо не записывается вовсе.
+type Store interface {
+	Get(key string) ([]byte, bool, error)
+	// List возвращает все записи, ключи которых начинаются с prefix.
+	List(prefix string) (map[string][]byte, error)
+	Apply(ops ...Op) error
+	// Err возвращает причину, по которой хранилище не принимает записи,
+	// или nil, если оно исправно.
+	Err() error
+	Close() error
+}
+
+// Op — одно изменение: запись значения или удаление ключа.
+type Op struct {
+	Key    string `json:"key"`
+	Value  []byte `json:"value,omitempty"`
+	Delete bool   `json:"delete,omitempty"`
+}
+
+func Put(key string, value []byte) Op {
+	return Op{Key: key, Value: value}
+}
+
+func Delete(key string) Op {
+	return Op{Key: key, Delete: true}
+}
+
+// CorruptLogError описывает повреждённый или обрезанный хвост журнала,
+// обнаруженный при восстановлении.
+type CorruptLogError struct {
+	Path   string
+	Offset int64
+	Reason string
+}
+
+func (e *CorruptLogError) Error() string {
+	return fmt.Sprintf("corrupt write-ahead log %s at offset %d: %s", e.Path, e.Offset, e.Reason)
+}
+
+func apply(data map[string][]byte, ops []Op) {
+	for _, op := range ops {
+		if op.Delete {
+			delete(data, op.Key)
+			continue
+		}
+		data[op.Key] = op.Value
+	}
+}
@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// SpanData — завершённый спан в виде, удобном для экспорта.
+type SpanData struct {
+	TraceID    string         `json:"trace_id"`
+	SpanID     string         `json:"span_id"`
+	ParentID   string         `json:"parent_id,omitempty"`
+	Name       string         `json:"name"`
+	Start      time.Time      `json:"start"`
+	End        time.Time      `json:"end"`
+	Duration   string         `json:"duration"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// Exporter получает завершённые спаны. Export вызывается из разных
+// горутин одновременно и не должен надолго блокироваться.
+type Exporter interface {
+	Export(span SpanData)
+	Close() error
+}
+
+// NopExporter отбрасывает спаны.
+type NopExporter struct{}
+
+func (NopExporter) Export(SpanData) {}
+func (NopExporter) Close() error    { return nil }
+
+// WriterExporter пишет спаны в w построчно в формате JSON.
+type WriterExporter struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+}
+
+// NewWriterExporter пишет спаны в w, например в os.Stdout.
+func NewWriterExporter(w io.Writer) *WriterExporter {
+	return &WriterExporter{enc: json.NewEncoder(w)}
+}
+
+// NewFileExporter дописывает спаны в файл path.
+func NewFileExporter(path string) (*WriterExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &WriterExporter{enc: json.NewEncoder(f), closer: f}, nil
+}
+
+func (e *WriterExporter) Export(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	// Ошибка записи не должна влиять на обработку запросов.
+	e.enc.Encode(span)
+}
+
+func (e *WriterExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
@@ -0,0 +1,70 @@
+package tracing
+
+import "testing"
+
+func TestParseTraceparent(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	tests := []struct {
+		name    string
+		header  string
+		sampled bool
+		wantErr bool
+	}{
+		{name: "sampled", header: "00-" + traceID + "-" + spanID + "-01", sampled: true},
+		{name: "not sampled", header: "00-" + traceID + "-" + spanID + "-00"},
+		{name: "other flag bits", header: "00-" + traceID + "-" + spanID + "-03", sampled: true},
+		{name: "surrounding spaces", header: " 00-" + traceID + "-" + spanID + "-01 ", sampled: true},
+		{name: "future version with extra field", header: "01-" + traceID + "-" + spanID + "-01-extra", sampled: true},
+
+		{name: "empty", header: "", wantErr: true},
+		{name: "too few fields", header: "00-" + traceID + "-" + spanID, wantErr: true},
+		{name: "version 00 with extra field", header: "00-" + traceID + "-" + spanID + "-01-extra", wantErr: true},
+		{name: "version ff", header: "ff-" + traceID + "-" + spanID + "-01", wantErr: true},
+		{name: "uppercase version", header: "0A-" + traceID + "-" + spanID + "-01", wantErr: true},
+		{name: "uppercase trace id", header: "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + spanID + "-01", wantErr: true},
+		{name: "uppercase span id", header: "00-" + traceID + "-00F067AA0BA902B7-01", wantErr: true},
+		{name: "uppercase flags", header: "00-" + traceID + "-" + spanID + "-0A", wantErr: true},
+		{name: "short trace id", header: "00-" + traceID[1:] + "-" + spanID + "-01", wantErr: true},
+		{name: "long span id", header: "00-" + traceID + "-" + spanID + "0-01", wantErr: true},
+		{name: "non-hex trace id", header: "00-" + traceID[:31] + "g-" + spanID + "-01", wantErr: true},
+		{name: "non-hex flags", header: "00-" + traceID + "-" + spanID + "-0x", wantErr: true},
+		{name: "zero trace id", header: "00-00000000000000000000000000000000-" + spanID + "-01", wantErr: true},
+		{name: "zero span id", header: "00-" + traceID + "-0000000000000000-01", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := ParseTraceparent(tt.header)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseTraceparent(%q) = %+v, want error", tt.header, sc)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sc.TraceID.String() != traceID || sc.SpanID.String() != spanID || sc.Sampled != tt.sampled {
+				t.Fatalf("ParseTraceparent(%q) = %s/%s sampled=%v", tt.header, sc.TraceID, sc.SpanID, sc.Sampled)
+			}
+		})
+	}
+}
+
+// Разобранный контекст форматируется обратно в тот же заголовок.
+func TestTraceparentRoundTrip(t *testing.T) {
+	for _, header := range []string{
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+	} {
+		sc, err := ParseTraceparent(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sc.Traceparent(); got != header {
+			t.Fatalf("Traceparent() = %q, want %q", got, header)
+		}
+	}
+}